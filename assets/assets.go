@@ -0,0 +1,20 @@
+// Package assets ships the templates the generator renders.
+package assets
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed templates
+var files embed.FS
+
+// Templates returns the built-in generator templates, rooted at the
+// templates dir.
+func Templates() fs.FS {
+	sub, err := fs.Sub(files, "templates")
+	if err != nil {
+		panic(err) // a constant, valid path
+	}
+	return sub
+}
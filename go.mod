@@ -2,4 +2,8 @@ module github.com/aquamarinepk/aquamarine
 
 go 1.22.7
 
-require gopkg.in/yaml.v3 v3.0.1
+require (
+	github.com/go-chi/chi/v5 v5.1.0
+	github.com/google/uuid v1.6.0
+	gopkg.in/yaml.v3 v3.0.1
+)
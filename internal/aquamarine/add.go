@@ -0,0 +1,142 @@
+package aquamarine
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+)
+
+// AddOptions is shared by the add commands.
+type AddOptions struct {
+	SpecFile   string   // defaults to DefaultSpecFile
+	OutputDir  string   // defaults to out/<mode>
+	Templates  string   // template dir layered over the spec's templates
+	Profile    string   // spec profile of the regenerate step
+	NoGenerate bool     // only edit the spec
+	NoHooks    bool     // skip the spec's hooks when regenerating
+	Plugins    []Plugin // contribute files when regenerating
+}
+
+// AddFeature appends a feat to the spec and generates it.
+func AddFeature(templates fs.FS, opts AddOptions, name, kind string, models []string) error {
+	if !slices.Contains(allowedKinds, kind) {
+		return fmt.Errorf("invalid kind %q (want one of %s)", kind, strings.Join(allowedKinds, ", "))
+	}
+	return editSpec(templates, opts, name, func(e *SpecEditor) (string, error) {
+		return fmt.Sprintf("added feat %s", name), e.AddFeature(name, kind, models)
+	})
+}
+
+// AddModel adds a model, or fields to an existing model, and regenerates its
+// feat.
+func AddModel(templates fs.FS, opts AddOptions, feat, model string, fields []FieldArg) error {
+	return editSpec(templates, opts, feat, func(e *SpecEditor) (string, error) {
+		return fmt.Sprintf("added model %s.%s", feat, model), e.AddModel(feat, model, fields)
+	})
+}
+
+// AddEndpoint adds an API route and regenerates its feat. handler defaults to
+// a name derived from method and path (GET /me: GetMe). Path parameters may
+// be written :id, as in other routers; the spec gets them as {id}.
+func AddEndpoint(templates fs.FS, opts AddOptions, feat, method, path, handler string) error {
+	method = strings.ToUpper(method)
+	if !slices.Contains(allowedMethods, method) {
+		return fmt.Errorf("invalid HTTP method %q (want one of %s)", method, strings.Join(allowedMethods, ", "))
+	}
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("invalid path %q (must start with /)", path)
+	}
+	path = routePath(path)
+	if handler == "" {
+		handler = handlerName(method, path)
+	}
+	return editSpec(templates, opts, feat, func(e *SpecEditor) (string, error) {
+		return fmt.Sprintf("added endpoint %s %s -> %s.%s", method, path, feat, handler), e.AddEndpoint(feat, method, path, handler)
+	})
+}
+
+// editSpec applies edit to the spec, validates the result and saves it, then
+// regenerates feat only. An invalid result leaves the spec untouched.
+func editSpec(templates fs.FS, opts AddOptions, feat string, edit func(*SpecEditor) (string, error)) error {
+	specFile := opts.SpecFile
+	if specFile == "" {
+		specFile = DefaultSpecFile
+	}
+	e, err := LoadSpecEditor(featFile(specFile, feat))
+	if err != nil {
+		return err
+	}
+	e.Spec = specFile
+	summary, err := edit(e)
+	if err != nil {
+		return err
+	}
+	if diags := e.Check(); diags.HasErrors() {
+		if err := WriteReport(os.Stdout, FormatText, specFile, diags); err != nil {
+			return err
+		}
+		return fmt.Errorf("%s: edit rejected, spec left unchanged", specFile)
+	}
+	if err := e.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("Updated %s: %s\n", e.File, summary)
+
+	if opts.NoGenerate {
+		return nil
+	}
+	return Generate(templates, GenerateOptions{
+		Profile: opts.Profile, SpecFile: specFile, OutputDir: opts.OutputDir, Templates: opts.Templates,
+		Feats: []string{feat}, NoHooks: opts.NoHooks, Plugins: opts.Plugins,
+	})
+}
+
+// featFile returns the file declaring feat: an included feat file, or the
+// spec itself for its own feats and new ones.
+func featFile(specFile, feat string) string {
+	cfg, _ := readSpec(specFile, specOptions{optionalEnv: true})
+	if cfg != nil {
+		if p, ok := cfg.Source[join("feats", feat)]; ok && p.File != "" {
+			return p.File
+		}
+	}
+	return specFile
+}
+
+var handlerVerbs = map[string]string{
+	"GET":    "Get",
+	"POST":   "Create",
+	"PUT":    "Update",
+	"PATCH":  "Update",
+	"DELETE": "Delete",
+}
+
+// routePath rewrites the :name path parameters of path as chi's {name}.
+func routePath(path string) string {
+	segs := strings.Split(path, "/")
+	for i, seg := range segs {
+		if name, ok := strings.CutPrefix(seg, ":"); ok && name != "" {
+			segs[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segs, "/")
+}
+
+// handlerName derives a service method name from a route, skipping path
+// parameters: GET /me is GetMe, POST /orders/{id}/items (or /orders/:id/items)
+// is CreateOrdersItems.
+func handlerName(method, path string) string {
+	name := handlerVerbs[method]
+	if name == "" {
+		name = capitalizeFirst(strings.ToLower(method))
+	}
+	for _, seg := range strings.Split(path, "/") {
+		if seg == "" || strings.HasPrefix(seg, "{") || strings.HasPrefix(seg, ":") {
+			continue
+		}
+		name += pascal(seg)
+	}
+	return name
+}
@@ -0,0 +1,59 @@
+package aquamarine
+
+import "cmp"
+
+// defaultVersionField is the version field of aggregates that name none.
+const defaultVersionField = "version"
+
+// childCollection is a child collection of an aggregate root: the Of values
+// stored, loaded and version-checked with the Root.
+type childCollection struct {
+	Root, Name, Of string
+}
+
+// table names the table holding the collection: order_items.
+func (c childCollection) table() string {
+	return toSnakeCase(c.Root) + "_" + toSnakeCase(c.Name)
+}
+
+// rootColumn names the column of table holding the root's id.
+func (c childCollection) rootColumn() string {
+	return toSnakeCase(c.Root) + "_id"
+}
+
+// featChildren returns the child collections of the aggregates of feat,
+// sorted by root and name. Collections CheckSpec rejects, of a root or child
+// that is not a model or of a root held by a collection, are left out.
+func featChildren(feat Feature) []childCollection {
+	var children []childCollection
+	for _, root := range sortedKeys(feat.Aggregates) {
+		if _, ok := feat.Models[root]; !ok {
+			continue
+		}
+		agg := feat.Aggregates[root]
+		for _, name := range sortedKeys(agg.Children) {
+			of := agg.Children[name].Of
+			_, isModel := feat.Models[of]
+			_, isRoot := feat.Aggregates[of]
+			if isModel && !isRoot {
+				children = append(children, childCollection{Root: root, Name: name, Of: of})
+			}
+		}
+	}
+	return children
+}
+
+// childModels returns the models of feat held in child collections. They
+// are persisted with their roots and get no repo of their own.
+func childModels(feat Feature) map[string]bool {
+	models := map[string]bool{}
+	for _, c := range featChildren(feat) {
+		models[c.Of] = true
+	}
+	return models
+}
+
+// versionField is the name of the field counting the saves of a.
+func versionField(a Aggregate) string {
+	return cmp.Or(a.VersionField, defaultVersionField)
+}
@@ -1,19 +1,25 @@
 package aquamarine
 
 import (
-	"embed"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
 )
 
 // App is the CLI entrypoint for the Aquamarine generator.
 type App struct {
-	assets embed.FS
+	templates fs.FS
+	plugins   []Plugin
 }
 
-func NewApp(assetsFS embed.FS) *App {
-	return &App{assets: assetsFS}
+// NewApp returns the CLI rendering templates. Plugins contribute files to
+// every generate run, including the one after an add.
+func NewApp(templates fs.FS, plugins ...Plugin) *App {
+	return &App{templates: templates, plugins: plugins}
 }
 
 // Run parses CLI args and dispatches to subcommands.
@@ -25,20 +31,22 @@ func (a *App) Run(args []string) error {
 	}
 	sub := args[1]
 	switch sub {
+	case "new":
+		return a.newProject(args[2:])
+	case "add":
+		return a.add(args[2:])
 	case "generate":
-		fs := flag.NewFlagSet("generate", flag.ContinueOnError)
-		dev := fs.Bool("dev", false, "generate into development output directory")
-		if err := fs.Parse(args[2:]); err != nil {
-			return err
-		}
-		mode := "prod"
-		if *dev {
-			mode = "dev"
-		}
-		if err := Generate(a.assets, mode); err != nil {
-			return err
-		}
-		return nil
+		return a.generate(args[2:])
+	case "validate":
+		return a.validate(args[2:])
+	case "diff":
+		return a.diff(args[2:])
+	case "schema":
+		return a.schema(args[2:])
+	case "upgrade":
+		return a.upgrade(args[2:])
+	case "graph":
+		return a.graph(args[2:])
 	case "help", "-h", "--help":
 		usage()
 		return nil
@@ -48,9 +56,275 @@ func (a *App) Run(args []string) error {
 	}
 }
 
+func (a *App) newProject(args []string) error {
+	fs := flag.NewFlagSet("new", flag.ContinueOnError)
+	module := fs.String("module", "", "Go module path (default: project name)")
+	skipGoMod := fs.Bool("skip-go-mod", false, "do not write go.mod (project inside an existing module)")
+	dev := fs.Bool("dev", false, "build against the local aquamarine checkout instead of a release")
+	pos, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 1 {
+		return fmt.Errorf("unexpected arguments: %v", pos[1:])
+	}
+	var dir string
+	if len(pos) == 1 {
+		dir = pos[0]
+	}
+	return NewProject(a.templates, NewOptions{Dir: dir, Module: *module, SkipGoMod: *skipGoMod, Dev: *dev})
+}
+
+func (a *App) add(args []string) error {
+	if len(args) == 0 {
+		return errors.New("add: want feature, model or endpoint")
+	}
+	fs := flag.NewFlagSet("add "+args[0], flag.ContinueOnError)
+	spec := fs.String("f", DefaultSpecFile, "spec file to edit")
+	out := fs.String("o", "", "output directory (default out/<mode>)")
+	tmpl := fs.String("templates", "", "template dir layered over the built-in templates")
+	profile := profileFlags(fs)
+	noGenerate := fs.Bool("no-generate", false, "only edit the spec")
+	noHooks := fs.Bool("no-hooks", false, "skip the spec's hooks")
+	opts := func() (AddOptions, error) {
+		p, err := profile()
+		return AddOptions{
+			SpecFile: *spec, OutputDir: *out, Templates: *tmpl, Profile: p,
+			NoGenerate: *noGenerate, NoHooks: *noHooks, Plugins: a.plugins,
+		}, err
+	}
+
+	switch args[0] {
+	case "feature":
+		kind := fs.String("kind", "domain", "feat kind: atom|domain|web")
+		models := fs.String("models", "", "comma-separated model names")
+		pos, err := parseArgs(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		if len(pos) != 1 {
+			return errors.New("usage: aquamarine add feature <name> [--kind atom|domain|web] [--models A,B]")
+		}
+		var names []string
+		if *models != "" {
+			names = strings.Split(*models, ",")
+		}
+		o, err := opts()
+		if err != nil {
+			return err
+		}
+		return AddFeature(a.templates, o, pos[0], *kind, names)
+
+	case "model":
+		pos, err := parseArgs(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		if len(pos) < 2 {
+			return errors.New("usage: aquamarine add model <feat> <Model> [name:type[:validations]...]")
+		}
+		var fields []FieldArg
+		for _, arg := range pos[2:] {
+			f, err := ParseFieldArg(arg)
+			if err != nil {
+				return err
+			}
+			fields = append(fields, f)
+		}
+		o, err := opts()
+		if err != nil {
+			return err
+		}
+		return AddModel(a.templates, o, pos[0], pos[1], fields)
+
+	case "endpoint":
+		methodName := fs.String("method-name", "", "service method handling the route (default: derived from method and path)")
+		pos, err := parseArgs(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		if len(pos) != 3 {
+			return errors.New("usage: aquamarine add endpoint <feat> <METHOD> <path> [--method-name Name]")
+		}
+		o, err := opts()
+		if err != nil {
+			return err
+		}
+		return AddEndpoint(a.templates, o, pos[0], pos[1], pos[2], *methodName)
+
+	default:
+		return fmt.Errorf("add: unknown %q, want feature, model or endpoint", args[0])
+	}
+}
+
+// parseArgs parses flags placed anywhere among the positional arguments,
+// which it returns.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var pos []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return pos, nil
+		}
+		pos = append(pos, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func (a *App) generate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	spec := fs.String("f", DefaultSpecFile, "spec file to generate from")
+	out := fs.String("o", "", "output directory (default out/<mode>)")
+	tmpl := fs.String("templates", "", "template dir layered over the built-in templates")
+	profile := profileFlags(fs)
+	dryRun := fs.Bool("dry-run", false, "print planned changes without writing")
+	force := fs.Bool("force", false, "overwrite files that conflict with hand edits")
+	noHooks := fs.Bool("no-hooks", false, "skip the spec's hooks")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	p, err := profile()
+	if err != nil {
+		return err
+	}
+	return Generate(a.templates, GenerateOptions{
+		Profile: p, SpecFile: *spec, OutputDir: *out, Templates: *tmpl,
+		DryRun: *dryRun, Force: *force, NoHooks: *noHooks, Plugins: a.plugins,
+	})
+}
+
+func (a *App) diff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	spec := fs.String("f", DefaultSpecFile, "spec file to generate from")
+	out := fs.String("o", "", "output directory (default out/<mode>)")
+	tmpl := fs.String("templates", "", "template dir layered over the built-in templates")
+	profile := profileFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	p, err := profile()
+	if err != nil {
+		return err
+	}
+	opts := GenerateOptions{Profile: p, SpecFile: *spec, OutputDir: *out, Templates: *tmpl, Plugins: a.plugins}
+	warnings, err := Diff(a.templates, os.Stdout, opts)
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+	return err
+}
+
+// profileFlags defines --profile and its --dev shorthand on fs. The returned
+// func gives the selected profile once fs is parsed, empty for the default.
+func profileFlags(fs *flag.FlagSet) func() (string, error) {
+	profile := fs.String("profile", "", "spec profile: dev, prod (default) or one declared under profiles")
+	dev := fs.Bool("dev", false, "shorthand for --profile dev")
+	return func() (string, error) {
+		switch {
+		case !*dev:
+			return *profile, nil
+		case *profile != "" && *profile != ProfileDev:
+			return "", fmt.Errorf("--dev selects the dev profile, not %q", *profile)
+		default:
+			return ProfileDev, nil
+		}
+	}
+}
+
+func outputMode(dev bool) string {
+	if dev {
+		return "dev"
+	}
+	return "prod"
+}
+
+func (a *App) validate(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	spec := fs.String("f", DefaultSpecFile, "spec file to validate")
+	format := fs.String("format", FormatText, "output format: text|json|sarif")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	diags := ValidateSpec(*spec)
+	if err := WriteReport(os.Stdout, *format, *spec, diags); err != nil {
+		return err
+	}
+	if diags.HasErrors() {
+		return &ExitError{Code: 1}
+	}
+	return nil
+}
+
+func (a *App) schema(args []string) error {
+	fs := flag.NewFlagSet("schema", flag.ContinueOnError)
+	out := fs.String("o", "", "write the schema to a file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	return writeTo(*out, WriteSchema)
+}
+
+func (a *App) graph(args []string) error {
+	fs := flag.NewFlagSet("graph", flag.ContinueOnError)
+	spec := fs.String("f", DefaultSpecFile, "spec file to draw")
+	format := fs.String("format", FormatDOT, "output format: dot|mermaid|markdown")
+	er := fs.Bool("er", false, "draw models as entities and their relationships")
+	out := fs.String("o", "", "write the graph to a file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := inspectSpec(*spec)
+	if err != nil {
+		return err
+	}
+	return writeTo(*out, func(w io.Writer) error {
+		return WriteGraph(w, cfg, GraphOptions{Format: *format, ER: *er})
+	})
+}
+
+// writeTo calls write with stdout, or with the file at path when given.
+func writeTo(path string, write func(io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (a *App) upgrade(args []string) error {
+	fs := flag.NewFlagSet("upgrade", flag.ContinueOnError)
+	spec := fs.String("f", DefaultSpecFile, "spec file to upgrade")
+	dryRun := fs.Bool("dry-run", false, "print the diff without writing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	return Upgrade(os.Stdout, UpgradeOptions{SpecFile: *spec, DryRun: *dryRun})
+}
+
+// addFlags are the flags every add command takes.
+const addFlags = "[-f spec] [-o dir] [--templates dir] [--profile name|--dev] [--no-generate] [--no-hooks]"
+
 func usage() {
 	fmt.Println("Aquamarine generator")
 	fmt.Println("Usage:")
-	fmt.Println("  aquamarine generate [--dev]")
+	fmt.Println("  aquamarine new [dir] [--module path] [--skip-go-mod] [--dev]")
+	fmt.Println("  aquamarine add feature <name> [--kind atom|domain|web] [--models A,B] " + addFlags)
+	fmt.Println("  aquamarine add model <feat> <Model> [name:type[:validations]...] " + addFlags)
+	fmt.Println("  aquamarine add endpoint <feat> <METHOD> <path> [--method-name Name] " + addFlags)
+	fmt.Println("  aquamarine generate [-f spec] [-o dir] [--templates dir] [--profile name|--dev] [--dry-run] [--force] [--no-hooks]")
+	fmt.Println("  aquamarine diff [-f spec] [-o dir] [--templates dir] [--profile name|--dev]")
+	fmt.Println("  aquamarine validate [-f aquamarine.yaml] [--format text|json|sarif]")
+	fmt.Println("  aquamarine schema [-o aquamarine.schema.json]")
+	fmt.Println("  aquamarine upgrade [-f spec] [--dry-run]")
+	fmt.Println("  aquamarine graph [-f spec] [--format dot|mermaid|markdown] [--er] [-o file]")
 	fmt.Println("  aquamarine help")
 }
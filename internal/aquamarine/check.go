@@ -0,0 +1,302 @@
+package aquamarine
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ValidateSpec loads the spec at path, with its includes, and runs the cross-reference checks on
+// top of schema validation. Nothing is written, so an unset ${VAR} is only a warning.
+func ValidateSpec(path string) Diagnostics {
+	cfg, diags := readSpec(path, specOptions{optionalEnv: true})
+	if cfg == nil {
+		return diags
+	}
+	diags = append(diags, CheckSpec(cfg)...)
+	sortDiagnostics(diags)
+	return diags
+}
+
+// CheckSpec verifies references between parts of an already decoded spec:
+// route handlers against service methods, page uses against feats,
+// aggregates, relations and refs against models, and ordering edges and feat
+// requires against feats and for cycles.
+func CheckSpec(cfg *Config) Diagnostics {
+	c := &specChecker{cfg: cfg}
+	for _, name := range sortedKeys(cfg.Feats) {
+		f := cfg.Feats[name]
+		fp := join("feats", name)
+		c.checkHandlers(f, fp)
+		c.checkPages(f, fp)
+		c.checkChildren(f, fp)
+		c.checkRelations(f, fp)
+		c.checkRefs(name, f)
+		c.checkRequires(f, fp)
+	}
+	c.checkOrdering()
+	return c.diags
+}
+
+type specChecker struct {
+	diagnosticList
+	cfg *Config
+}
+
+func (c *specChecker) report(rule, path, format string, args ...any) {
+	c.rule = rule
+	pos, _ := c.cfg.Source.nearest(path)
+	c.errorf(pos, path, format, args...)
+}
+
+func (c *specChecker) checkHandlers(f Feature, fp string) {
+	if len(f.Service.Methods) == 0 {
+		return
+	}
+	methods := map[string]bool{}
+	for _, m := range f.Service.Methods {
+		methods[m] = true
+	}
+	for i, r := range f.API.Routes {
+		if r.Handler != "" && !methods[r.Handler] {
+			p := fmt.Sprintf("%s.api.routes[%d].handler", fp, i)
+			c.report(RuleHandler, p, "handler %q is not listed in service.methods", r.Handler)
+		}
+	}
+}
+
+func (c *specChecker) checkPages(f Feature, fp string) {
+	for i, page := range f.Web.Pages {
+		for j, use := range page.Uses {
+			p := fmt.Sprintf("%s.web.pages[%d].uses[%d]", fp, i, j)
+			switch {
+			case use == f.Name:
+				c.report(RuleUnknownFeat, p, "page uses its own feat %q", use)
+			case !c.hasFeat(use):
+				c.report(RuleUnknownFeat, p, "page uses unknown feat %q", use)
+			}
+		}
+	}
+}
+
+func (c *specChecker) checkChildren(f Feature, fp string) {
+	tables := map[string]string{}
+	for _, model := range sortedKeys(f.Models) {
+		tables[toSnakeCase(pluralize(model))] = "the table of model " + model
+	}
+	fks, joins := featLinks(c.cfg.Source, fp, f)
+	for _, jt := range joins {
+		tables[jt.table()] = "the join table of relation " + jt.Owner + "." + jt.Name
+	}
+	keys := map[string]bool{}
+	for _, fk := range fks {
+		keys[fk.Model+"."+toSnakeCase(fk.field())] = true
+	}
+	for _, agg := range sortedKeys(f.Aggregates) {
+		a := f.Aggregates[agg]
+		ap := fmt.Sprintf("%s.aggregates.%s", fp, agg)
+		root, isModel := f.Models[agg]
+		if !isModel {
+			c.report(RuleUnknownModel, ap, "aggregate %q is not a model of the feat", agg)
+		}
+		for _, field := range sortedKeys(a.Fields) {
+			if hasField(root, field) {
+				c.report(RuleAggregate, ap+".fields."+field, "field %q is already a field of model %s", field, agg)
+			}
+		}
+		if v := versionField(a); hasField(root, v) || hasField(Model{Fields: a.Fields}, v) {
+			p := ap + ".version_field"
+			if a.VersionField == "" {
+				p = ap
+			}
+			c.report(RuleAggregate, p, "version field %q clashes with a field of %s: set version_field to another name", v, agg)
+		}
+		for _, child := range sortedKeys(a.Children) {
+			of := a.Children[child].Of
+			p := fmt.Sprintf("%s.children.%s", ap, child)
+			cc := childCollection{Root: agg, Name: child, Of: of}
+			_, known := f.Models[of]
+			_, isRoot := f.Aggregates[of]
+			switch {
+			case of != "" && !known:
+				c.report(RuleUnknownModel, p+".of", "child collection %q refers to unknown model %q", child, of)
+			case isRoot:
+				c.report(RuleAggregate, p+".of", "child collection %q holds %s, an aggregate root", child, of)
+			case singularize(child) == child:
+				c.report(RuleAggregate, p, "child collection name %q must be plural, as it names a list", child)
+			case hasField(root, child) || hasField(root, singularize(child)):
+				c.report(RuleAggregate, p, "child collection %q clashes with a field of %s", child, agg)
+			case tables[cc.table()] != "":
+				c.report(RuleAggregate, p, "table %s of child collection %q is %s", cc.table(), child, tables[cc.table()])
+			case hasField(f.Models[of], cc.rootColumn()) || keys[of+"."+cc.rootColumn()]:
+				c.report(RuleAggregate, p, "%s already has %s, the column of table %s holding the %s id", of, cc.rootColumn(), cc.table(), agg)
+			}
+			tables[cc.table()] = "the table of child collection " + agg + "." + child
+		}
+	}
+
+	// Children live in their roots' tables or documents: nothing else can
+	// point at them, and they can only point out.
+	children := childModels(f)
+	for _, model := range sortedKeys(f.Models) {
+		rels := f.Models[model].Relations
+		for _, name := range sortedKeys(rels) {
+			r := rels[name]
+			rp := fmt.Sprintf("%s.models.%s.relations.%s", fp, model, name)
+			switch {
+			case children[r.Model]:
+				c.report(RuleAggregate, rp, "relation %q refers to %s, which lives in a child collection", name, r.Model)
+			case children[model] && r.Type != "belongs_to":
+				c.report(RuleAggregate, rp, "%s lives in a child collection: it can only have belongs_to relations", model)
+			}
+		}
+	}
+}
+
+func (c *specChecker) checkRelations(f Feature, fp string) {
+	tables := map[string]string{}
+	for _, model := range sortedKeys(f.Models) {
+		tables[toSnakeCase(pluralize(model))] = model
+	}
+	for _, model := range sortedKeys(f.Models) {
+		rels := f.Models[model].Relations
+		for _, name := range sortedKeys(rels) {
+			r := rels[name]
+			rp := fmt.Sprintf("%s.models.%s.relations.%s", fp, model, name)
+			target, ok := f.Models[r.Model]
+			if !ok {
+				if r.Model != "" {
+					c.report(RuleUnknownModel, rp+".model", "relation %q refers to unknown model %q", name, r.Model)
+				}
+				continue
+			}
+			switch r.Type {
+			case "has_many":
+				back := belongsTo(target, model)
+				switch {
+				case r.Via != "" && !slices.Contains(back, r.Via):
+					c.report(RuleRelation, rp+".via", "%s has no belongs_to relation %q to %s", r.Model, r.Via, model)
+				case r.Via == "" && len(back) > 1:
+					c.report(RuleRelation, rp, "%s belongs to %s through %s: set via to one of them", r.Model, model, strings.Join(back, " and "))
+				}
+			case "many_to_many":
+				jt := joinTable{Owner: model, Name: name, Target: r.Model}
+				switch {
+				case tables[jt.table()] != "":
+					c.report(RuleRelation, rp, "join table %s of relation %q is the table of model %s", jt.table(), name, tables[jt.table()])
+				case jt.ownerColumn() == jt.targetColumn():
+					c.report(RuleRelation, rp, "join table %s would hold %s twice: rename relation %q", jt.table(), jt.ownerColumn(), name)
+				}
+			}
+		}
+	}
+
+	fks, _ := featLinks(c.cfg.Source, fp, f)
+	seen := map[string]foreignKey{}
+	for _, fk := range fks {
+		rp := fmt.Sprintf("%s.models.%s.relations.%s", fp, fk.Model, fk.Name)
+		if fk.Implied {
+			rp = fmt.Sprintf("%s.models.%s.relations", fp, fk.Target)
+		}
+		key := fk.Model + "." + fk.field()
+		switch prev, dup := seen[key]; {
+		case dup:
+			c.report(RuleRelation, rp, "%s.%s is added by both %s and %s", fk.Model, fk.field(), prev.origin(), fk.origin())
+		case hasField(f.Models[fk.Model], fk.field()):
+			c.report(RuleRelation, rp, "%s.%s is a declared field, but %s adds it", fk.Model, fk.field(), fk.origin())
+		}
+		seen[key] = fk
+	}
+}
+
+func (c *specChecker) checkRefs(name string, f Feature) {
+	for _, r := range modelRefs(c.cfg.Source, name, f) {
+		p := fmt.Sprintf("feats.%s.models.%s.fields.%s.ref", name, r.Model, r.Field)
+		owner, known := c.cfg.Feats[r.Owner]
+		_, ok := owner.Models[r.Target]
+		switch {
+		case r.Owner == name:
+			c.report(RuleRelation, p, "ref %s.%s is in this feat: use a belongs_to relation", r.Owner, r.Target)
+		case !known:
+			c.report(RuleUnknownFeat, p, "ref to unknown feat %q", r.Owner)
+		case !ok:
+			c.report(RuleUnknownModel, p, "ref to unknown model %q of feat %s", r.Target, r.Owner)
+		case childModels(owner)[r.Target]:
+			c.report(RuleAggregate, p, "ref to %s.%s, which lives in a child collection", r.Owner, r.Target)
+		}
+	}
+}
+
+// hasField reports whether m declares field name, in any case style.
+func hasField(m Model, name string) bool {
+	for field := range m.Fields {
+		if toSnakeCase(field) == toSnakeCase(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *specChecker) checkRequires(f Feature, fp string) {
+	for i, dep := range f.Requires {
+		p := fmt.Sprintf("%s.requires[%d]", fp, i)
+		switch {
+		case dep == f.Name:
+			c.report(RuleOrdering, p, "feat %q requires itself", dep)
+		case !c.hasFeat(dep):
+			c.report(RuleOrdering, p, "unknown feat %q", dep)
+		}
+	}
+}
+
+func (c *specChecker) checkOrdering() {
+	for i, edge := range c.cfg.Ordering.Requires {
+		for j, name := range edge {
+			if !c.hasFeat(name) {
+				p := fmt.Sprintf("ordering.requires[%d][%d]", i, j)
+				c.report(RuleOrdering, p, "unknown feat %q", name)
+			}
+		}
+	}
+	names := declared(c.cfg.Source, "feats", c.cfg.Feats)
+	if cycle := findCycle(names, c.cfg.featDeps()); cycle != nil {
+		c.report(RuleOrderCycle, c.edgePath(cycle[0], cycle[1]), "dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+}
+
+// edgePath returns the path declaring that feat depends on dep.
+func (c *specChecker) edgePath(feat, dep string) string {
+	f := c.cfg.Feats[feat]
+	if i := slices.Index(f.Requires, dep); i >= 0 {
+		return fmt.Sprintf("feats.%s.requires[%d]", feat, i)
+	}
+	if i := slices.Index(c.cfg.Ordering.Requires, [2]string{feat, dep}); i >= 0 {
+		return fmt.Sprintf("ordering.requires[%d]", i)
+	}
+	for i, p := range f.Web.Pages {
+		if j := slices.Index(p.Uses, dep); j >= 0 {
+			return fmt.Sprintf("feats.%s.web.pages[%d].uses[%d]", feat, i, j)
+		}
+	}
+	for _, r := range modelRefs(c.cfg.Source, feat, f) {
+		if r.Owner == dep {
+			return fmt.Sprintf("feats.%s.models.%s.fields.%s.ref", feat, r.Model, r.Field)
+		}
+	}
+	return "ordering.requires"
+}
+
+func (c *specChecker) hasFeat(name string) bool {
+	_, ok := c.cfg.Feats[name]
+	return ok
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
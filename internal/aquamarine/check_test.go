@@ -0,0 +1,69 @@
+package aquamarine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckSpec(t *testing.T) {
+	const head = "version: 0.2\nproject: {name: shop, module: example.com/shop}\nruntime: {http: {api: {port: 8081}, web: {port: 8080}}}\n"
+	tests := []struct {
+		name, spec       string
+		rule, path, want string
+		line             int
+	}{
+		{"handler not a method", "feats:\n  - name: orders\n    service: {methods: [Pay]}\n    api: {routes: [{method: POST, path: /pay, handler: Refund}]}\n",
+			RuleHandler, "feats.orders.api.routes[0].handler", `handler "Refund" is not listed in service.methods`, 7},
+		{"page uses its own feat", "feats:\n  - name: web\n    web: {pages: [{route: GET /, uses: [web]}]}\n",
+			RuleUnknownFeat, "feats.web.web.pages[0].uses[0]", `page uses its own feat "web"`, 6},
+		{"page uses unknown feat", "feats:\n  - name: web\n    web: {pages: [{route: GET /, uses: [orders]}]}\n",
+			RuleUnknownFeat, "feats.web.web.pages[0].uses[0]", `page uses unknown feat "orders"`, 6},
+		{"requires itself", "feats:\n  - name: orders\n    requires: [orders]\n",
+			RuleOrdering, "feats.orders.requires[0]", `feat "orders" requires itself`, 6},
+		{"requires unknown feat", "feats:\n  - name: orders\n    requires: [auth]\n",
+			RuleOrdering, "feats.orders.requires[0]", `unknown feat "auth"`, 6},
+		{"ordering unknown feat", "ordering: {requires: [[orders, auth]]}\nfeats:\n  - name: orders\n",
+			RuleOrdering, "ordering.requires[0][1]", `unknown feat "auth"`, 4},
+		{"cycle", "feats:\n  - name: orders\n    requires: [auth]\n  - name: auth\n    requires: [orders]\n",
+			RuleOrderCycle, "feats.orders.requires[0]", "dependency cycle: orders -> auth -> orders", 6},
+	}
+	for _, tt := range tests {
+		cfg, diags := ParseSpec("aquamarine.yaml", []byte(head+tt.spec))
+		if diags.HasErrors() {
+			t.Fatalf("%s: %v", tt.name, diags)
+		}
+		diags = CheckSpec(cfg)
+		if len(diags) != 1 {
+			t.Errorf("%s: got %v, want one diagnostic", tt.name, diags)
+			continue
+		}
+		d := diags[0]
+		if d.Rule != tt.rule || d.Path != tt.path || d.Line != tt.line || d.Severity != SeverityError || !strings.Contains(d.Message, tt.want) {
+			t.Errorf("%s: got %s [%s], want line %d %s: %s [%s]", tt.name, d, d.Rule, tt.line, tt.path, tt.want, tt.rule)
+		}
+	}
+}
+
+func TestCheckSpecValid(t *testing.T) {
+	spec := `version: 0.2
+project: {name: shop, module: example.com/shop}
+runtime: {http: {api: {port: 8081}, web: {port: 8080}}}
+ordering: {requires: [[orders, auth]]}
+feats:
+  - name: auth
+    models: {User: {fields: {email: {type: email}}}}
+  - name: orders
+    service: {methods: [Pay]}
+    api: {routes: [{method: POST, path: /pay, handler: Pay}]}
+  - name: web
+    requires: [orders]
+    web: {pages: [{route: GET /, uses: [orders, auth]}]}
+`
+	cfg, diags := ParseSpec("aquamarine.yaml", []byte(spec))
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	if diags := CheckSpec(cfg); len(diags) > 0 {
+		t.Errorf("got %v, want no diagnostics", diags)
+	}
+}
@@ -1,11 +1,19 @@
 package aquamarine
 
+// Config is the canonical in-memory model of aquamarine.yaml.
+// It is produced by LoadSpec and consumed by every generator stage.
 type Config struct {
 	Version    string             `yaml:"version"`
 	Project    ProjectConfig      `yaml:"project"`
 	Runtime    RuntimeConfig      `yaml:"runtime,omitempty"`
+	Ordering   OrderingConfig     `yaml:"ordering,omitempty"`
+	Templates  TemplatesConfig    `yaml:"templates,omitempty"`
+	Hooks      HooksConfig        `yaml:"hooks,omitempty"`
+	Include    []string           `yaml:"include,omitempty"` // globs of feat files, relative to the spec
+	Profiles   map[string]Profile `yaml:"profiles,omitempty"`
 	Feats      map[string]Feature `yaml:"feats"`
 	ModulePath string             `yaml:"-"` // Set during generation
+	Source     SourceMap          `yaml:"-"` // Set by the spec loader
 }
 
 // ProjectConfig contains project-level configuration.
@@ -41,11 +49,39 @@ type WebConfig struct {
 // DatabaseConfig contains database configuration.
 type DatabaseConfig struct {
 	Engine string `yaml:"engine,omitempty"`
+	DSN    string `yaml:"dsn,omitempty"`
 }
 
+// Profile selects how and where generate renders the spec. The dev and prod
+// profiles always exist; declaring them adjusts them.
+type Profile struct {
+	Local   *bool         `yaml:"local,omitempty"`   // build against the local aquamarine checkout; dev only by default
+	Output  string        `yaml:"output,omitempty"`  // relative to the spec; out/<profile> by default
+	Runtime RuntimeConfig `yaml:"runtime,omitempty"` // values set here replace the spec's
+}
+
+// OrderingConfig contains optional cross-feature ordering edges.
+type OrderingConfig struct {
+	// Requires holds [feat, dependency] pairs: the first feat depends on the second.
+	Requires [][2]string `yaml:"requires,omitempty"`
+}
+
+// TemplatesConfig layers local templates over the built-in ones, file by file.
+type TemplatesConfig struct {
+	Dir   string   `yaml:"dir,omitempty"`   // overrides, relative to the spec file
+	Packs []string `yaml:"packs,omitempty"` // pack names or paths; later packs win
+}
+
+// HooksConfig lists shell commands run around generate, in the output dir.
+type HooksConfig struct {
+	PreGenerate  []string `yaml:"pre_generate,omitempty"`  // before any file is written
+	PostGenerate []string `yaml:"post_generate,omitempty"` // after a run without conflicts
+}
+
+// Feature represents a single feat entry of the spec.
 type Feature struct {
 	Name       string               `yaml:"name,omitempty"`
-	Kind       string               `yaml:"kind,omitempty"` // "feature", "web", "support"
+	Kind       string               `yaml:"kind,omitempty"` // "atom", "domain", "web"
 	Models     map[string]Model     `yaml:"models,omitempty"`
 	Service    ServiceConfig        `yaml:"service,omitempty"`
 	API        APIFeatureConfig     `yaml:"api,omitempty"`
@@ -53,6 +89,7 @@ type Feature struct {
 	RepoImpl   []string             `yaml:"repo_impl,omitempty"`
 	Auth       *AuthConfig          `yaml:"auth,omitempty"`
 	Aggregates map[string]Aggregate `yaml:"aggregates,omitempty"`
+	Requires   []string             `yaml:"requires,omitempty"` // feats this one depends on, like ordering.requires edges
 }
 
 // ServiceConfig contains service-level configuration.
@@ -85,13 +122,27 @@ type PageConfig struct {
 
 // Model represents a domain model.
 type Model struct {
-	Fields  map[string]Field `yaml:"fields,omitempty"`
-	Options *ModelOptions    `yaml:"options,omitempty"`
+	Fields    map[string]Field    `yaml:"fields,omitempty"`
+	Relations map[string]Relation `yaml:"relations,omitempty"`
+	Options   *ModelOptions       `yaml:"options,omitempty"`
+}
+
+// Relation links a model to another model of its feat.
+type Relation struct {
+	Type     string `yaml:"type"`               // belongs_to, has_many or many_to_many
+	Model    string `yaml:"model"`              // the related model
+	Via      string `yaml:"via,omitempty"`      // has_many: the belongs_to of Model it is the inverse of
+	Nullable bool   `yaml:"nullable,omitempty"` // belongs_to: the foreign key may be null
 }
 
 // Field represents a model field.
 type Field struct {
 	Type        string       `yaml:"type"`
+	Of          string       `yaml:"of,omitempty"`     // element type of a list
+	Values      []string     `yaml:"values,omitempty"` // values of an enum
+	Ref         string       `yaml:"ref,omitempty"`    // uuid: the feat.Model of another feat the id refers to
+	Nullable    bool         `yaml:"nullable,omitempty"`
+	Default     string       `yaml:"default,omitempty"`
 	Validations []Validation `yaml:"validations,omitempty"`
 }
 
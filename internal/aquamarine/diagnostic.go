@@ -0,0 +1,212 @@
+package aquamarine
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity classifies a diagnostic.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a single finding produced while loading or checking a spec.
+// Line and Column are 1-based; zero means the position is unknown.
+type Diagnostic struct {
+	Rule     string   `json:"rule"`
+	File     string   `json:"file"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Path     string   `json:"path,omitempty"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	var b strings.Builder
+	b.WriteString(d.File)
+	if d.Line > 0 {
+		fmt.Fprintf(&b, ":%d:%d", d.Line, d.Column)
+	}
+	b.WriteString(": ")
+	if d.Severity == SeverityWarning {
+		b.WriteString("warning: ")
+	}
+	if d.Path != "" {
+		b.WriteString(d.Path)
+		b.WriteString(": ")
+	}
+	b.WriteString(d.Message)
+	return b.String()
+}
+
+// Diagnostics is an ordered list of findings. It implements error so a loader
+// can hand back every problem at once.
+type Diagnostics []Diagnostic
+
+// Error renders one diagnostic per line.
+func (ds Diagnostics) Error() string {
+	lines := make([]string, 0, len(ds))
+	for _, d := range ds {
+		lines = append(lines, d.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HasErrors reports whether any diagnostic has error severity.
+func (ds Diagnostics) HasErrors() bool {
+	for _, d := range ds {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Errors returns only the error-severity diagnostics.
+func (ds Diagnostics) Errors() Diagnostics {
+	var out Diagnostics
+	for _, d := range ds {
+		if d.Severity == SeverityError {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// sortDiagnostics orders diagnostics by file and position, keeping the
+// discovery order for findings at the same place.
+func sortDiagnostics(ds Diagnostics) {
+	sort.SliceStable(ds, func(i, j int) bool {
+		a, b := ds[i], ds[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+// Pos is a location in a spec file.
+type Pos struct {
+	File   string
+	Line   int
+	Column int
+	Rank   int // order of File in the spec: 0 for the spec itself, then its includes as read
+}
+
+func nodePos(file string, n *yaml.Node) Pos {
+	if n == nil {
+		return Pos{File: file}
+	}
+	return Pos{File: file, Line: n.Line, Column: n.Column}
+}
+
+// SourceMap records where each spec path was declared, keyed by the same
+// dotted paths used in diagnostics (e.g. "feats.auth.api.routes[0].handler").
+type SourceMap map[string]Pos
+
+// nearest returns the position of path or of its closest declared ancestor.
+func (sm SourceMap) nearest(path string) (Pos, bool) {
+	for path != "" {
+		if p, ok := sm[path]; ok {
+			return p, true
+		}
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+	return Pos{}, false
+}
+
+// before reports whether path a was declared before path b, the spec's own
+// declarations first, then each included file. Declared paths come before
+// undeclared ones, which are left in place.
+func (sm SourceMap) before(a, b string) bool {
+	pa, oka := sm[a]
+	pb, okb := sm[b]
+	switch {
+	case oka && okb:
+		if pa.Rank != pb.Rank {
+			return pa.Rank < pb.Rank
+		}
+		return pa.Line < pb.Line || (pa.Line == pb.Line && pa.Column < pb.Column)
+	default:
+		return oka && !okb
+	}
+}
+
+// declared returns the keys of m, the entries of the spec path, in the order
+// the spec declares them. Keys without a position (a Config built in code)
+// follow in name order.
+func declared[V any](sm SourceMap, path string, m map[string]V) []string {
+	keys := sortedKeys(m)
+	sort.SliceStable(keys, func(i, j int) bool {
+		return sm.before(join(path, keys[i]), join(path, keys[j]))
+	})
+	return keys
+}
+
+// Diagnostic rules, used as stable identifiers in machine-readable output.
+const (
+	RuleSyntax       = "syntax"
+	RuleSchema       = "schema"
+	RuleHandler      = "unknown-handler"
+	RuleUnknownFeat  = "unknown-feat"
+	RuleUnknownModel = "unknown-model"
+	RuleRelation     = "relation"
+	RuleAggregate    = "aggregate"
+	RuleOrdering     = "ordering"
+	RuleOrderCycle   = "ordering-cycle"
+)
+
+// diagnosticList accumulates positioned diagnostics under a single rule.
+type diagnosticList struct {
+	rule  string
+	diags Diagnostics
+}
+
+func (dl *diagnosticList) errorf(pos Pos, path, format string, args ...any) {
+	dl.add(SeverityError, pos, path, fmt.Sprintf(format, args...))
+}
+
+func (dl *diagnosticList) warnf(pos Pos, path, format string, args ...any) {
+	dl.add(SeverityWarning, pos, path, fmt.Sprintf(format, args...))
+}
+
+func (dl *diagnosticList) add(sev Severity, pos Pos, path, msg string) {
+	dl.diags = append(dl.diags, Diagnostic{
+		Rule:     dl.rule,
+		File:     pos.File,
+		Line:     pos.Line,
+		Column:   pos.Column,
+		Path:     path,
+		Severity: sev,
+		Message:  msg,
+	})
+}
+
+var yamlLineRe = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// syntaxDiagnostic converts a yaml.v3 parse error into a positioned diagnostic.
+func syntaxDiagnostic(file string, err error) Diagnostic {
+	d := Diagnostic{Rule: RuleSyntax, File: file, Severity: SeverityError, Message: err.Error()}
+	if m := yamlLineRe.FindStringSubmatch(err.Error()); m != nil {
+		d.Line, _ = strconv.Atoi(m[1])
+		d.Column = 1
+		d.Message = m[2]
+	}
+	return d
+}
@@ -0,0 +1,39 @@
+package aquamarine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeclared(t *testing.T) {
+	sm := SourceMap{
+		"feats.web":    {File: "aquamarine.yaml", Line: 9, Column: 5},
+		"feats.auth":   {File: "aquamarine.yaml", Line: 4, Column: 5},
+		"feats.orders": {File: "feats/orders.yaml", Line: 1, Column: 1, Rank: 2},
+		"feats.users":  {File: "feats/users.yaml", Line: 8, Column: 1, Rank: 1},
+		"feats.a":      {File: "aquamarine.yaml", Line: 4, Column: 12}, // flow map after auth
+	}
+	feats := map[string]int{"web": 0, "auth": 0, "orders": 0, "users": 0, "a": 0, "zeta": 0, "beta": 0}
+	got := strings.Join(declared(sm, "feats", feats), " ")
+	if want := "auth a web users orders beta zeta"; got != want {
+		t.Errorf("declared = %q, want %q (spec, then includes by rank, then undeclared by name)", got, want)
+	}
+
+	for _, tt := range []struct {
+		a, b string
+		want bool
+	}{
+		{"feats.auth", "feats.web", true},
+		{"feats.web", "feats.auth", false},
+		{"feats.web", "feats.users", true}, // a later line of the spec, but an earlier file
+		{"feats.orders", "feats.users", false},
+		{"feats.users", "feats.zeta", true},
+		{"feats.zeta", "feats.users", false},
+		{"feats.beta", "feats.zeta", false}, // undeclared paths keep their order
+		{"feats.auth", "feats.auth", false},
+	} {
+		if got := sm.before(tt.a, tt.b); got != tt.want {
+			t.Errorf("before(%s, %s) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
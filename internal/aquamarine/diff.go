@@ -0,0 +1,166 @@
+package aquamarine
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const diffContext = 3
+
+// edit is a single line of a line diff: ' ' kept, '-' deleted, '+' inserted.
+type edit struct {
+	op   byte
+	line string
+}
+
+// unifiedDiff writes the unified diff turning a into b. Nothing is written
+// when they are equal.
+func unifiedDiff(w io.Writer, oldName, newName string, a, b []byte) error {
+	edits := lineDiff(splitDiffLines(a), splitDiffLines(b))
+	changed := false
+	for _, e := range edits {
+		if e.op != ' ' {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", oldName, newName); err != nil {
+		return err
+	}
+
+	// Line numbers before each edit, for the hunk headers.
+	oldPos := make([]int, len(edits)+1)
+	newPos := make([]int, len(edits)+1)
+	for i, e := range edits {
+		oldPos[i+1], newPos[i+1] = oldPos[i], newPos[i]
+		if e.op != '+' {
+			oldPos[i+1]++
+		}
+		if e.op != '-' {
+			newPos[i+1]++
+		}
+	}
+
+	for i := 0; i < len(edits); {
+		for i < len(edits) && edits[i].op == ' ' {
+			i++
+		}
+		if i == len(edits) {
+			break
+		}
+		start := max(i-diffContext, 0)
+		end := i
+		for end < len(edits) {
+			if edits[end].op != ' ' {
+				end++
+				continue
+			}
+			j := end
+			for j < len(edits) && edits[j].op == ' ' {
+				j++
+			}
+			if j == len(edits) || j-end > 2*diffContext {
+				end = min(end+diffContext, j)
+				break
+			}
+			end = j
+		}
+		if err := writeHunk(w, edits[start:end], oldPos[start], oldPos[end]-oldPos[start], newPos[start], newPos[end]-newPos[start]); err != nil {
+			return err
+		}
+		i = end
+	}
+	return nil
+}
+
+func writeHunk(w io.Writer, edits []edit, oldStart, oldCount, newStart, newCount int) error {
+	// Empty ranges are numbered after the line they follow.
+	if oldCount > 0 {
+		oldStart++
+	}
+	if newCount > 0 {
+		newStart++
+	}
+	if _, err := fmt.Fprintf(w, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount); err != nil {
+		return err
+	}
+	for _, e := range edits {
+		line := string(e.op) + e.line
+		if !strings.HasSuffix(line, "\n") {
+			line += "\n\\ No newline at end of file\n"
+		}
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func splitDiffLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	lines := splitLines(content)
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// lineDiff returns a shortest edit script from a to b, computed as a longest
+// common subsequence after trimming the common prefix and suffix. Generated
+// files are small enough for the quadratic table.
+func lineDiff(a, b []string) []edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// lcs[i][j] is the LCS length of ma[i:] and mb[j:].
+	lcs := make([][]int, len(ma)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(mb)+1)
+	}
+	for i := len(ma) - 1; i >= 0; i-- {
+		for j := len(mb) - 1; j >= 0; j-- {
+			if ma[i] == mb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	edits := make([]edit, 0, len(a)+len(b))
+	for _, l := range a[:prefix] {
+		edits = append(edits, edit{' ', l})
+	}
+	i, j := 0, 0
+	for i < len(ma) || j < len(mb) {
+		switch {
+		case i < len(ma) && j < len(mb) && ma[i] == mb[j]:
+			edits = append(edits, edit{' ', ma[i]})
+			i++
+			j++
+		case i < len(ma) && (j == len(mb) || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{'-', ma[i]})
+			i++
+		default:
+			edits = append(edits, edit{'+', mb[j]})
+			j++
+		}
+	}
+	for _, l := range a[len(a)-suffix:] {
+		edits = append(edits, edit{' ', l})
+	}
+	return edits
+}
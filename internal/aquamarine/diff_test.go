@@ -0,0 +1,40 @@
+package aquamarine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	lines := func(n int) string {
+		var b strings.Builder
+		for i := 1; i <= n; i++ {
+			b.WriteString(string(rune('a'+i-1)) + "\n")
+		}
+		return b.String()
+	}
+	tests := []struct {
+		name, a, b, want string
+	}{
+		{"equal", "a\nb\n", "a\nb\n", ""},
+		{"created", "", "a\nb\n", "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n"},
+		{"deleted", "a\nb\n", "", "--- old\n+++ new\n@@ -1,2 +0,0 @@\n-a\n-b\n"},
+		{"changed line with context", lines(9), strings.Replace(lines(9), "e\n", "E\n", 1),
+			"--- old\n+++ new\n@@ -2,7 +2,7 @@\n b\n c\n d\n-e\n+E\n f\n g\n h\n"},
+		{"inserted line", "a\nc\n", "a\nb\nc\n", "--- old\n+++ new\n@@ -1,2 +1,3 @@\n a\n+b\n c\n"},
+		{"distant changes split in hunks", lines(12), strings.Replace(strings.Replace(lines(12), "a\n", "A\n", 1), "l\n", "L\n", 1),
+			"--- old\n+++ new\n@@ -1,4 +1,4 @@\n-a\n+A\n b\n c\n d\n@@ -9,4 +9,4 @@\n i\n j\n k\n-l\n+L\n"},
+		{"close changes share a hunk", lines(8), strings.Replace(strings.Replace(lines(8), "b\n", "B\n", 1), "g\n", "G\n", 1),
+			"--- old\n+++ new\n@@ -1,8 +1,8 @@\n a\n-b\n+B\n c\n d\n e\n f\n-g\n+G\n h\n"},
+		{"no newline at end", "a\nb", "a\nc", "--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n"},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := unifiedDiff(&b, "old", "new", []byte(tt.a), []byte(tt.b)); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != tt.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tt.name, b.String(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package aquamarine
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// unsetEnvError reports a ${VAR} without default whose VAR is not set.
+type unsetEnvError struct {
+	Name string
+}
+
+func (e *unsetEnvError) Error() string {
+	return fmt.Sprintf("environment variable %s is not set (use ${%s:-default} for a fallback)", e.Name, e.Name)
+}
+
+// expandEnv replaces ${VAR} with the value of the environment variable VAR
+// and ${VAR:-default} with default when VAR is unset or empty. $${ is a
+// literal ${; a $ not followed by { is kept as is. An unset VAR without a
+// default is an *unsetEnvError, so a missing secret never renders as empty.
+func expandEnv(s string) (string, error) {
+	return expandWith(s, os.LookupEnv)
+}
+
+// checkEnvRefs reports the malformed ${...} references of s without reading
+// the environment, for values expanded when the generated app starts.
+func checkEnvRefs(s string) error {
+	_, err := expandWith(s, func(string) (string, bool) { return "", true })
+	return err
+}
+
+func expandWith(s string, lookup func(string) (string, bool)) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		if i > 0 && s[i-1] == '$' {
+			b.WriteString(s[:i-1] + "${")
+			s = s[i+2:]
+			continue
+		}
+		b.WriteString(s[:i])
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated ${ in %q", s)
+		}
+		ref := s[i+2 : i+end]
+		s = s[i+end+1:]
+
+		name, def, hasDef := strings.Cut(ref, ":-")
+		if !identRe.MatchString(name) {
+			return "", fmt.Errorf("invalid variable name %q in ${%s}", name, ref)
+		}
+		v, ok := lookup(name)
+		switch {
+		case hasDef && v == "":
+			v = def
+		case !ok:
+			return "", &unsetEnvError{Name: name}
+		}
+		b.WriteString(v)
+	}
+}
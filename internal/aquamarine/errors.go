@@ -0,0 +1,81 @@
+package aquamarine
+
+import "fmt"
+
+// TemplateError reports a template that does not parse, fails to execute or
+// renders invalid Go.
+type TemplateError struct {
+	Template string
+	File     string // output file being rendered, empty for parse errors
+	Err      error
+}
+
+func (e *TemplateError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("template %s: %v", e.Template, e.Err)
+	}
+	return fmt.Sprintf("%s: template %s: %v", e.File, e.Template, e.Err)
+}
+
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// ExitError ends a command with a failing exit status and no message of its
+// own: the command already reported why, as validate does in its report.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// PluginError reports a plugin that failed or returned a file it may not
+// write.
+type PluginError struct {
+	Plugin string
+	File   string // offending file, empty when the plugin itself failed
+	Err    error
+}
+
+func (e *PluginError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("plugin %s: %v", e.Plugin, e.Err)
+	}
+	return fmt.Sprintf("%s: plugin %s: %v", e.File, e.Plugin, e.Err)
+}
+
+func (e *PluginError) Unwrap() error {
+	return e.Err
+}
+
+// ConflictError reports hand-edited files that conflict with the spec. Their
+// new content was written next to them with ConflictSuffix.
+type ConflictError struct {
+	Files []string
+}
+
+func (e *ConflictError) Error() string {
+	verb := "conflict"
+	if len(e.Files) == 1 {
+		verb = "conflicts"
+	}
+	return fmt.Sprintf("%s edited by hand %s with the spec: merge in each %s file, then delete it, or rerun with --force",
+		count(len(e.Files), "file"), verb, ConflictSuffix)
+}
+
+// Err returns a *ConflictError listing the conflicts of the plan, nil when
+// there are none.
+func (p *Plan) Err() error {
+	var files []string
+	for _, c := range p.Changes {
+		if c.Action == FileConflict {
+			files = append(files, c.Path)
+		}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	return &ConflictError{Files: files}
+}
@@ -0,0 +1,37 @@
+package aquamarine
+
+import "testing"
+
+func TestFormatGo(t *testing.T) {
+	const local = "example.com/app"
+	tests := []struct {
+		name, src, want string
+	}{
+		{"groups",
+			"package x\nimport (\n\"example.com/app/internal/web\"\n\"github.com/google/uuid\"\n\"fmt\"\nam \"github.com/aquamarinepk/aquamarine/pkg/lib/am\"\n\"context\"\n)\nvar _ = fmt.Sprint\n",
+			"package x\n\nimport (\n\t\"context\"\n\t\"fmt\"\n\n\t\"github.com/google/uuid\"\n\n\t\"example.com/app/internal/web\"\n\tam \"github.com/aquamarinepk/aquamarine/pkg/lib/am\"\n)\n\nvar _ = fmt.Sprint\n"},
+		{"prefix is a path",
+			"package x\n\nimport (\n\t\"example.com/appx\"\n\t\"example.com/app\"\n)\n",
+			"package x\n\nimport (\n\t\"example.com/appx\"\n\n\t\"example.com/app\"\n)\n"},
+		{"commented block left as written",
+			"package x\n\nimport (\n\t\"github.com/google/uuid\"\n\t// aquamarine:user imports\n\t\"fmt\"\n\t// aquamarine:end\n)\n",
+			"package x\n\nimport (\n\t\"github.com/google/uuid\"\n\t// aquamarine:user imports\n\t\"fmt\"\n\t// aquamarine:end\n)\n"},
+		{"single import",
+			"package x\nimport \"fmt\"\nvar _ = fmt.Sprint\n",
+			"package x\n\nimport \"fmt\"\n\nvar _ = fmt.Sprint\n"},
+	}
+	for _, tt := range tests {
+		got, err := formatGo([]byte(tt.src), runtimeModule, local)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := formatGo([]byte("package x\nfunc {\n")); err == nil {
+		t.Error("invalid Go: want an error")
+	}
+}
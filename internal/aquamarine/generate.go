@@ -1,219 +1,140 @@
 package aquamarine
 
 import (
-	"embed"
-	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 
-	"gopkg.in/yaml.v3"
+	"github.com/aquamarinepk/aquamarine/internal/core"
 )
 
-type Spec struct {
-	Version string `yaml:"version"`
-	Project struct {
-		Name   string `yaml:"name"`
-		Module string `yaml:"module"`
-	} `yaml:"project"`
-	Runtime struct {
-		HTTP struct {
-			API struct {
-				Host string `yaml:"host"`
-				Port int    `yaml:"port"`
-			} `yaml:"api"`
-			Web struct {
-				Host string `yaml:"host"`
-				Port int    `yaml:"port"`
-			} `yaml:"web"`
-		} `yaml:"http"`
-		Database struct {
-			Engine string `yaml:"engine"`
-			DSN    string `yaml:"dsn"`
-		} `yaml:"database"`
-	} `yaml:"runtime"`
-	Feats []Feat `yaml:"feats"`
+// GenerateOptions controls a generate run.
+type GenerateOptions struct {
+	Profile   string   // spec profile; defaults to ProfileProd
+	SpecFile  string   // defaults to DefaultSpecFile
+	OutputDir string   // defaults to the profile's output, or out/<profile>
+	Templates string   // template dir layered over the spec's templates and packs
+	Feats     []string // limit feat files to these feats; nil renders all
+	DryRun    bool     // print the planned changes without writing
+	Force     bool     // overwrite files that conflict with hand edits
+	NoHooks   bool     // skip the spec's hooks
+	Plugins   []Plugin // contribute files after the templates
 }
 
-type Feat struct {
-	Name  string `yaml:"name"`
-	Kind  string `yaml:"kind"`
-	Model struct {
-		Name   string                    `yaml:"name"`
-		Fields map[string]map[string]any `yaml:"fields"`
-	} `yaml:"model"`
-	Models map[string]struct {
-		Fields map[string]map[string]any `yaml:"fields"`
-	} `yaml:"models"`
-	Service struct {
-		Methods []string `yaml:"methods"`
-	} `yaml:"service"`
-	API struct {
-		Routes []Route `yaml:"routes"`
-	} `yaml:"api"`
-	Pages []Page `yaml:"pages"`
-}
-
-type Route struct {
-	Method  string `yaml:"method"`
-	Path    string `yaml:"path"`
-	Handler string `yaml:"handler"`
-}
-
-type Page struct {
-	Route string   `yaml:"route"`
-	Uses  []string `yaml:"uses"`
-}
-
-// Generate creates a minimal app skeleton under out/<mode> based on aquamarine.yaml.
-// It does not overwrite files outside the out/ tree.
-func Generate(assetsFS embed.FS, mode string) error {
-	spec, err := readSpec("aquamarine.yaml")
+// Generate renders the app described by the spec, by default aquamarine.yaml
+// into out/<profile>, with the profile's runtime settings. It does not write
+// outside the output dir and never overwrites hand edits unless forced; see
+// FeatureGenerator.Generate.
+//
+// The spec's pre_generate hooks run before anything is written, its
+// post_generate hooks after a run without conflicts, and the manifest then
+// records the files they rewrote.
+func Generate(templates fs.FS, opts GenerateOptions) error {
+	spec, fg, err := newGenerator(templates, opts)
 	if err != nil {
 		return err
 	}
-	outRoot := filepath.Join("out", mode)
-
-	fmt.Printf("Generating aquamarine project '%s' in directory: %s\n", spec.Project.Name, outRoot)
+	fg.Force = opts.Force
+	fg.Scope = opts.Feats
 
-	// Create base directory structure
-	if err := os.MkdirAll(outRoot, 0o755); err != nil {
-		return err
+	if opts.DryRun {
+		plan, err := fg.Plan()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Dry run for aquamarine project '%s' (profile %s) in directory: %s (nothing written)\n",
+			spec.Project.Name, opts.profile(), fg.OutputDir)
+		return plan.WriteSummary(os.Stdout)
 	}
 
-	if err := writeGoMod(outRoot, spec.Project.Module); err != nil {
+	fmt.Printf("Generating aquamarine project '%s' (profile %s) in directory: %s\n", spec.Project.Name, opts.profile(), fg.OutputDir)
+
+	hooks := spec.Hooks
+	if opts.NoHooks {
+		hooks = HooksConfig{}
+	}
+	if err := runHooks(HookPreGenerate, hooks.PreGenerate, fg.OutputDir, opts.specFile(), opts.profile(), fg.DevMode); err != nil {
 		return err
 	}
-	if err := writeAppMain(outRoot, spec); err != nil {
+	if err := fg.Generate(); err != nil {
 		return err
 	}
-
-	// Base dirs
-	for _, d := range []string{
-		"internal/platform",
-		"internal/web",
-		"internal/feat",
-		"assets/templates",
-		"assets/migrations/sqlite",
-		"assets/seeds/sqlite",
-		"assets/queries/sqlite",
-	} {
-		if err := os.MkdirAll(filepath.Join(outRoot, d), 0o755); err != nil {
-			return err
-		}
+	if len(hooks.PostGenerate) == 0 {
+		return nil
 	}
-
-	for _, f := range spec.Feats {
-		featDir := filepath.Join(outRoot, "internal/feat", f.Name)
-		if err := os.MkdirAll(featDir, 0o755); err != nil {
-			return err
-		}
-		if err := os.WriteFile(filepath.Join(featDir, "README.md"), []byte("# "+f.Name+"\n\nGenerated feat skeleton."), 0o644); err != nil {
-			return err
-		}
-		// TODO: move this to assets/templates/service.tmpl
-		svc := fmt.Sprintf("package %s\n\n// Service methods (scaffold): %v\n", f.Name, f.Service.Methods)
-		_ = os.WriteFile(filepath.Join(featDir, "service.go"), []byte(svc), 0o644)
+	if err := runHooks(HookPostGenerate, hooks.PostGenerate, fg.OutputDir, opts.specFile(), opts.profile(), fg.DevMode); err != nil {
+		return err
 	}
-
-	assetsReadme := []byte("# assets\n\nCentralized assets for the generated app.\n")
-	_ = os.WriteFile(filepath.Join(outRoot, "assets/README.md"), assetsReadme, 0o644)
-
-	return nil
+	// A post_generate hook such as go mod tidy rewrites generated files: its
+	// output is generate's own, not a hand edit to keep on the next run.
+	return fg.plan.Rehash()
 }
 
-func readSpec(path string) (*Spec, error) {
-	b, err := os.ReadFile(path)
+// Diff writes a unified diff of what generate would change in the output dir
+// and returns the warnings of the plan, which are not part of the diff.
+func Diff(templates fs.FS, w io.Writer, opts GenerateOptions) ([]string, error) {
+	_, fg, err := newGenerator(templates, opts)
 	if err != nil {
 		return nil, err
 	}
-	var s Spec
-	if err := yaml.Unmarshal(b, &s); err != nil {
+	plan, err := fg.Plan()
+	if err != nil {
 		return nil, err
 	}
-	if s.Project.Module == "" {
-		return nil, errors.New("project.module is required")
-	}
-	return &s, nil
+	return plan.Warnings, plan.WriteDiff(w)
 }
 
-func writeGoMod(outRoot, module string) error {
-	// TODO: move this to assets/templates/go.mod.tmpl
-	rel := "../.." // from out/<mode> to repo root
-	content := fmt.Sprintf(`module %s
-
-go 1.22
-
-require github.com/aquamarinepk/aquamarine v0.0.0
-
-replace github.com/aquamarinepk/aquamarine => %s
-`, module, rel)
-	return safeWriteFile(filepath.Join(outRoot, "go.mod"), []byte(content), 0o644)
+func (opts GenerateOptions) specFile() string {
+	if opts.SpecFile == "" {
+		return DefaultSpecFile
+	}
+	return opts.SpecFile
 }
 
-func writeAppMain(outRoot string, cfg *Spec) error {
-	// TODO: move this to assets/templates/main.tmpl
-	main := fmt.Sprintf(`package main
-
-import (
-  "context"
-  "embed"
-  "log"
-  "net/http"
-  "os/signal"
-  "syscall"
-
-  "github.com/go-chi/chi/v5"
-  "github.com/aquamarinepk/aquamarine/pkg/lib/am"
-)
-
-//go:embed assets
-var assetsFS embed.FS
-
-func main() {
-  apiPort := ":8081"
-  webPort := ":%d"
-
-  logger := am.NewLogger("info")
-
-  ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-  defer cancel()
-
-  apiRouter := chi.NewRouter()
-  webRouter := chi.NewRouter()
-  
-  apiRouter.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {
-    w.WriteHeader(http.StatusOK)
-    _, _ = w.Write([]byte("api ok"))
-  })
-  
-  webRouter.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {
-    w.WriteHeader(http.StatusOK)
-    _, _ = w.Write([]byte("web ok"))
-  })
-
-  var deps []any
-
-  starts, stops := am.Setup(ctx, apiRouter, webRouter, deps...)
-
-  if err := am.Start(ctx, starts, stops); err != nil {
-    log.Fatal(err)
-  }
-
-  servers := []am.Server{
-    {Name: "API", Addr: apiPort, Handler: apiRouter},
-    {Name: "Web", Addr: webPort, Handler: webRouter},
-  }
-
-  am.StartServers(servers, logger)
-
-  <-ctx.Done()
-  
-  am.GracefulShutdown(servers, stops, logger)
+func (opts GenerateOptions) profile() string {
+	if opts.Profile == "" {
+		return ProfileProd
+	}
+	return opts.Profile
 }
-`, cfg.Runtime.HTTP.Web.Port)
-	return safeWriteFile(filepath.Join(outRoot, "main.go"), []byte(main), 0o644)
+
+func newGenerator(templates fs.FS, opts GenerateOptions) (*Config, *FeatureGenerator, error) {
+	specFile := opts.specFile()
+	spec, specWarnings, err := LoadSpec(specFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	profile, err := spec.ApplyProfile(opts.profile())
+	if err != nil {
+		return nil, nil, err
+	}
+	out := opts.OutputDir
+	switch {
+	case out != "":
+	case profile.Output != "":
+		out = filepath.Join(filepath.Dir(specFile), profile.Output)
+	default:
+		out = filepath.Join("out", opts.profile())
+	}
+	templates, warnings, err := SpecTemplates(templates, spec, filepath.Dir(specFile), opts.Templates)
+	if err != nil {
+		return nil, nil, err
+	}
+	ws := core.BuildConfig(*profile.Local, out, spec.Project.Module).Workspace
+	spec.ModulePath = ws.ModulePath
+	fg, err := NewFeatureGenerator(*spec, ws.OutputDir, ws.DevMode, templates)
+	if err != nil {
+		return nil, nil, err
+	}
+	fg.Plugins = opts.Plugins
+	for _, d := range specWarnings {
+		d.Severity = "" // the summary and diff label plan warnings themselves
+		fg.Warnings = append(fg.Warnings, d.String())
+	}
+	fg.Warnings = append(fg.Warnings, warnings...)
+	return spec, fg, nil
 }
 
 func safeWriteFile(path string, data []byte, perm fs.FileMode) error {
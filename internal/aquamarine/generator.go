@@ -1,39 +1,177 @@
 package aquamarine
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io/fs"
-	"log"
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime/debug"
+	"slices"
 	"strings"
 	"text/template"
 )
 
+// runtimeModule provides pkg/lib/am to generated projects.
+const runtimeModule = "github.com/aquamarinepk/aquamarine"
+
+// RuntimeVersion is the released runtime that prod output pins. Release
+// builds set it with -ldflags "-X <pkg>.RuntimeVersion=vX.Y.Z"; a binary
+// installed with go install at a tagged version uses that version instead.
+var RuntimeVersion = "v0.1.0"
+
+var releaseRe = regexp.MustCompile(`^v\d+\.\d+\.\d+$`)
+
+// Versions pinned in the go.mod of generated projects.
+var generatedRequires = []ModuleRequire{
+	{Path: runtimeModule, Version: "v0.0.0"},
+	{Path: "github.com/go-chi/chi/v5", Version: "v5.1.0"},
+	{Path: "github.com/google/uuid", Version: "v1.6.0"},
+	{Path: "gopkg.in/yaml.v3", Version: "v3.0.1"},
+}
+
+var engineRequires = map[string]ModuleRequire{
+	"sqlite":  {Path: "modernc.org/sqlite", Version: "v1.33.1"},
+	"mongodb": {Path: "go.mongodb.org/mongo-driver", Version: "v1.17.1"},
+}
+
+// ModuleRequire is a single require line of a generated go.mod.
+type ModuleRequire struct {
+	Path    string
+	Version string
+}
+
+// GoModTemplateData holds all data needed to render go.mod.
+type GoModTemplateData struct {
+	ModulePath  string
+	GoVersion   string
+	Requires    []ModuleRequire
+	ReplacePath string
+}
+
 // FieldTemplateData holds data for a single field in the template.
 type FieldTemplateData struct {
 	Name        string
 	Type        string
 	JSONTag     string
+	Column      string
+	SQLType     string
+	Default     string            // Go expression the constructor sets the field to, if any
+	Spec        Field             // the field as the spec declares it
+	Enum        *EnumTemplateData // the type of an enum field
+	References  string            // table a foreign key points to
 	IsID        bool
+	Unique      bool
 	Validations []FieldValidationData
 }
 
+// LookupData is a repo method listing the values of a model by a foreign
+// key: PostRepo.ListPostsForAuthor.
+type LookupData struct {
+	Method string
+	Const  string // the SQL constant of the query
+	Column string
+}
+
+// LinkData is a many_to_many relation of a model: the repo methods that
+// list, assign and unassign the related values.
+type LinkData struct {
+	Target        string
+	TargetTable   string
+	TargetColumns []ColumnData
+	Table         string // SQL join table
+	OwnerColumn   string
+	TargetColumn  string
+	DocField      string // the ids of the related values in a Mongo document
+	List          string
+	Assign        string
+	Unassign      string
+	ListConst     string
+	AssignConst   string
+	UnassignConst string
+}
+
+// JoinTableData is the SQL table of a many_to_many relation.
+type JoinTableData struct {
+	Table        string
+	OwnerTable   string
+	OwnerColumn  string
+	TargetTable  string
+	TargetColumn string
+}
+
+// EnumTemplateData holds the Go type generated for an enum field.
+type EnumTemplateData struct {
+	Type   string
+	Field  string // JSON name of the field, for docs
+	Values []EnumValueData
+}
+
+// EnumValueData is one value of an enum and the constant naming it.
+type EnumValueData struct {
+	Const string
+	Value string
+}
+
 // FieldValidationData holds data for a single validation rule.
+// Check is a Go expression over the model value m that must hold.
 type FieldValidationData struct {
-	Name  string
-	Value string
+	Name    string
+	Value   string
+	Var     string // package-level variable backing the check, if any
+	Check   string
+	Message string
+}
+
+// ColumnData maps a persisted column to the Go field holding its value.
+type ColumnData struct {
+	Column string
+	Field  string
 }
 
 // ModelTemplateData holds all data needed to render a model template.
 type ModelTemplateData struct {
-	PackageName  string
-	ModelName    string
-	Audit        bool
-	Fields       []FieldTemplateData
-	NeedsFmt     bool
-	NeedsStrconv bool
+	PackageName   string
+	ModulePath    string
+	ModelName     string
+	ModelPlural   string
+	VarName       string
+	TableName     string
+	Audit         bool
+	ID            FieldTemplateData
+	Fields        []FieldTemplateData
+	Columns       []ColumnData // every column, ID first
+	UpdateColumns []ColumnData // columns rewritten on update
+	NeedsRegexp   bool
+	NeedsTime     bool // the model file imports time
+	NeedsAM       bool // the model file imports am
+	Enums         []EnumTemplateData
+	Lookups       []LookupData   // one per foreign key
+	Links         []LinkData     // one per many_to_many relation
+	Aggregate     *AggregateData // set on aggregate roots
+}
+
+// AggregateData describes an aggregate root: the field counting its saves,
+// checked by updates, and the child collections saved with it.
+type AggregateData struct {
+	Version       string // Go field
+	VersionColumn string
+	Children      []ChildData
+}
+
+// ChildData describes a child collection of an aggregate root.
+type ChildData struct {
+	Name       string // in the spec: items
+	Field      string // root field holding the children: Items
+	One        string // a child, in method names: Item
+	JSONTag    string
+	Root       string
+	Model      ModelTemplateData // the children's model
+	Table      string
+	RootColumn string
+	Const      string // prefix of its SQL statement constants
 }
 
 // HandlerTemplateData holds all data needed to render a handler template.
@@ -43,10 +181,130 @@ type HandlerTemplateData struct {
 	ModelPlural       string
 	ModelLower        string
 	ModelPluralLower  string
+	IDField           string
 	AuthEnabled       bool
 	Audit             bool
 	ModulePath        string
 	IsChildCollection bool
+	Aggregate         bool // the model is an aggregate root
+}
+
+// FeatureTemplateData holds all data needed to render the feat-level files.
+type FeatureTemplateData struct {
+	PackageName string
+	ModulePath  string
+	Kind        string
+	Atom        *ModelTemplateData
+	Models      []ModelTemplateData
+	Methods     []string
+	Routes      []RouteTemplateData
+	Actions     []ActionTemplateData
+	Pages       []PageTemplateData
+	Uses        []UseTemplateData
+	Ports       []PortData    // read-only views of other feats' models its fields refer to
+	Exposed     []ExposedData // its models other feats refer to, served to their ports
+	JoinTables  []JoinTableData
+	HasService  bool
+	HasAPI      bool
+	AuthEnabled bool
+	// HasAggregates is set when some model is an aggregate root.
+	HasAggregates bool
+	// HasBodyActions is set when some action decodes a JSON request body.
+	HasBodyActions bool
+}
+
+// RouteTemplateData describes a custom API route bound to a service method.
+type RouteTemplateData struct {
+	Method  string // chi router method: Get, Post...
+	Path    string
+	Handler string
+	HasBody bool
+}
+
+// ActionTemplateData describes the HTTP handler of a service method exposed
+// by one or more routes.
+type ActionTemplateData struct {
+	Name    string
+	HasBody bool
+}
+
+// PageTemplateData describes a server-rendered web page.
+type PageTemplateData struct {
+	Method   string // chi router method: Get, Post...
+	Path     string
+	Name     string // template base name, e.g. "index"
+	Func     string // handler method name, e.g. "pageIndex"
+	Title    string
+	Template string // path of the page template under assets
+	Uses     []string
+}
+
+// UseTemplateData describes another feat whose service a web feat consumes.
+type UseTemplateData struct {
+	Feat   string
+	Field  string
+	Import string
+}
+
+// PortData describes the read-only interface through which a feat checks
+// the ids its ref fields hold against the feat owning the model.
+type PortData struct {
+	Type   string // AuthUserReader
+	Feat   string // owning feat
+	Model  string
+	Method string
+	Fields []string // Model.field refs using the port
+}
+
+// ExposedData describes a model other feats refer to; the feat's service
+// serves their ports.
+type ExposedData struct {
+	Model  string
+	Method string
+}
+
+// MainTemplateData holds all data needed to render main.go.
+type MainTemplateData struct {
+	ModulePath string
+	Engine     string
+	DB         bool     // some feat persists models
+	Migrations []string // feats with SQL migrations and seeds, in apply order
+	Feats      []MainFeatData
+}
+
+// MainFeatData describes how main.go wires a single feat.
+type MainFeatData struct {
+	Name    string
+	Alias   string
+	Var     string
+	Repos   []MainRepoData
+	Uses    []MainUseData
+	Assets  bool
+	Service bool
+}
+
+// MainRepoData is a repository dependency built in main.go.
+type MainRepoData struct {
+	Field       string
+	Constructor string
+}
+
+// MainUseData is a service dependency injected from another feat.
+type MainUseData struct {
+	Field string
+	Var   string
+}
+
+// ProjectTemplateData holds data for project-level files (config, Makefile).
+type ProjectTemplateData struct {
+	ProjectName string
+	ModulePath  string
+	APIHost     string
+	APIPort     int
+	WebHost     string
+	WebPort     int
+	Engine      string
+	DSN         string
 }
 
 type FeatureGenerator struct {
@@ -58,113 +316,162 @@ type FeatureGenerator struct {
 	ServiceInterfaceTemplate *template.Template
 	SQLiteRepoTemplate       *template.Template
 	SQLiteQueriesTemplate    *template.Template
+	SQLiteMigrationTemplate  *template.Template
 	MongoRepoTemplate        *template.Template
 	HandlerTemplate          *template.Template
 	ValidatorTemplate        *template.Template
+	FeatureTemplate          *template.Template
+	PageTemplate             *template.Template
 	MainTemplate             *template.Template
+	GoModTemplate            *template.Template
 	ConfigTemplate           *template.Template
 	ConfigYAMLTemplate       *template.Template
 	XParamsTemplate          *template.Template
 	MakefileTemplate         *template.Template
 	AggregateRootTemplate    *template.Template
 	ChildCollectionTemplate  *template.Template
+
+	// Output receives the generated tree; NewFeatureGenerator sets it to
+	// OutputDir on disk. OutputDir still names the tree in messages and
+	// anchors the dev replace path.
+	Output Output
+
+	// Force overwrites hand-edited files that conflict with the spec and
+	// removes hand-edited files the spec no longer produces.
+	Force bool
+	// Scope limits feat files to the named feats; project files are always
+	// rendered. Nil renders every feat.
+	Scope []string
+	// Plugins contribute files after the templates are rendered.
+	Plugins []Plugin
+	// Warnings start the warnings of every plan, such as those of
+	// SpecTemplates.
+	Warnings []string
+
+	plan      *Plan
+	generated map[string]bool
 }
 
-// NewFeatureGenerator creates a new feature generator.
-func NewFeatureGenerator(config Config, outputDir string, devMode bool, assetsFS fs.FS) (*FeatureGenerator, error) {
-	tmplFS, err := fs.Sub(assetsFS, "assets/templates")
+// NewFeatureGenerator creates a feature generator rendering the templates
+// (rooted at the templates dir, see assets.Templates) into outputDir.
+func NewFeatureGenerator(config Config, outputDir string, devMode bool, templates fs.FS) (*FeatureGenerator, error) {
+	tmpl, err := parseTemplate(templates, "model.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	repoInterfaceTmpl, err := parseTemplate(templates, "repo_interface.tmpl")
 	if err != nil {
-		log.Fatalf("cannot create sub-filesystem for templates: %v", err)
+		return nil, err
 	}
 
-	tmpl, err := template.New("model.tmpl").ParseFS(tmplFS, "model.tmpl")
+	serviceInterfaceTmpl, err := parseTemplate(templates, "service_interface.tmpl")
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse model template: %w", err)
+		return nil, err
 	}
 
-	repoInterfaceTmpl, err := template.New("repo_interface.tmpl").ParseFS(tmplFS, "repo_interface.tmpl")
+	sqliteRepoTmpl, err := parseTemplate(templates, "repo_sqlite.tmpl")
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse repository interface template: %w", err)
+		return nil, err
 	}
 
-	serviceInterfaceTmpl, err := template.New("service_interface.tmpl").ParseFS(tmplFS, "service_interface.tmpl")
+	sqliteQueriesTmpl, err := parseTemplate(templates, "queries_sqlite.tmpl")
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse service interface template: %w", err)
+		return nil, err
 	}
 
-	sqliteRepoTmpl, err := template.New("repo_sqlite.tmpl").ParseFS(tmplFS, "repo_sqlite.tmpl")
+	sqliteMigrationTmpl, err := parseTemplate(templates, "migration_sqlite.tmpl")
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse SQLite repository template: %w", err)
+		return nil, err
 	}
 
-	sqliteQueriesTmpl, err := template.New("queries_sqlite.tmpl").ParseFS(tmplFS, "queries_sqlite.tmpl")
+	mongoRepoTmpl, err := parseTemplate(templates, "repo_mongo.tmpl")
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse SQLite queries template: %w", err)
+		return nil, err
 	}
 
-	mongoRepoTmpl, err := template.New("repo_mongo.tmpl").ParseFS(tmplFS, "repo_mongo.tmpl")
+	handlerTmpl, err := parseTemplate(templates, "handler.tmpl")
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse MongoDB repository template: %w", err)
+		return nil, err
 	}
 
-	handlerTmpl, err := template.New("handler.tmpl").ParseFS(tmplFS, "handler.tmpl")
+	validatorTmpl, err := parseTemplate(templates, "validator.tmpl")
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse handler template: %w", err)
+		return nil, err
 	}
 
-	validatorTmpl, err := template.New("validator.tmpl").ParseFS(tmplFS, "validator.tmpl")
+	featureTmpl, err := parseTemplate(templates, "feature.tmpl")
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse validator template: %w", err)
+		return nil, err
 	}
 
-	mainTmpl, err := template.New("main.tmpl").ParseFS(tmplFS, "main.tmpl")
+	pageTmpl, err := parseTemplate(templates, "page.html.tmpl")
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse main template: %w", err)
+		return nil, err
 	}
 
-	configTmpl, err := template.New("config.go.tmpl").ParseFS(tmplFS, "config.go.tmpl")
+	mainTmpl, err := parseTemplate(templates, "main.tmpl")
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse config go template: %w", err)
+		return nil, err
 	}
 
-	configYAMLTmpl, err := template.New("config.yaml.tmpl").ParseFS(tmplFS, "config.yaml.tmpl")
+	goModTmpl, err := parseTemplate(templates, "go.mod.tmpl")
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse config yaml template: %w", err)
+		return nil, err
 	}
 
-	xparamsTmpl, err := template.New("xparams.go.tmpl").ParseFS(tmplFS, "xparams.go.tmpl")
+	configTmpl, err := parseTemplate(templates, "config.go.tmpl")
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse xparams template: %w", err)
+		return nil, err
 	}
 
-	makefileTmpl, err := template.New("Makefile.tmpl").ParseFS(tmplFS, "Makefile.tmpl")
+	configYAMLTmpl, err := parseTemplate(templates, "config.yaml.tmpl")
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse Makefile template: %w", err)
+		return nil, err
 	}
 
-	aggregateRootTmpl, err := template.New("aggregate_root.tmpl").ParseFS(tmplFS, "aggregate_root.tmpl")
+	xparamsTmpl, err := parseTemplate(templates, "xparams.go.tmpl")
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse aggregate root template: %w", err)
+		return nil, err
 	}
 
-	childCollectionTmpl, err := template.New("child_collection.tmpl").ParseFS(tmplFS, "child_collection.tmpl")
+	makefileTmpl, err := parseTemplate(templates, "Makefile.tmpl")
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse child collection template: %w", err)
+		return nil, err
+	}
+
+	aggregateRootTmpl, err := parseTemplate(templates, "aggregate_root.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	childCollectionTmpl, err := parseTemplate(templates, "child_collection.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	if config.ModulePath == "" {
+		config.ModulePath = config.Project.Module
 	}
 
 	return &FeatureGenerator{
 		Config:                   config,
 		OutputDir:                outputDir,
+		Output:                   DirOutput(outputDir),
 		DevMode:                  devMode,
 		Template:                 tmpl,
 		RepoInterfaceTemplate:    repoInterfaceTmpl,
 		ServiceInterfaceTemplate: serviceInterfaceTmpl,
 		SQLiteRepoTemplate:       sqliteRepoTmpl,
 		SQLiteQueriesTemplate:    sqliteQueriesTmpl,
+		SQLiteMigrationTemplate:  sqliteMigrationTmpl,
 		MongoRepoTemplate:        mongoRepoTmpl,
 		HandlerTemplate:          handlerTmpl,
 		ValidatorTemplate:        validatorTmpl,
+		FeatureTemplate:          featureTmpl,
+		PageTemplate:             pageTmpl,
 		MainTemplate:             mainTmpl,
+		GoModTemplate:            goModTmpl,
 		ConfigTemplate:           configTmpl,
 		ConfigYAMLTemplate:       configYAMLTmpl,
 		XParamsTemplate:          xparamsTmpl,
@@ -174,89 +481,914 @@ func NewFeatureGenerator(config Config, outputDir string, devMode bool, assetsFS
 	}, nil
 }
 
+// Plan renders the whole project in memory, project files then every feat,
+// and returns the changes needed to bring OutputDir in line with it. Nothing
+// is written.
+func (fg *FeatureGenerator) Plan() (*Plan, error) {
+	manifest, err := LoadManifest(fg.Output)
+	if err != nil {
+		return nil, err
+	}
+	fg.plan = &Plan{Dir: fg.OutputDir, Warnings: slices.Clone(fg.Warnings), out: fg.Output, manifest: manifest}
+	fg.generated = map[string]bool{}
+
+	if err := fg.GenerateProject(); err != nil {
+		return nil, err
+	}
+	if err := fg.GenerateModels(); err != nil {
+		return nil, err
+	}
+	if err := fg.GenerateFeatures(); err != nil {
+		return nil, err
+	}
+	if err := fg.GeneratePlugins(); err != nil {
+		return nil, err
+	}
+	if err := fg.prune(); err != nil {
+		return nil, err
+	}
+	return fg.plan, nil
+}
+
+// Generate plans and applies the whole project, printing what changed.
+// Hand edits are never overwritten unless Force is set: they are reported as
+// conflicts, with the new content written next to the file, and Generate
+// returns an error.
+func (fg *FeatureGenerator) Generate() error {
+	plan, err := fg.Plan()
+	if err != nil {
+		return err
+	}
+	if err := plan.Apply(); err != nil {
+		return err
+	}
+	if err := plan.WriteSummary(os.Stdout); err != nil {
+		return err
+	}
+	return plan.Err()
+}
+
+// GenerateProject renders go.mod, main.go, Makefile and the platform package.
+func (fg *FeatureGenerator) GenerateProject() error {
+	cfg := fg.Config
+	engines := fg.engines()
+	requires := append([]ModuleRequire{}, generatedRequires...)
+	for _, e := range allowedEngines {
+		if engines[e] {
+			requires = append(requires, engineRequires[e])
+		}
+	}
+	goMod := GoModTemplateData{
+		ModulePath: cfg.ModulePath,
+		GoVersion:  "1.22",
+		Requires:   requires,
+	}
+	// Prod pins a runtime release; dev builds against the local checkout.
+	if fg.DevMode {
+		replace, err := fg.replacePath()
+		if err != nil {
+			return err
+		}
+		goMod.ReplacePath = replace
+	} else {
+		requires[0].Version = runtimeVersion()
+	}
+	var buf bytes.Buffer
+	if err := fg.GoModTemplate.Execute(&buf, goMod); err != nil {
+		return &TemplateError{Template: fg.GoModTemplate.Name(), File: goModFile, Err: err}
+	}
+	if err := fg.writeGoMod(buf.Bytes()); err != nil {
+		return err
+	}
+
+	project := ProjectTemplateData{
+		ProjectName: cfg.Project.Name,
+		ModulePath:  cfg.ModulePath,
+		APIHost:     cfg.Runtime.HTTP.API.Host,
+		APIPort:     cfg.Runtime.HTTP.API.Port,
+		WebHost:     cfg.Runtime.HTTP.Web.Host,
+		WebPort:     cfg.Runtime.HTTP.Web.Port,
+		Engine:      cfg.Runtime.Database.Engine,
+		DSN:         fg.dsn(),
+	}
+	files := []struct {
+		tmpl *template.Template
+		path string
+	}{
+		{fg.MakefileTemplate, "Makefile"},
+		{fg.ConfigTemplate, "internal/platform/config.go"},
+		{fg.XParamsTemplate, "internal/platform/xparams.go"},
+		{fg.ConfigYAMLTemplate, "assets/config/config.yaml"},
+	}
+	for _, f := range files {
+		if err := fg.render("", f.tmpl, project, f.path); err != nil {
+			return err
+		}
+	}
+	main, err := fg.mainData()
+	if err != nil {
+		return err
+	}
+	if err := fg.render("", fg.MainTemplate, main, "main.go"); err != nil {
+		return err
+	}
+	return fg.write("", "assets/README.md", []byte("# assets\n\nCentralized assets for the generated app.\n"))
+}
+
 func (fg *FeatureGenerator) GenerateModels() error {
-	for featName, feat := range fg.Config.Feats {
-		for modelName, model := range feat.Models {
-			if model.Fields == nil {
-				model.Fields = make(map[string]Field)
+	for _, featName := range fg.scopedFeats() {
+		feat := fg.Config.Feats[featName]
+		children := childModels(feat)
+		for _, modelName := range declared(fg.Config.Source, "feats."+featName+".models", feat.Models) {
+			data := fg.modelData(featName, feat, modelName)
+			// Individual model files: user.go, user_repo.go, user_handler.go, etc...
+			base := toSnakeCase(modelName)
+			files := []renderJob{
+				{fg.Template, base + ".go", data},
+				{fg.ValidatorTemplate, base + "_validator.go", data},
 			}
-			fmt.Printf("  - Generating model: %s/%s\n", featName, modelName)
-
-			packageName := featName
-			// Individual model files: user.go, list.go, order.go, etc...
-			modelFileName := strings.ToLower(modelName) + ".go"
-			// Path: internal/feat/{featName}/{model}.go
-			modelPath := filepath.Join(fg.OutputDir, "internal", "feat", featName, modelFileName)
-
-			data := ModelTemplateData{
-				PackageName: packageName,
-				ModelName:   modelName,
-				Audit:       false,
-				Fields:      []FieldTemplateData{},
+			// Children are stored and served through their aggregate roots.
+			engines := fg.featEngines(feat)
+			if children[modelName] {
+				engines = nil
+			} else {
+				files = append(files,
+					renderJob{fg.RepoInterfaceTemplate, base + "_repo.go", data},
+					renderJob{fg.HandlerTemplate, base + "_handler.go", fg.handlerData(data, feat)},
+				)
 			}
-			if model.Options != nil {
-				data.Audit = model.Options.Audit
+			if data.Aggregate != nil {
+				files = append(files, renderJob{fg.AggregateRootTemplate, base + "_aggregate.go", data})
+				for _, c := range data.Aggregate.Children {
+					files = append(files, renderJob{fg.ChildCollectionTemplate, base + "_" + toSnakeCase(c.Name) + "_collection.go", c})
+				}
 			}
-
-			var needsFmt bool
-			var needsStrconv bool
-
-			for fieldName, field := range model.Fields {
-				goType := mapGoType(field.Type)
-				fieldData := FieldTemplateData{
-					Name:        capitalizeFirst(fieldName),
-					Type:        goType,
-					JSONTag:     toSnakeCase(fieldName),
-					IsID:        false,
-					Validations: []FieldValidationData{},
+			for _, engine := range engines {
+				switch engine {
+				case "sqlite":
+					files = append(files,
+						renderJob{fg.SQLiteQueriesTemplate, base + "_queries_sqlite.go", data},
+						renderJob{fg.SQLiteRepoTemplate, base + "_repo_sqlite.go", data},
+					)
+				case "mongodb":
+					files = append(files, renderJob{fg.MongoRepoTemplate, base + "_repo_mongo.go", data})
 				}
+			}
 
-				for _, v := range field.Validations {
-					fieldData.Validations = append(fieldData.Validations, FieldValidationData{
-						Name:  v.Name,
-						Value: v.Value,
-					})
-					switch v.Name {
-					case "min_length", "max_length", "min", "max":
-						needsFmt = true
-						needsStrconv = true
-					}
+			for _, f := range files {
+				// Path: internal/feat/{featName}/{file}
+				if err := fg.render(featName, f.tmpl, f.data, filepath.Join("internal", "feat", featName, f.name)); err != nil {
+					return fmt.Errorf("cannot generate model %s: %w", modelName, err)
 				}
-				data.Fields = append(data.Fields, fieldData)
 			}
+		}
+	}
+	return nil
+}
 
-			data.NeedsFmt = needsFmt
-			data.NeedsStrconv = needsStrconv
+// GenerateFeatures renders the feat-level files: feature wiring, service,
+// migrations and web pages.
+func (fg *FeatureGenerator) GenerateFeatures() error {
+	for _, featName := range fg.scopedFeats() {
+		feat := fg.Config.Feats[featName]
+		data := fg.featureData(featName, feat)
+		dir := filepath.Join("internal", "feat", featName)
 
-			dir := filepath.Dir(modelPath)
-			if err := os.MkdirAll(dir, 0o755); err != nil {
-				return fmt.Errorf("cannot create directory for model %s: %w", modelName, err)
+		if err := fg.render(featName, fg.FeatureTemplate, data, filepath.Join(dir, "feature.go")); err != nil {
+			return err
+		}
+		if data.HasService {
+			if err := fg.render(featName, fg.ServiceInterfaceTemplate, data, filepath.Join(dir, "service.go")); err != nil {
+				return err
 			}
-
-			file, err := os.Create(modelPath)
-			if err != nil {
-				return fmt.Errorf("cannot create model file %s: %w", modelPath, err)
+		}
+		if fg.usesEngine(feat, "sqlite") {
+			if err := fg.sqliteMigrations(featName, data); err != nil {
+				return err
 			}
-			defer file.Close()
+		}
+		for _, page := range data.Pages {
+			if err := fg.render(featName, fg.PageTemplate, page, page.Template); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
 
-			if err := fg.Template.Execute(file, data); err != nil {
-				return fmt.Errorf("cannot execute model template for %s: %w", modelName, err)
+// renderJob is a single template rendering into a file.
+type renderJob struct {
+	tmpl *template.Template
+	name string
+	data any
+}
+
+// render executes tmpl with data and writes the result to rel under OutputDir.
+// feat names the feat owning the file, empty for project files.
+func (fg *FeatureGenerator) render(feat string, tmpl *template.Template, data any, rel string) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return &TemplateError{Template: tmpl.Name(), File: rel, Err: err}
+	}
+	generated := buf.Bytes()
+	if strings.HasSuffix(rel, ".go") {
+		formatted, err := formatGo(generated, runtimeModule, fg.Config.ModulePath)
+		if err != nil {
+			return &TemplateError{Template: tmpl.Name(), File: rel, Err: fmt.Errorf("invalid Go: %w", err)}
+		}
+		generated = formatted
+	}
+	return fg.write(feat, rel, generated)
+}
+
+// write plans bringing rel under OutputDir in line with generated,
+// preserving user regions and hand edits, and records the outcome in the
+// manifest.
+func (fg *FeatureGenerator) write(feat, rel string, generated []byte) error {
+	key := filepath.ToSlash(rel)
+	if fg.plan.manifest.skips(key) {
+		return nil
+	}
+	fg.generated[key] = true
+
+	existing, exists, err := fg.read(key)
+	if err != nil {
+		return err
+	}
+	entry, tracked := fg.plan.manifest.Files[key]
+	action, content := reconcile(entry, tracked, existing, exists, generated)
+	if _, pending, _ := fg.read(key + ConflictSuffix); action == FileKept && pending {
+		// Still unresolved from an earlier run.
+		action, content = FileConflict, mergeRegions(generated, existing)
+	}
+	forced := fg.Force && action == FileConflict
+	if forced {
+		action = FileUpdated
+	}
+	if action != FileKept {
+		fg.plan.manifest.Files[key] = manifestEntry(feat, generated)
+	}
+	fg.plan.Changes = append(fg.plan.Changes, Change{Path: key, Action: action, Old: existing, New: content, Forced: forced})
+	return nil
+}
+
+// prune plans deleting files generated by an earlier run that the spec no
+// longer produces. Files edited since are left in place and reported as
+// orphaned, unless Force is set. A scoped run only prunes files of its feats.
+func (fg *FeatureGenerator) prune() error {
+	manifest := fg.plan.manifest
+	for _, key := range sortedKeys(manifest.Files) {
+		entry := manifest.Files[key]
+		if fg.generated[key] || (fg.Scope != nil && !fg.inScope(entry.Feat)) {
+			continue
+		}
+		delete(manifest.Files, key)
+		existing, exists, err := fg.read(key)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		change := Change{Path: key, Action: FileRemoved, Old: existing}
+		if !untouched(entry, existing) {
+			if fg.Force {
+				change.Forced = true
+			} else {
+				change.Action = FileOrphaned
 			}
-			fmt.Printf("    - Created %s\n", modelPath)
 		}
+		fg.plan.Changes = append(fg.plan.Changes, change)
 	}
 	return nil
 }
 
-var (
-	matchFirstCap = regexp.MustCompile("(.)([A-Z][a-z]+)")
-	matchAllCap   = regexp.MustCompile("([a-z0-9])([A-Z])")
-)
+// scopedFeats lists the feats to render in declaration order.
+func (fg *FeatureGenerator) scopedFeats() []string {
+	var names []string
+	for _, name := range declared(fg.Config.Source, "feats", fg.Config.Feats) {
+		if fg.Scope == nil || fg.inScope(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
 
-func toSnakeCase(str string) string {
-	snake := matchFirstCap.ReplaceAllString(str, "${1}_${2}")
-	snake = matchAllCap.ReplaceAllString(snake, "${1}_${2}")
-	return strings.ToLower(snake)
+func (fg *FeatureGenerator) inScope(feat string) bool {
+	for _, s := range fg.Scope {
+		if s == feat {
+			return true
+		}
+	}
+	return false
+}
+
+// read returns the content of key in the output, reporting whether it exists.
+func (fg *FeatureGenerator) read(key string) ([]byte, bool, error) {
+	b, err := fg.Output.ReadFile(key)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return b, true, nil
+}
+
+func parseTemplate(templates fs.FS, name string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).ParseFS(templates, name)
+	if err != nil {
+		return nil, &TemplateError{Template: name, Err: err}
+	}
+	return tmpl, nil
+}
+
+// modelData returns the template data of a model, with the repo methods its
+// relations add.
+func (fg *FeatureGenerator) modelData(featName string, feat Feature, modelName string) ModelTemplateData {
+	data := fg.modelFields(featName, feat, modelName)
+	fks, joins := featLinks(fg.Config.Source, "feats."+featName, feat)
+	for _, fk := range fks {
+		if fk.Model != modelName {
+			continue
+		}
+		method := "List" + data.ModelPlural + "For" + pascal(fk.Name)
+		data.Lookups = append(data.Lookups, LookupData{Method: method, Const: camel(method) + "SQL", Column: toSnakeCase(fk.field())})
+	}
+	for _, jt := range joins {
+		if jt.Owner != modelName {
+			continue
+		}
+		target := fg.modelFields(featName, feat, jt.Target)
+		one := pascal(singularize(jt.Name))
+		l := LinkData{
+			Target:        jt.Target,
+			TargetTable:   target.TableName,
+			TargetColumns: target.Columns,
+			Table:         jt.table(),
+			OwnerColumn:   jt.ownerColumn(),
+			TargetColumn:  jt.targetColumn(),
+			DocField:      toSnakeCase(singularize(jt.Name)) + "_ids",
+			List:          "List" + pascal(jt.Name) + "For" + modelName,
+			Assign:        "Assign" + one,
+			Unassign:      "Unassign" + one,
+		}
+		l.ListConst = camel(l.List) + "SQL"
+		l.AssignConst = data.VarName + l.Assign + "SQL"
+		l.UnassignConst = data.VarName + l.Unassign + "SQL"
+		data.Links = append(data.Links, l)
+	}
+	if agg, ok := feat.Aggregates[modelName]; ok {
+		version := versionField(agg)
+		data.Aggregate = &AggregateData{Version: pascal(version), VersionColumn: toSnakeCase(version)}
+		for _, c := range featChildren(feat) {
+			if c.Root != modelName {
+				continue
+			}
+			field := pascal(c.Name)
+			data.Aggregate.Children = append(data.Aggregate.Children, ChildData{
+				Name:       c.Name,
+				Field:      field,
+				One:        pascal(singularize(c.Name)),
+				JSONTag:    toSnakeCase(c.Name),
+				Root:       modelName,
+				Model:      fg.modelFields(featName, feat, c.Of),
+				Table:      c.table(),
+				RootColumn: c.rootColumn(),
+				Const:      data.VarName + field,
+			})
+		}
+	}
+	return data
+}
+
+// modelFields returns the template data of a model and its fields, the
+// foreign keys of its relations included.
+func (fg *FeatureGenerator) modelFields(featName string, feat Feature, modelName string) ModelTemplateData {
+	model := feat.Models[modelName]
+	data := ModelTemplateData{
+		PackageName: featName,
+		ModulePath:  fg.Config.ModulePath,
+		ModelName:   modelName,
+		ModelPlural: pluralize(modelName),
+		VarName:     camel(modelName),
+		TableName:   toSnakeCase(pluralize(modelName)),
+		Fields:      []FieldTemplateData{},
+	}
+	if model.Options != nil {
+		data.Audit = model.Options.Audit
+	}
+	agg, isRoot := feat.Aggregates[modelName]
+	if isRoot {
+		data.Audit = data.Audit || agg.Audit
+	}
+	for _, c := range featChildren(feat) {
+		if c.Of == modelName && feat.Aggregates[c.Root].Children[c.Name].Audit {
+			data.Audit = true
+		}
+	}
+
+	data.ID = fg.fieldData(modelName, "id", Field{Type: "uuid"})
+	data.ID.IsID = true
+	var fields []FieldTemplateData
+	for _, fieldName := range declared(fg.Config.Source, "feats."+featName+".models."+modelName+".fields", model.Fields) {
+		if fieldName != "id" {
+			fields = append(fields, fg.fieldData(modelName, fieldName, model.Fields[fieldName]))
+		}
+	}
+	if isRoot {
+		for _, fieldName := range declared(fg.Config.Source, "feats."+featName+".aggregates."+modelName+".fields", agg.Fields) {
+			fields = append(fields, fg.fieldData(modelName, fieldName, agg.Fields[fieldName]))
+		}
+	}
+	fks, _ := featLinks(fg.Config.Source, "feats."+featName, feat)
+	for _, fk := range fks {
+		if fk.Model != modelName {
+			continue
+		}
+		key := Field{Type: "uuid", Nullable: fk.Nullable}
+		if !fk.Nullable {
+			key.Validations = []Validation{{Name: "required"}}
+		}
+		fieldData := fg.fieldData(modelName, fk.field(), key)
+		fieldData.References = toSnakeCase(pluralize(fk.Target))
+		fields = append(fields, fieldData)
+	}
+	for _, fieldData := range fields {
+		for _, v := range fieldData.Validations {
+			if v.Name == "pattern" {
+				data.NeedsRegexp = true
+			}
+		}
+		if strings.Contains(fieldData.Type+fieldData.Default, "time.") {
+			data.NeedsTime = true
+		}
+		if strings.Contains(fieldData.Type+fieldData.Default, "am.") {
+			data.NeedsAM = true
+		}
+		if fieldData.Enum != nil {
+			data.Enums = append(data.Enums, *fieldData.Enum)
+		}
+		data.Fields = append(data.Fields, fieldData)
+	}
+	if data.Audit {
+		data.NeedsTime, data.NeedsAM = true, true
+	}
+
+	data.Columns = []ColumnData{{Column: data.ID.Column, Field: data.ID.Name}}
+	for _, f := range data.Fields {
+		data.Columns = append(data.Columns, ColumnData{Column: f.Column, Field: f.Name})
+		data.UpdateColumns = append(data.UpdateColumns, ColumnData{Column: f.Column, Field: f.Name})
+	}
+	if isRoot {
+		version := versionField(agg)
+		data.Columns = append(data.Columns, ColumnData{Column: toSnakeCase(version), Field: pascal(version)})
+	}
+	if data.Audit {
+		data.Columns = append(data.Columns,
+			ColumnData{Column: "created_at", Field: "CreatedAt"},
+			ColumnData{Column: "updated_at", Field: "UpdatedAt"},
+			ColumnData{Column: "created_by", Field: "CreatedBy"},
+			ColumnData{Column: "updated_by", Field: "UpdatedBy"},
+		)
+		data.UpdateColumns = append(data.UpdateColumns,
+			ColumnData{Column: "updated_at", Field: "UpdatedAt"},
+			ColumnData{Column: "updated_by", Field: "UpdatedBy"},
+		)
+	}
+	return data
+}
+
+func (fg *FeatureGenerator) fieldData(modelName, fieldName string, field Field) FieldTemplateData {
+	enum := enumType(modelName, fieldName)
+	fieldData := FieldTemplateData{
+		Name:        pascal(fieldName),
+		Type:        goFieldType(field, enum),
+		JSONTag:     toSnakeCase(fieldName),
+		Column:      toSnakeCase(fieldName),
+		SQLType:     columnType("sqlite", field.Type),
+		Default:     goDefault(field, enum),
+		Spec:        field,
+		Validations: []FieldValidationData{},
+	}
+	if field.Type == "enum" {
+		fieldData.Enum = &EnumTemplateData{Type: enum, Field: fieldData.JSONTag}
+		for _, v := range field.Values {
+			fieldData.Enum.Values = append(fieldData.Enum.Values, EnumValueData{Const: enumConst(enum, v), Value: v})
+		}
+	}
+	for _, v := range field.Validations {
+		if v.Name == "unique" {
+			fieldData.Unique = true
+			continue
+		}
+		check, msg := validationCheck(modelName, fieldData.Name, field, v)
+		if check == "" {
+			continue
+		}
+		vd := FieldValidationData{
+			Name:    v.Name,
+			Value:   v.Value,
+			Check:   check,
+			Message: msg,
+		}
+		if v.Name == "pattern" {
+			vd.Var = patternVar(modelName, fieldData.Name)
+		}
+		fieldData.Validations = append(fieldData.Validations, vd)
+	}
+	if field.Type == "enum" {
+		// Enums always check their value: JSON decodes any string into them.
+		v := Validation{Name: "oneof", Value: strings.Join(field.Values, ", ")}
+		check, msg := validationCheck(modelName, fieldData.Name, field, v)
+		fieldData.Validations = append(fieldData.Validations, FieldValidationData{Name: v.Name, Value: v.Value, Check: check, Message: msg})
+	}
+	return fieldData
+}
+
+// validationCheck returns the Go expression enforcing v on field name of
+// modelName, and the message reported when it does not hold. Rules on a
+// nullable field hold when it is null, except required.
+func validationCheck(modelName, name string, field Field, v Validation) (string, string) {
+	ref := "m." + name
+	if field.Nullable {
+		if v.Name == "required" {
+			return ref + " != nil", "is required"
+		}
+		ref = "*" + ref
+	}
+	check, msg := valueCheck(modelName, name, ref, field, v)
+	if check != "" && field.Nullable {
+		check = fmt.Sprintf("m.%s == nil || %s", name, check)
+	}
+	return check, msg
+}
+
+// valueCheck is validationCheck on the value ref of a field; methods are
+// called on the field itself, which works for pointers too.
+func valueCheck(modelName, name, ref string, field Field, v Validation) (string, string) {
+	t, _ := lookupFieldType(field.Type)
+	switch v.Name {
+	case "required":
+		switch t.class {
+		case classString:
+			return fmt.Sprintf("am.IsRequired(%s)", ref), "is required"
+		case classUUID:
+			return fmt.Sprintf("am.IsRequiredUUID(%s)", ref), "is required"
+		case classNumber:
+			return fmt.Sprintf("%s != 0", ref), "is required"
+		case classDecimal, classDate, classJSON, classEnum:
+			return fmt.Sprintf("%s != \"\"", ref), "is required"
+		case classTime:
+			return fmt.Sprintf("!m.%s.IsZero()", name), "is required"
+		case classBytes, classList:
+			return fmt.Sprintf("len(%s) > 0", ref), "is required"
+		}
+	case "email":
+		return fmt.Sprintf("am.IsEmail(%s)", ref), "must be a valid email address"
+	case "min", "min_length":
+		switch {
+		case t.class == classNumber:
+			return fmt.Sprintf("%s >= %s", ref, v.Value), "must be at least " + v.Value
+		case t.class == classDecimal:
+			return fmt.Sprintf("m.%s.Cmp(%q) >= 0", name, v.Value), "must be at least " + v.Value
+		case t.class == classList:
+			return fmt.Sprintf("len(%s) >= %s", ref, v.Value), "must have at least " + v.Value + " items"
+		}
+		return fmt.Sprintf("am.MinLength(%s, %s)", ref, v.Value), "must be at least " + v.Value + " characters"
+	case "max", "max_length":
+		switch {
+		case t.class == classNumber:
+			return fmt.Sprintf("%s <= %s", ref, v.Value), "must be at most " + v.Value
+		case t.class == classDecimal:
+			return fmt.Sprintf("m.%s.Cmp(%q) <= 0", name, v.Value), "must be at most " + v.Value
+		case t.class == classList:
+			return fmt.Sprintf("len(%s) <= %s", ref, v.Value), "must have at most " + v.Value + " items"
+		}
+		return fmt.Sprintf("am.MaxLength(%s, %s)", ref, v.Value), "must be at most " + v.Value + " characters"
+	case "pattern":
+		return fmt.Sprintf("%s.MatchString(%s)", patternVar(modelName, name), ref), "has an invalid format"
+	case "oneof":
+		return fmt.Sprintf("m.%s.Valid()", name), "must be one of " + v.Value
+	}
+	return "", ""
+}
+
+func patternVar(modelName, fieldName string) string {
+	return camel(modelName) + fieldName + "Pattern"
+}
+
+func (fg *FeatureGenerator) handlerData(m ModelTemplateData, feat Feature) HandlerTemplateData {
+	return HandlerTemplateData{
+		PackageName:      m.PackageName,
+		ModelName:        m.ModelName,
+		ModelPlural:      m.ModelPlural,
+		ModelLower:       camel(m.ModelName),
+		ModelPluralLower: toSnakeCase(m.ModelPlural),
+		IDField:          m.ID.Name,
+		AuthEnabled:      feat.Auth != nil && feat.Auth.Enabled,
+		Audit:            m.Audit,
+		ModulePath:       m.ModulePath,
+		Aggregate:        m.Aggregate != nil,
+	}
+}
+
+func (fg *FeatureGenerator) featureData(featName string, feat Feature) FeatureTemplateData {
+	data := FeatureTemplateData{
+		PackageName: featName,
+		ModulePath:  fg.Config.ModulePath,
+		Kind:        feat.Kind,
+		AuthEnabled: feat.Auth != nil && feat.Auth.Enabled,
+	}
+	children := childModels(feat)
+	for _, modelName := range declared(fg.Config.Source, "feats."+featName+".models", feat.Models) {
+		if children[modelName] {
+			continue
+		}
+		m := fg.modelData(featName, feat, modelName)
+		data.HasAggregates = data.HasAggregates || m.Aggregate != nil
+		data.Models = append(data.Models, m)
+	}
+	_, joins := featLinks(fg.Config.Source, "feats."+featName, feat)
+	for _, jt := range joins {
+		data.JoinTables = append(data.JoinTables, JoinTableData{
+			Table:        jt.table(),
+			OwnerTable:   toSnakeCase(pluralize(jt.Owner)),
+			OwnerColumn:  jt.ownerColumn(),
+			TargetTable:  toSnakeCase(pluralize(jt.Target)),
+			TargetColumn: jt.targetColumn(),
+		})
+	}
+	if feat.Kind == "atom" && len(data.Models) == 1 {
+		data.Atom = &data.Models[0]
+	}
+
+	seen := map[string]bool{}
+	for _, m := range feat.Service.Methods {
+		seen[m] = true
+		data.Methods = append(data.Methods, m)
+	}
+	actions := map[string]int{}
+	for _, r := range feat.API.Routes {
+		if !seen[r.Handler] {
+			seen[r.Handler] = true
+			data.Methods = append(data.Methods, r.Handler)
+		}
+		route := RouteTemplateData{
+			Method:  chiMethod(r.Method),
+			Path:    r.Path,
+			Handler: r.Handler,
+			HasBody: r.Method == "POST" || r.Method == "PUT" || r.Method == "PATCH",
+		}
+		data.Routes = append(data.Routes, route)
+
+		i, ok := actions[r.Handler]
+		if !ok {
+			i = len(data.Actions)
+			actions[r.Handler] = i
+			data.Actions = append(data.Actions, ActionTemplateData{Name: r.Handler})
+		}
+		if route.HasBody {
+			data.Actions[i].HasBody = true
+			data.HasBodyActions = true
+		}
+	}
+	for _, r := range fg.Config.validRefs() {
+		if r.Feat != featName {
+			continue
+		}
+		i := slices.IndexFunc(data.Ports, func(p PortData) bool { return p.Type == r.port() })
+		if i < 0 {
+			i = len(data.Ports)
+			data.Ports = append(data.Ports, PortData{Type: r.port(), Feat: r.Owner, Model: r.Target, Method: r.method()})
+		}
+		data.Ports[i].Fields = append(data.Ports[i].Fields, r.Model+"."+r.Field)
+	}
+	for _, model := range fg.Config.referenced(featName) {
+		data.Exposed = append(data.Exposed, ExposedData{Model: model, Method: modelRef{Target: model}.method()})
+	}
+	data.HasService = len(data.Methods) > 0 || data.Atom != nil || len(data.Exposed) > 0
+	data.HasAPI = len(data.Models) > 0 || len(data.Routes) > 0
+
+	used := map[string]bool{}
+	for _, p := range feat.Web.Pages {
+		method, path, _ := strings.Cut(p.Route, " ")
+		name := pageName(method, path)
+		data.Pages = append(data.Pages, PageTemplateData{
+			Method:   chiMethod(method),
+			Path:     path,
+			Name:     name,
+			Func:     "page" + pascal(name),
+			Title:    pascal(name),
+			Template: filepath.ToSlash(filepath.Join("assets", "templates", featName, name+".html")),
+			Uses:     p.Uses,
+		})
+		for _, u := range p.Uses {
+			if used[u] || !fg.hasService(u) {
+				continue
+			}
+			used[u] = true
+			data.Uses = append(data.Uses, UseTemplateData{
+				Feat:   u,
+				Field:  pascal(u),
+				Import: fg.Config.ModulePath + "/internal/feat/" + u,
+			})
+		}
+	}
+	return data
+}
+
+func (fg *FeatureGenerator) hasService(featName string) bool {
+	feat, ok := fg.Config.Feats[featName]
+	if !ok {
+		return false
+	}
+	return len(feat.Service.Methods) > 0 || len(feat.API.Routes) > 0 ||
+		(feat.Kind == "atom" && len(feat.Models)-len(childModels(feat)) == 1) ||
+		len(fg.Config.referenced(featName)) > 0
+}
+
+// mainImportNames are identifiers main.go already uses; feat imports that
+// would shadow them get a "feat" suffix.
+var mainImportNames = map[string]bool{
+	"context": true, "embed": true, "log": true, "http": true, "signal": true, "syscall": true,
+	"sql": true, "chi": true, "am": true, "platform": true, "mongo": true, "options": true, "main": true,
+}
+
+// mainData lists the feats in dependency order, so main.go builds, migrates,
+// seeds and sets up every feat after those it requires or uses.
+func (fg *FeatureGenerator) mainData() (MainTemplateData, error) {
+	data := MainTemplateData{
+		ModulePath: fg.Config.ModulePath,
+		Engine:     fg.Config.Runtime.Database.Engine,
+	}
+	order, err := fg.Config.FeatOrder()
+	if err != nil {
+		return data, err
+	}
+	for _, featName := range order {
+		feat := fg.Config.Feats[featName]
+		if len(feat.Models) > 0 {
+			data.DB = true
+			if data.Engine == "sqlite" {
+				data.Migrations = append(data.Migrations, featName)
+			}
+		}
+		alias := featName
+		if mainImportNames[alias] {
+			alias += "feat"
+		}
+		mf := MainFeatData{
+			Name:    featName,
+			Alias:   alias,
+			Var:     camel(featName) + "Feat",
+			Assets:  len(feat.Web.Pages) > 0,
+			Service: fg.hasService(featName),
+		}
+		children := childModels(feat)
+		for _, modelName := range declared(fg.Config.Source, "feats."+featName+".models", feat.Models) {
+			if children[modelName] {
+				continue
+			}
+			ctor := "New" + modelName + "SQLiteRepo(db)"
+			if data.Engine == "mongodb" {
+				ctor = "New" + modelName + "MongoRepo(db)"
+			}
+			mf.Repos = append(mf.Repos, MainRepoData{Field: modelName + "Repo", Constructor: ctor})
+		}
+		fd := fg.featureData(featName, feat)
+		for _, u := range fd.Uses {
+			mf.Uses = append(mf.Uses, MainUseData{Field: u.Field, Var: camel(u.Feat) + "Feat"})
+		}
+		for _, p := range fd.Ports {
+			mf.Uses = append(mf.Uses, MainUseData{Field: p.Type, Var: camel(p.Feat) + "Feat"})
+		}
+		data.Feats = append(data.Feats, mf)
+	}
+	return data, nil
+}
+
+// engines returns the set of storage engines used by any feat.
+func (fg *FeatureGenerator) engines() map[string]bool {
+	out := map[string]bool{}
+	for _, feat := range fg.Config.Feats {
+		if len(feat.Models) == 0 {
+			continue
+		}
+		for _, e := range fg.featEngines(feat) {
+			out[e] = true
+		}
+	}
+	return out
+}
+
+// featEngines returns the repository implementations generated for feat:
+// the runtime engine plus any listed in repo_impl.
+func (fg *FeatureGenerator) featEngines(feat Feature) []string {
+	engines := []string{fg.Config.Runtime.Database.Engine}
+	for _, e := range feat.RepoImpl {
+		if e != engines[0] {
+			engines = append(engines, e)
+		}
+	}
+	return engines
+}
+
+func (fg *FeatureGenerator) usesEngine(feat Feature, engine string) bool {
+	for _, e := range fg.featEngines(feat) {
+		if e == engine {
+			return true
+		}
+	}
+	return false
+}
+
+// replacePath points the generated go.mod at the local aquamarine checkout,
+// relative to the output dir.
+func (fg *FeatureGenerator) replacePath() (string, error) {
+	root := aquamarineModuleDir()
+	if root == "" {
+		return "", fmt.Errorf("dev mode builds against a local %s checkout, none found above the working dir or the binary; "+
+			"run from a checkout or generate without --dev", runtimeModule)
+	}
+	out, err := filepath.Abs(fg.OutputDir)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(out, root)
+	if err != nil {
+		return filepath.ToSlash(root), nil
+	}
+	rel = filepath.ToSlash(rel)
+	if !strings.HasPrefix(rel, ".") {
+		rel = "./" + rel // a replace target needs a path, not a module
+	}
+	return rel, nil
+}
+
+// runtimeVersion is the runtime release prod output requires: the version
+// of this binary when it is a tagged release, else RuntimeVersion.
+func runtimeVersion() string {
+	return releaseVersion(debug.ReadBuildInfo())
+}
+
+// releaseVersion returns the version of the main module of info when it is
+// a tagged release of aquamarine, else RuntimeVersion.
+func releaseVersion(info *debug.BuildInfo, ok bool) string {
+	if ok && info.Main.Path == runtimeModule && releaseRe.MatchString(info.Main.Version) {
+		return info.Main.Version
+	}
+	return RuntimeVersion
+}
+
+// aquamarineModuleDir looks for the aquamarine module source above the
+// working directory, then above the running binary.
+func aquamarineModuleDir() string {
+	var starts []string
+	if wd, err := os.Getwd(); err == nil {
+		starts = append(starts, wd)
+	}
+	if exe, err := os.Executable(); err == nil {
+		starts = append(starts, filepath.Dir(exe))
+	}
+	for _, dir := range starts {
+		for {
+			if modulePathOf(filepath.Join(dir, "go.mod")) == runtimeModule {
+				return dir
+			}
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				break
+			}
+			dir = parent
+		}
+	}
+	return ""
+}
+
+// modulePathOf returns the module declared by the go.mod at path, if any.
+func modulePathOf(path string) string {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
+
+func (fg *FeatureGenerator) dsn() string {
+	db := fg.Config.Runtime.Database
+	if db.DSN != "" {
+		return db.DSN
+	}
+	if db.Engine == "mongodb" {
+		return "mongodb://127.0.0.1:27017/" + fg.Config.Project.Name
+	}
+	return "file:" + fg.Config.Project.Name + ".db?_pragma=foreign_keys(1)"
 }
 
 func capitalizeFirst(str string) string {
@@ -266,22 +1398,25 @@ func capitalizeFirst(str string) string {
 	return strings.ToUpper(str[:1]) + str[1:]
 }
 
-// mapGoType maps YAML types to Go types.
-func mapGoType(yamlType string) string {
-	switch yamlType {
-	case "text", "string", "email":
-		return "string"
-	case "bool":
-		return "bool"
-	case "uuid":
-		return "uuid.UUID"
-	case "int":
-		return "int"
-	case "int64":
-		return "int64"
-	case "float64":
-		return "float64"
-	default:
-		return "any"
+func lowerFirst(str string) string {
+	if len(str) == 0 {
+		return str
 	}
+	return strings.ToLower(str[:1]) + str[1:]
+}
+
+// pageName derives a template name from a page route, e.g. "GET /" -> "index".
+func pageName(method, path string) string {
+	name := strings.Trim(regexp.MustCompile(`[^A-Za-z0-9]+`).ReplaceAllString(path, "_"), "_")
+	if name == "" {
+		name = "index"
+	}
+	if method != "GET" {
+		name = strings.ToLower(method) + "_" + name
+	}
+	return strings.ToLower(name)
+}
+
+func chiMethod(method string) string {
+	return capitalizeFirst(strings.ToLower(method))
 }
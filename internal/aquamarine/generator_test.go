@@ -0,0 +1,103 @@
+package aquamarine
+
+import (
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestMapGoType(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"text", "string"},
+		{"string", "string"},
+		{"email", "string"},
+		{"bool", "bool"},
+		{"uuid", "uuid.UUID"},
+		{"int", "int"},
+		{"int64", "int64"},
+		{"float64", "float64"},
+		{"decimal", "am.Decimal"},
+		{"date", "am.Date"},
+		{"datetime", "time.Time"},
+		{"bytes", "[]byte"},
+		{"json", "am.JSON"},
+		{"unknown", "any"},
+	}
+	for _, tt := range tests {
+		if got := mapGoType(tt.in); got != tt.want {
+			t.Errorf("mapGoType(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	for _, typ := range fieldTypes {
+		if mapGoType(typ) == "any" {
+			t.Errorf("field type %q has no Go type", typ)
+		}
+	}
+}
+
+func TestReleaseVersion(t *testing.T) {
+	build := func(path, version string) *debug.BuildInfo {
+		return &debug.BuildInfo{Main: debug.Module{Path: path, Version: version}}
+	}
+	tests := []struct {
+		name string
+		info *debug.BuildInfo
+		want string
+	}{
+		{"tagged release", build(runtimeModule, "v1.2.3"), "v1.2.3"},
+		{"built from a checkout", build(runtimeModule, "(devel)"), RuntimeVersion},
+		{"pseudo-version", build(runtimeModule, "v1.2.4-0.20240102150405-abcdef123456"), RuntimeVersion},
+		{"prerelease", build(runtimeModule, "v1.3.0-rc.1"), RuntimeVersion},
+		{"other main module", build("example.com/tool", "v1.2.3"), RuntimeVersion},
+	}
+	for _, tt := range tests {
+		if got := releaseVersion(tt.info, true); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if got := releaseVersion(nil, false); got != RuntimeVersion {
+		t.Errorf("no build info: got %q, want %q", got, RuntimeVersion)
+	}
+	if got := runtimeVersion(); got != RuntimeVersion {
+		t.Errorf("test binary: got %q, want %q", got, RuntimeVersion)
+	}
+}
+
+func TestReplacePath(t *testing.T) {
+	root := aquamarineModuleDir()
+	if root == "" {
+		t.Fatal("no aquamarine checkout above the test dir")
+	}
+	for out, want := range map[string]string{
+		root:                              ".",
+		filepath.Join(root, "out", "dev"): "../..",
+		filepath.Dir(root):                "./" + filepath.Base(root),
+		filepath.Join("testdata", "gen"):  "../../../..", // relative to the working dir
+		filepath.Join(root, "..", "sib"):  "../" + filepath.Base(root),
+	} {
+		fg := &FeatureGenerator{OutputDir: out}
+		got, err := fg.replacePath()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("output %s: got %q, want %q", out, got, want)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if _, err := (&FeatureGenerator{OutputDir: "out"}).replacePath(); err == nil || !strings.Contains(err.Error(), "--dev") {
+		t.Errorf("outside a checkout: got %v, want an error", err)
+	}
+}
@@ -0,0 +1,234 @@
+package aquamarine
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/aquamarinepk/aquamarine/assets"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden trees under testdata/golden")
+
+// buildModules are the modules this repo's go.mod provides, so generated
+// code importing them is type-checked against their source. Imports from
+// other modules (database drivers) are opaque: their use is not checked.
+var buildModules = []string{
+	runtimeModule,
+	"github.com/go-chi/chi/v5",
+	"github.com/google/uuid",
+	"gopkg.in/yaml.v3",
+}
+
+var errOpaque = errors.New("not provided by this module, left unchecked")
+
+// TestGolden renders every testdata/golden/<case>/aquamarine.yaml in memory,
+// compares the tree with testdata/golden/<case>/want and type-checks the
+// generated packages. Run with -update to accept the current output.
+func TestGolden(t *testing.T) {
+	specs, err := filepath.Glob("testdata/golden/*/" + DefaultSpecFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(specs) == 0 {
+		t.Fatal("no golden cases")
+	}
+	for _, spec := range specs {
+		dir := filepath.Dir(spec)
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			fg, tree := renderTree(t, spec)
+			want := filepath.Join(dir, "want")
+			if *update {
+				writeTree(t, want, tree)
+			}
+			compareTree(t, want, tree)
+			typeCheck(t, fg.Config.ModulePath, tree)
+		})
+	}
+}
+
+// renderTree plans a prod generate of spec into an empty in-memory output
+// and returns the files it would create.
+func renderTree(t *testing.T, spec string) (*FeatureGenerator, fstest.MapFS) {
+	t.Helper()
+	cfg, _, err := LoadSpec(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fg, err := NewFeatureGenerator(*cfg, "out", false, assets.Templates())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fg.Output = MapOutput{}
+	plan, err := fg.Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree := fstest.MapFS{}
+	for _, c := range plan.Changes {
+		if c.Action != FileCreated {
+			t.Fatalf("%s: %s in an empty output dir", c.Path, c.Action)
+		}
+		tree[c.Path] = &fstest.MapFile{Data: c.New}
+	}
+	return fg, tree
+}
+
+func writeTree(t *testing.T, dir string, tree fstest.MapFS) {
+	t.Helper()
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	for name, f := range tree {
+		if err := safeWriteFile(filepath.Join(dir, filepath.FromSlash(name)), f.Data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func compareTree(t *testing.T, dir string, tree fstest.MapFS) {
+	t.Helper()
+	want := map[string][]byte{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		want[filepath.ToSlash(rel)], err = os.ReadFile(p)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+
+	for _, name := range sortedKeys(want) {
+		got, ok := tree[name]
+		if !ok {
+			t.Errorf("%s: no longer generated", name)
+			continue
+		}
+		if !bytes.Equal(got.Data, want[name]) {
+			var diff strings.Builder
+			if err := unifiedDiff(&diff, "want/"+name, "got/"+name, want[name], got.Data); err != nil {
+				t.Fatal(err)
+			}
+			t.Errorf("%s differs from the golden file:\n%s", name, diff.String())
+		}
+	}
+	for _, name := range sortedKeys(tree) {
+		if _, ok := want[name]; !ok {
+			t.Errorf("%s: generated but not in the golden tree", name)
+		}
+	}
+}
+
+// typeCheck runs go/types over every package of the generated module.
+func typeCheck(t *testing.T, module string, tree fstest.MapFS) {
+	t.Helper()
+	im := &treeImporter{
+		fset:   token.NewFileSet(),
+		module: module,
+		tree:   tree,
+		pkgs:   map[string]*types.Package{},
+	}
+	im.ext = importer.ForCompiler(im.fset, "source", nil)
+
+	dirs := map[string]bool{}
+	for name := range tree {
+		if strings.HasSuffix(name, ".go") {
+			dirs[path.Dir(name)] = true
+		}
+	}
+	for _, dir := range sortedKeys(dirs) {
+		pkgPath := module
+		if dir != "." {
+			pkgPath += "/" + dir
+		}
+		if _, err := im.Import(pkgPath); err != nil {
+			t.Errorf("%s does not compile:\n%v", dir, err)
+		}
+	}
+}
+
+// treeImporter type-checks the generated packages from the in-memory tree
+// and everything else from source.
+type treeImporter struct {
+	fset   *token.FileSet
+	module string
+	tree   fstest.MapFS
+	pkgs   map[string]*types.Package
+	ext    types.Importer
+}
+
+func (im *treeImporter) Import(pkgPath string) (*types.Package, error) {
+	if pkg, ok := im.pkgs[pkgPath]; ok {
+		return pkg, nil
+	}
+	dir, ok := strings.CutPrefix(pkgPath, im.module+"/")
+	if pkgPath == im.module {
+		dir, ok = ".", true
+	}
+	if !ok {
+		if !inBuild(pkgPath) {
+			return nil, errOpaque
+		}
+		return im.ext.Import(pkgPath)
+	}
+
+	var files []*ast.File
+	for _, name := range sortedKeys(im.tree) {
+		if path.Dir(name) != dir || !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		f, err := parser.ParseFile(im.fset, name, im.tree[name].Data, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	var errs []string
+	conf := types.Config{
+		Importer: im,
+		Error: func(err error) {
+			if te, ok := err.(types.Error); ok && strings.Contains(te.Msg, errOpaque.Error()) {
+				return
+			}
+			errs = append(errs, err.Error())
+		},
+	}
+	pkg, _ := conf.Check(pkgPath, im.fset, files, nil)
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return nil, errors.New(strings.Join(errs, "\n"))
+	}
+	im.pkgs[pkgPath] = pkg
+	return pkg, nil
+}
+
+func inBuild(pkgPath string) bool {
+	if first, _, _ := strings.Cut(pkgPath, "/"); !strings.Contains(first, ".") {
+		return true // standard library
+	}
+	for _, m := range buildModules {
+		if pkgPath == m || strings.HasPrefix(pkgPath, m+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package aquamarine
+
+import (
+	"bytes"
+	"strings"
+)
+
+// goModFile is the generated go.mod. The generated Makefile runs go mod tidy
+// before every build, which rewrites it: rather than telling generated
+// content from hand edits by hash, generate merges what it needs into the
+// go.mod on disk.
+const goModFile = "go.mod"
+
+// writeGoMod plans go.mod: generated when there is none, else the go.mod on
+// disk with the module path, requirements and runtime replace of generated
+// merged into it. Everything else, such as the indirect requirements go mod
+// tidy adds, is kept.
+func (fg *FeatureGenerator) writeGoMod(generated []byte) error {
+	if fg.plan.manifest.skips(goModFile) {
+		return nil
+	}
+	existing, exists, err := fg.read(goModFile)
+	if err != nil {
+		return err
+	}
+	if exists {
+		// The go.mod on disk is what the merge starts from, not a hand
+		// edit to keep.
+		fg.plan.manifest.Files[goModFile] = manifestEntry("", existing)
+		generated = mergeGoMod(existing, generated)
+	}
+	return fg.write("", goModFile, generated)
+}
+
+// modFile is what generate puts in a go.mod.
+type modFile struct {
+	module   string            // the module line
+	requires map[string]string // by module path, the version
+	order    []string          // module paths of requires, in order
+	replace  string            // the runtime replace line, if any
+}
+
+func parseGoMod(b []byte) modFile {
+	m := modFile{requires: map[string]string{}}
+	inRequire := false
+	for _, line := range strings.Split(string(b), "\n") {
+		f := strings.Fields(line)
+		switch {
+		case len(f) == 0 || strings.HasPrefix(f[0], "//"):
+		case f[0] == "module":
+			m.module = line
+		case f[0] == "require" && len(f) == 2 && f[1] == "(":
+			inRequire = true
+		case f[0] == ")":
+			inRequire = false
+		case inRequire && len(f) >= 2, f[0] == "require" && len(f) >= 3:
+			if f[0] == "require" {
+				f = f[1:]
+			}
+			m.requires[f[0]] = f[1]
+			m.order = append(m.order, f[0])
+		case isRuntimeReplace(f):
+			m.replace = line
+		}
+	}
+	return m
+}
+
+// isRuntimeReplace reports whether the fields of a go.mod line replace the
+// aquamarine module.
+func isRuntimeReplace(f []string) bool {
+	return len(f) >= 3 && f[0] == "replace" && f[1] == runtimeModule
+}
+
+// mergeGoMod returns existing with the module line, required versions and
+// runtime replace of generated. Requirements missing from existing go to its
+// first require block; a replace generated no longer has is dropped.
+func mergeGoMod(existing, generated []byte) []byte {
+	gen := parseGoMod(generated)
+	seen := map[string]bool{}
+	var out []string
+	inRequire, firstBlock := false, -1
+	for _, line := range strings.Split(strings.TrimRight(string(existing), "\n"), "\n") {
+		f := strings.Fields(line)
+		switch {
+		case len(f) == 0 || strings.HasPrefix(f[0], "//"):
+		case f[0] == "module" && gen.module != "":
+			line = gen.module
+		case f[0] == "require" && len(f) == 2 && f[1] == "(":
+			inRequire = true
+		case f[0] == ")":
+			if inRequire && firstBlock < 0 {
+				firstBlock = len(out)
+			}
+			inRequire = false
+		case inRequire && len(f) >= 2, f[0] == "require" && len(f) >= 3:
+			path, version := f[0], f[1]
+			if f[0] == "require" {
+				path, version = f[1], f[2]
+			}
+			seen[path] = true
+			if v, ok := gen.requires[path]; ok && v != version {
+				line = strings.Replace(line, path+" "+version, path+" "+v, 1)
+			}
+		case isRuntimeReplace(f):
+			seen[runtimeModule+" =>"] = true
+			if gen.replace == "" {
+				if n := len(out); n > 0 && out[n-1] == "" {
+					out = out[:n-1]
+				}
+				continue
+			}
+			line = gen.replace
+		}
+		out = append(out, line)
+	}
+
+	var missing []string
+	for _, path := range gen.order {
+		if !seen[path] {
+			missing = append(missing, "\t"+path+" "+gen.requires[path])
+		}
+	}
+	if len(missing) > 0 {
+		if firstBlock < 0 {
+			out = append(out, "", "require (")
+			out = append(out, missing...)
+			out = append(out, ")")
+		} else {
+			out = append(out[:firstBlock], append(missing, out[firstBlock:]...)...)
+		}
+	}
+	if gen.replace != "" && !seen[runtimeModule+" =>"] {
+		out = append(out, "", gen.replace)
+	}
+	var b bytes.Buffer
+	for _, line := range out {
+		b.WriteString(line + "\n")
+	}
+	return b.Bytes()
+}
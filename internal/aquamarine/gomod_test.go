@@ -0,0 +1,135 @@
+package aquamarine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aquamarinepk/aquamarine/assets"
+)
+
+const tidied = `module example.com/shop
+
+go 1.22
+
+toolchain go1.22.7
+
+require (
+	github.com/aquamarinepk/aquamarine v0.1.0
+	github.com/go-chi/chi/v5 v5.1.0
+	github.com/google/uuid v1.6.0
+)
+
+require (
+	// keep in step with chi
+	github.com/dustin/go-humanize v1.0.1 // indirect
+	golang.org/x/sys v0.22.0 // indirect
+)
+`
+
+func TestMergeGoMod(t *testing.T) {
+	tests := []struct {
+		name, existing, generated, want string
+	}{
+		{"nothing new", tidied, `module example.com/shop
+
+go 1.22
+
+require (
+	github.com/aquamarinepk/aquamarine v0.1.0
+	github.com/go-chi/chi/v5 v5.1.0
+)
+`, tidied},
+		{"new and bumped requires", tidied, `module example.com/shop
+
+go 1.22
+
+require (
+	github.com/aquamarinepk/aquamarine v0.2.0
+	github.com/go-chi/chi/v5 v5.1.0
+	modernc.org/sqlite v1.33.1
+)
+`, `module example.com/shop
+
+go 1.22
+
+toolchain go1.22.7
+
+require (
+	github.com/aquamarinepk/aquamarine v0.2.0
+	github.com/go-chi/chi/v5 v5.1.0
+	github.com/google/uuid v1.6.0
+	modernc.org/sqlite v1.33.1
+)
+
+require (
+	// keep in step with chi
+	github.com/dustin/go-humanize v1.0.1 // indirect
+	golang.org/x/sys v0.22.0 // indirect
+)
+`},
+		{"dev replace added", "module example.com/shop\n\nrequire github.com/aquamarinepk/aquamarine v0.1.0\n",
+			"module example.com/shop\n\nrequire (\n\tgithub.com/aquamarinepk/aquamarine v0.1.0\n\tgithub.com/google/uuid v1.6.0\n)\n\nreplace github.com/aquamarinepk/aquamarine => ../..\n",
+			"module example.com/shop\n\nrequire github.com/aquamarinepk/aquamarine v0.1.0\n\nrequire (\n\tgithub.com/google/uuid v1.6.0\n)\n\nreplace github.com/aquamarinepk/aquamarine => ../..\n"},
+		{"dev replace moved", "module example.com/shop\n\nreplace github.com/aquamarinepk/aquamarine => ../..\n",
+			"module example.com/shop\n\nreplace github.com/aquamarinepk/aquamarine => ../../..\n",
+			"module example.com/shop\n\nreplace github.com/aquamarinepk/aquamarine => ../../..\n"},
+		{"prod drops the replace", "module example.com/shop\n\nreplace github.com/aquamarinepk/aquamarine => ../..\n\nreplace example.com/lib => ../lib\n",
+			"module example.com/shop\n",
+			"module example.com/shop\n\nreplace example.com/lib => ../lib\n"},
+		{"module renamed", "module example.com/shop\n\ngo 1.22\n", "module example.com/store\n\ngo 1.22\n", "module example.com/store\n\ngo 1.22\n"},
+	}
+	for _, tt := range tests {
+		if got := string(mergeGoMod([]byte(tt.existing), []byte(tt.generated))); got != tt.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGoModAfterTidy(t *testing.T) {
+	out := MapOutput{}
+	spec := "version: 0.2\nproject: {name: shop, module: example.com/shop}\nruntime: {http: {api: {port: 8081}, web: {port: 8080}}}\nfeats: []\n"
+	generate := func(spec string) *Plan {
+		t.Helper()
+		cfg, diags := ParseSpec("aquamarine.yaml", []byte(spec))
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		fg, err := NewFeatureGenerator(*cfg, "out", false, assets.Templates())
+		if err != nil {
+			t.Fatal(err)
+		}
+		fg.Output = out
+		plan, err := fg.Plan()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := plan.Apply(); err != nil {
+			t.Fatal(err)
+		}
+		return plan
+	}
+	generate(spec)
+	out[goModFile] = append(out[goModFile], "\nrequire golang.org/x/sys v0.22.0 // indirect\n"...)
+
+	action := func(plan *Plan) FileAction {
+		for _, c := range plan.Changes {
+			if c.Path == goModFile {
+				return c.Action
+			}
+		}
+		return ""
+	}
+	if got := action(generate(spec)); got != FileUnchanged {
+		t.Errorf("after go mod tidy: go.mod %s, want unchanged", got)
+	}
+	// As after aquamarine add feature notes.
+	spec = strings.Replace(spec, "feats: []", "feats:\n  - {name: notes, kind: atom, models: {Note: {fields: {title: {type: string}}}}}", 1)
+	plan := generate(strings.Replace(spec, "web: {port: 8080}}", "web: {port: 8080}}, database: {engine: sqlite}", 1))
+	if got := action(plan); got != FileUpdated || plan.Err() != nil {
+		t.Errorf("new requirement after go mod tidy: go.mod %s (%v), want updated", got, plan.Err())
+	}
+	mod := parseGoMod(out[goModFile])
+	if mod.requires["golang.org/x/sys"] == "" || mod.requires[engineRequires["sqlite"].Path] == "" {
+		t.Errorf("merged go.mod lost a requirement:\n%s", out[goModFile])
+	}
+}
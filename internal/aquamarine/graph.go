@@ -0,0 +1,326 @@
+package aquamarine
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Graph output formats accepted by the graph command.
+const (
+	FormatDOT      = "dot"
+	FormatMermaid  = "mermaid"
+	FormatMarkdown = "markdown" // Mermaid in a fenced block, rendered by GitHub and most doc sites
+)
+
+// GraphOptions selects what WriteGraph draws.
+type GraphOptions struct {
+	Format string // FormatDOT (default), FormatMermaid or FormatMarkdown
+	ER     bool   // models as entities with their fields and relationships, instead of feats
+}
+
+// graph is the format-independent drawing of a spec: one cluster per feat.
+type graph struct {
+	name     string
+	clusters []graphCluster
+	edges    []graphEdge
+}
+
+type graphCluster struct {
+	id, label string
+	nodes     []graphNode
+}
+
+type graphNode struct {
+	id, label string
+	kind      string   // feat, model or aggregate
+	fields    []string // "type name", ER only
+}
+
+type graphEdge struct {
+	from, to, label string
+	kind            string // requires, uses, child, belongs_to, many_to_many or ref
+	optional        bool   // belongs_to or ref through a nullable key: to may have no from
+}
+
+// WriteGraph draws the feats of cfg, their models and aggregates, and the
+// requires and pages.uses edges between feats; with ER, the models as
+// entities and the relationships between them.
+func WriteGraph(w io.Writer, cfg *Config, opts GraphOptions) error {
+	g := buildGraph(cfg, opts.ER)
+	switch opts.Format {
+	case FormatDOT, "":
+		return writeDOT(w, g, opts.ER)
+	case FormatMermaid:
+		return writeMermaid(w, g, opts.ER)
+	case FormatMarkdown:
+		if _, err := fmt.Fprintln(w, "```mermaid"); err != nil {
+			return err
+		}
+		if err := writeMermaid(w, g, opts.ER); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintln(w, "```")
+		return err
+	default:
+		return fmt.Errorf("unknown format %q (want %s|%s|%s)", opts.Format, FormatDOT, FormatMermaid, FormatMarkdown)
+	}
+}
+
+func buildGraph(cfg *Config, er bool) graph {
+	g := graph{name: cfg.Project.Name}
+	feats := declared(cfg.Source, "feats", cfg.Feats)
+	for _, featName := range feats {
+		feat := cfg.Feats[featName]
+		// The feat node names the cluster, except in ER mode which has none.
+		c := graphCluster{id: "cluster_" + featName, label: featName}
+		if !er {
+			c.label = ""
+			c.nodes = append(c.nodes, graphNode{id: featID(featName), label: featName + "\n" + feat.Kind, kind: "feat"})
+		}
+		models := declared(cfg.Source, join("feats", featName)+".models", feat.Models)
+		for _, modelName := range models {
+			n := graphNode{id: modelID(featName, modelName), label: modelName, kind: "model"}
+			if _, ok := feat.Aggregates[modelName]; ok {
+				n.kind = "aggregate"
+			}
+			if er {
+				model := feat.Models[modelName]
+				for _, f := range declared(cfg.Source, join("feats", featName)+".models."+modelName+".fields", model.Fields) {
+					n.fields = append(n.fields, model.Fields[f].Type+" "+f)
+				}
+			}
+			c.nodes = append(c.nodes, n)
+		}
+		fks, joins := featLinks(cfg.Source, join("feats", featName), feat)
+		for _, fk := range fks {
+			if er {
+				i := slices.IndexFunc(c.nodes, func(n graphNode) bool { return n.id == modelID(featName, fk.Model) })
+				c.nodes[i].fields = append(c.nodes[i].fields, "uuid "+fk.field())
+			}
+			g.edges = append(g.edges,
+				graphEdge{from: modelID(featName, fk.Target), to: modelID(featName, fk.Model), label: fk.Name, kind: "belongs_to", optional: fk.Nullable})
+		}
+		for _, jt := range joins {
+			g.edges = append(g.edges,
+				graphEdge{from: modelID(featName, jt.Owner), to: modelID(featName, jt.Target), label: jt.Name, kind: "many_to_many"})
+		}
+		for _, aggName := range declared(cfg.Source, join("feats", featName)+".aggregates", feat.Aggregates) {
+			agg := feat.Aggregates[aggName]
+			if !slices.Contains(models, aggName) {
+				c.nodes = append(c.nodes, graphNode{id: modelID(featName, aggName), label: aggName, kind: "aggregate"})
+			}
+			i := slices.IndexFunc(c.nodes, func(n graphNode) bool { return n.id == modelID(featName, aggName) })
+			if er {
+				for _, f := range sortedKeys(agg.Fields) {
+					c.nodes[i].fields = append(c.nodes[i].fields, agg.Fields[f].Type+" "+f)
+				}
+			}
+			for _, child := range sortedKeys(agg.Children) {
+				if of := agg.Children[child].Of; of != "" {
+					g.edges = append(g.edges, graphEdge{from: modelID(featName, aggName), to: modelID(featName, of), label: child, kind: "child"})
+				}
+			}
+		}
+		g.clusters = append(g.clusters, c)
+	}
+	for _, r := range cfg.validRefs() {
+		optional := cfg.Feats[r.Feat].Models[r.Model].Fields[r.Field].Nullable
+		g.edges = append(g.edges, graphEdge{from: modelID(r.Owner, r.Target), to: modelID(r.Feat, r.Model), label: r.Field, kind: "ref", optional: optional})
+	}
+	if er {
+		return g
+	}
+
+	for _, featName := range feats {
+		feat := cfg.Feats[featName]
+		var requires []string
+		for _, e := range cfg.Ordering.Requires {
+			if e[0] == featName && !slices.Contains(requires, e[1]) {
+				requires = append(requires, e[1])
+			}
+		}
+		for _, dep := range feat.Requires {
+			if !slices.Contains(requires, dep) {
+				requires = append(requires, dep)
+			}
+		}
+		for _, dep := range requires {
+			g.edges = append(g.edges, graphEdge{from: featID(featName), to: featID(dep), label: "requires", kind: "requires"})
+		}
+		var uses []string
+		for _, p := range feat.Web.Pages {
+			for _, use := range p.Uses {
+				if !slices.Contains(uses, use) {
+					uses = append(uses, use)
+				}
+			}
+		}
+		for _, use := range uses {
+			g.edges = append(g.edges, graphEdge{from: featID(featName), to: featID(use), label: "uses", kind: "uses"})
+		}
+	}
+	return g
+}
+
+// Node ids are prefixed so feat names cannot clash with keywords of either
+// format (Mermaid's end, DOT's node).
+func featID(feat string) string {
+	return "feat_" + feat
+}
+
+func modelID(feat, model string) string {
+	return "model_" + feat + "_" + model
+}
+
+func writeDOT(w io.Writer, g graph, er bool) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(g.name))
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [fontname=\"Helvetica\"];\n")
+	b.WriteString("\tedge [fontname=\"Helvetica\", fontsize=10];\n")
+	for _, c := range g.clusters {
+		fmt.Fprintf(&b, "\n\tsubgraph %s {\n", c.id)
+		fmt.Fprintf(&b, "\t\tlabel=%s;\n\t\tstyle=rounded;\n", dotQuote(c.label))
+		for _, n := range c.nodes {
+			fmt.Fprintf(&b, "\t\t%s [%s];\n", n.id, dotNodeAttrs(n, er))
+		}
+		b.WriteString("\t}\n")
+	}
+	if len(g.edges) > 0 {
+		b.WriteString("\n")
+	}
+	for _, e := range g.edges {
+		attrs := "label=" + dotQuote(e.label)
+		switch {
+		case e.kind == "uses":
+			attrs += ", style=dashed"
+		case (e.kind == "child" || e.kind == "belongs_to") && er:
+			attrs += ", arrowtail=" + dotOne(e) + ", arrowhead=crow, dir=both"
+		case e.kind == "ref" && er:
+			attrs += ", arrowtail=" + dotOne(e) + ", arrowhead=crow, dir=both, style=dashed"
+		case e.kind == "many_to_many" && er:
+			attrs += ", arrowtail=crow, arrowhead=crow, dir=both"
+		case e.kind == "child":
+			attrs += ", arrowtail=diamond, dir=both"
+		case e.kind == "many_to_many":
+			attrs += ", dir=both"
+		case e.kind == "ref":
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(&b, "\t%s -> %s [%s];\n", e.from, e.to, attrs)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotOne is the crow's foot arrow for the one side of an ER edge: exactly
+// one, or zero or one through a nullable key.
+func dotOne(e graphEdge) string {
+	if e.optional {
+		return "teeodot"
+	}
+	return "tee"
+}
+
+func dotNodeAttrs(n graphNode, er bool) string {
+	if er {
+		var rows strings.Builder
+		for _, f := range n.fields {
+			rows.WriteString(dotRecordEscape(f) + `\l`)
+		}
+		return fmt.Sprintf("shape=record, label=\"{%s|%s}\"", dotRecordEscape(n.label), rows.String())
+	}
+	switch n.kind {
+	case "feat":
+		return "shape=box, style=bold, label=" + dotQuote(n.label)
+	case "aggregate":
+		return "shape=ellipse, peripheries=2, label=" + dotQuote(n.label)
+	default:
+		return "shape=ellipse, label=" + dotQuote(n.label)
+	}
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + strings.ReplaceAll(s, "\n", `\n`) + `"`
+}
+
+// dotRecordEscape escapes the characters that structure a record label.
+func dotRecordEscape(s string) string {
+	r := strings.NewReplacer(`{`, `\{`, `}`, `\}`, `|`, `\|`, `<`, `\<`, `>`, `\>`, `"`, `\"`)
+	return r.Replace(s)
+}
+
+func writeMermaid(w io.Writer, g graph, er bool) error {
+	var b strings.Builder
+	if er {
+		b.WriteString("erDiagram\n")
+		for _, c := range g.clusters {
+			for _, n := range c.nodes {
+				if len(n.fields) == 0 {
+					fmt.Fprintf(&b, "\t%s[%s]\n", n.id, mermaidQuote(n.label))
+					continue
+				}
+				fmt.Fprintf(&b, "\t%s[%s] {\n", n.id, mermaidQuote(n.label))
+				for _, f := range n.fields {
+					fmt.Fprintf(&b, "\t\t%s\n", f)
+				}
+				b.WriteString("\t}\n")
+			}
+		}
+		for _, e := range g.edges {
+			one := "||"
+			if e.optional {
+				one = "|o"
+			}
+			card := one + "--o{"
+			switch e.kind {
+			case "many_to_many":
+				card = "}o--o{"
+			case "ref":
+				card = one + "..o{"
+			}
+			fmt.Fprintf(&b, "\t%s %s %s : %s\n", e.from, card, e.to, e.label)
+		}
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	b.WriteString("flowchart LR\n")
+	for _, c := range g.clusters {
+		fmt.Fprintf(&b, "\tsubgraph %s[%s]\n", c.id, mermaidQuote(cmp.Or(c.label, " ")))
+		for _, n := range c.nodes {
+			label := mermaidQuote(strings.ReplaceAll(n.label, "\n", "<br>"))
+			switch n.kind {
+			case "feat":
+				fmt.Fprintf(&b, "\t\t%s[%s]\n", n.id, label)
+			case "aggregate":
+				fmt.Fprintf(&b, "\t\t%s(((%s)))\n", n.id, label)
+			default:
+				fmt.Fprintf(&b, "\t\t%s([%s])\n", n.id, label)
+			}
+		}
+		b.WriteString("\tend\n")
+	}
+	for _, e := range g.edges {
+		arrow := "-->"
+		switch e.kind {
+		case "uses", "ref":
+			arrow = "-.->"
+		case "many_to_many":
+			arrow = "<-->"
+		}
+		fmt.Fprintf(&b, "\t%s %s|%s| %s\n", e.from, arrow, e.label, e.to)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
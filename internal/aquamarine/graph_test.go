@@ -0,0 +1,97 @@
+package aquamarine
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const graphSpec = `version: 0.2
+project: {name: shop, module: example.com/shop}
+runtime: {http: {api: {port: 8081}, web: {port: 8080}}}
+feats:
+  - name: web
+    kind: web
+    web: {pages: [{route: "GET /", uses: [ordering]}]}
+  - name: ordering
+    kind: domain
+    requires: [auth]
+    models:
+      Order: {fields: {total: {type: float64}}}
+      LineItem: {fields: {sku: {type: string}}}
+      Customer:
+        fields: {name: {type: string}, user_id: {type: uuid, ref: auth.User}, referrer_id: {type: uuid, ref: auth.User, nullable: true}}
+        relations:
+          orders: {type: has_many, model: Order}
+          favorites: {type: many_to_many, model: LineItem}
+      Note:
+        fields: {text: {type: string}}
+        relations:
+          order: {type: belongs_to, model: Order, nullable: true}
+    aggregates:
+      Order: {children: {items: {of: LineItem}}}
+  - {name: auth, models: {User: {fields: {email: {type: email}}}}}
+`
+
+func TestGraph(t *testing.T) {
+	cfg, diags := ParseSpec("aquamarine.yaml", []byte(graphSpec))
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	tests := []struct {
+		opts GraphOptions
+		want []string
+	}{
+		{GraphOptions{}, []string{
+			`digraph "shop" {`,
+			`feat_ordering [shape=box, style=bold, label="ordering\ndomain"];`,
+			`model_ordering_Order [shape=ellipse, peripheries=2, label="Order"];`,
+			`model_ordering_Order -> model_ordering_LineItem [label="items", arrowtail=diamond, dir=both];`,
+			`feat_ordering -> feat_auth [label="requires"];`,
+			`feat_web -> feat_ordering [label="uses", style=dashed];`,
+		}},
+		{GraphOptions{Format: FormatMermaid}, []string{
+			"flowchart LR\n",
+			`model_ordering_Order((("Order")))`,
+			`feat_web -.->|uses| feat_ordering`,
+			`feat_ordering -->|requires| feat_auth`,
+			`model_auth_User -.->|user_id| model_ordering_Customer`,
+		}},
+		{GraphOptions{Format: FormatMarkdown, ER: true}, []string{
+			"```mermaid\nerDiagram\n",
+			"model_ordering_LineItem[\"LineItem\"] {\n\t\tstring sku\n\t}",
+			`model_ordering_Order ||--o{ model_ordering_LineItem : items`,
+			"model_ordering_Order[\"Order\"] {\n\t\tfloat64 total\n\t\tuuid customer_id\n\t}",
+			`model_ordering_Customer ||--o{ model_ordering_Order : customer`,
+			`model_ordering_Customer }o--o{ model_ordering_LineItem : favorites`,
+			`model_auth_User ||..o{ model_ordering_Customer : user_id`,
+			`model_ordering_Order |o--o{ model_ordering_Note : order`,
+			`model_auth_User |o..o{ model_ordering_Customer : referrer_id`,
+			"```\n",
+		}},
+		{GraphOptions{ER: true}, []string{
+			`model_ordering_Order [shape=record, label="{Order|float64 total\luuid customer_id\l}"];`,
+			`model_ordering_Customer -> model_ordering_LineItem [label="favorites", arrowtail=crow, arrowhead=crow, dir=both];`,
+			`model_ordering_Customer -> model_ordering_Order [label="customer", arrowtail=tee, arrowhead=crow, dir=both];`,
+			`model_ordering_Order -> model_ordering_Note [label="order", arrowtail=teeodot, arrowhead=crow, dir=both];`,
+		}},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if err := WriteGraph(&out, cfg, tt.opts); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("%+v: output lacks %q:\n%s", tt.opts, want, out.String())
+			}
+		}
+		if tt.opts.ER && strings.Contains(out.String(), "feat_") {
+			t.Errorf("%+v: ER output draws feats:\n%s", tt.opts, out.String())
+		}
+	}
+
+	if err := WriteGraph(&bytes.Buffer{}, cfg, GraphOptions{Format: "svg"}); err == nil {
+		t.Error("unknown format: want an error")
+	}
+}
@@ -0,0 +1,62 @@
+package aquamarine
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// Hook stages, as named in the spec's hooks section.
+const (
+	HookPreGenerate  = "pre_generate"
+	HookPostGenerate = "post_generate"
+)
+
+// runHooks runs the commands of a hook stage one by one through the shell, in
+// dir, stopping at the first failure. Dir is created when missing, as it is
+// for pre_generate hooks on the first run. Commands see the run in their
+// environment: AQUAMARINE_SPEC, AQUAMARINE_OUTPUT, AQUAMARINE_PROFILE and
+// AQUAMARINE_MODE (dev when building against the local checkout, else prod).
+func runHooks(stage string, cmds []string, dir, specFile, profile string, local bool) error {
+	if len(cmds) == 0 {
+		return nil
+	}
+	spec, err := filepath.Abs(specFile)
+	if err != nil {
+		return err
+	}
+	out, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		return err
+	}
+	env := append(os.Environ(),
+		"AQUAMARINE_SPEC="+spec,
+		"AQUAMARINE_OUTPUT="+out,
+		"AQUAMARINE_PROFILE="+profile,
+		"AQUAMARINE_MODE="+outputMode(local),
+	)
+	for _, c := range cmds {
+		fmt.Printf("%s: %s\n", stage, c)
+		cmd := shell(c)
+		cmd.Dir = out
+		cmd.Env = env
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s hook %q: %w", stage, c, err)
+		}
+	}
+	return nil
+}
+
+func shell(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
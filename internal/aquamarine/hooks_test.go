@@ -0,0 +1,110 @@
+package aquamarine
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/aquamarinepk/aquamarine/assets"
+)
+
+func TestRunHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks in this test use sh")
+	}
+	dir := t.TempDir()
+	cmds := []string{`echo "$AQUAMARINE_PROFILE $AQUAMARINE_MODE $(basename "$AQUAMARINE_SPEC")" > hook.txt`, "exit 3", "touch never"}
+	err := runHooks(HookPostGenerate, cmds, dir, "aquamarine.yaml", "staging", true)
+	if err == nil || !strings.Contains(err.Error(), `post_generate hook "exit 3"`) {
+		t.Errorf("got %v, want the failing hook reported", err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "hook.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(b)); got != "staging dev aquamarine.yaml" {
+		t.Errorf("hook env = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "never")); err == nil {
+		t.Error("hooks kept running after a failure")
+	}
+}
+
+func TestPostGenerateRewrites(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks in this test use sh")
+	}
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "aquamarine.yaml")
+	content := `version: 0.2
+project: {name: shop, module: example.com/shop}
+runtime: {http: {api: {port: 8081}, web: {port: 8080}}}
+hooks:
+  post_generate: ['echo "// tidied" >> main.go']
+feats: []
+`
+	if err := os.WriteFile(spec, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "out")
+	for run := 1; run <= 2; run++ {
+		if err := Generate(assets.Templates(), GenerateOptions{SpecFile: spec, OutputDir: out}); err != nil {
+			t.Fatalf("run %d: %v", run, err)
+		}
+	}
+	b, err := os.ReadFile(filepath.Join(out, "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := bytes.Count(b, []byte("// tidied")); n != 1 {
+		t.Errorf("main.go has the hook's line %d times, want once: the hook's rewrite was kept as a hand edit", n)
+	}
+	m, err := LoadManifest(DirOutput(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Files["main.go"].Hash != contentHash(b) {
+		t.Error("manifest does not record main.go as the hook left it")
+	}
+}
+
+func TestPreGenerateFreshOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks in this test use sh")
+	}
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "aquamarine.yaml")
+	content := `version: 0.2
+project: {name: shop, module: example.com/shop}
+runtime: {http: {api: {port: 8081}, web: {port: 8080}}}
+hooks:
+  pre_generate: ['pwd > pre.txt']
+feats: []
+`
+	if err := os.WriteFile(spec, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "new", "out")
+	if err := Generate(assets.Templates(), GenerateOptions{SpecFile: spec, OutputDir: out}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(out, "pre.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := filepath.EvalSymlinks(strings.TrimSpace(string(b))); got != mustEval(t, out) {
+		t.Errorf("pre_generate ran in %s, want %s", got, out)
+	}
+}
+
+func mustEval(t *testing.T, path string) string {
+	t.Helper()
+	p, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
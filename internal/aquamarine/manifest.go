@@ -0,0 +1,147 @@
+package aquamarine
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// ManifestFile records what generate wrote, relative to the output dir.
+const ManifestFile = ".aquamarine/manifest.json"
+
+const manifestVersion = 1
+
+// ConflictSuffix is appended to a hand-edited file's path to hold the newly
+// generated content the user has to merge by hand.
+const ConflictSuffix = ".new"
+
+// Manifest lists the generated files of an output dir with the hash of the
+// content generate last wrote to them. A file whose hash on disk no longer
+// matches was edited by hand.
+type Manifest struct {
+	Version int                      `json:"version"`
+	Files   map[string]ManifestEntry `json:"files"`
+	Skip    []string                 `json:"skip,omitempty"` // files the project opted out of
+
+	// Schemas holds, by migration dir, the schema the migrations in it
+	// build, as the migration template renders it in one piece.
+	Schemas map[string]string `json:"schemas,omitempty"`
+}
+
+func (m *Manifest) skips(path string) bool {
+	for _, s := range m.Skip {
+		if s == path {
+			return true
+		}
+	}
+	return false
+}
+
+// ManifestEntry is the generated state of a single file.
+type ManifestEntry struct {
+	Feat    string            `json:"feat,omitempty"`    // owning feat, empty for project files
+	Hash    string            `json:"hash"`              // content hash, user regions blanked
+	Regions map[string]string `json:"regions,omitempty"` // generated body hash per user region
+}
+
+// LoadManifest reads the manifest of out. A missing manifest is empty.
+func LoadManifest(out Output) (*Manifest, error) {
+	m := &Manifest{Version: manifestVersion, Files: map[string]ManifestEntry{}}
+	b, err := out.ReadFile(ManifestFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", ManifestFile, err)
+	}
+	if m.Files == nil {
+		m.Files = map[string]ManifestEntry{}
+	}
+	return m, nil
+}
+
+// Save writes the manifest into out.
+func (m *Manifest) Save(out Output) error {
+	m.Version = manifestVersion
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return out.WriteFile(ManifestFile, append(b, '\n'))
+}
+
+func manifestEntry(feat string, generated []byte) ManifestEntry {
+	return ManifestEntry{Feat: feat, Hash: contentHash(generated), Regions: regionHashes(generated)}
+}
+
+// FileAction is what regeneration does to a single file.
+type FileAction string
+
+const (
+	FileCreated   FileAction = "created"
+	FileUpdated   FileAction = "updated"
+	FileUnchanged FileAction = "unchanged"
+	FileKept      FileAction = "kept"     // hand-edited, nothing new to apply
+	FileConflict  FileAction = "conflict" // hand-edited and the template output changed
+	FileRemoved   FileAction = "removed"
+	FileOrphaned  FileAction = "orphaned" // no longer generated, hand-edited, left in place
+)
+
+// reconcile decides how a file moves from its existing content to newly
+// generated content, given its manifest entry (tracked reports whether there
+// is one). It returns the action and the content to write: the file itself
+// for created and updated, the conflict file for conflicts.
+//
+// A file still matching its manifest hash is regenerated with its user
+// regions carried over. A hand-edited file is kept as is; if the template
+// output changed since it was last generated that is a conflict.
+func reconcile(entry ManifestEntry, tracked bool, existing []byte, exists bool, generated []byte) (FileAction, []byte) {
+	if !exists {
+		return FileCreated, generated
+	}
+	merged := mergeRegions(generated, existing)
+	current := contentHash(existing)
+	pristine := (tracked && current == entry.Hash) || (!tracked && current == contentHash(generated))
+	switch {
+	case pristine && droppedRegions(entry, existing, generated):
+		return FileConflict, merged
+	case pristine && bytes.Equal(merged, existing):
+		return FileUnchanged, nil
+	case pristine:
+		return FileUpdated, merged
+	case tracked && contentHash(generated) == entry.Hash:
+		return FileKept, nil
+	default:
+		return FileConflict, merged
+	}
+}
+
+// droppedRegions reports whether existing has a user region, edited since it
+// was generated, that generated no longer has.
+func droppedRegions(entry ManifestEntry, existing, generated []byte) bool {
+	next := regionBodies(generated)
+	for name, hash := range regionHashes(existing) {
+		if _, ok := next[name]; !ok && hash != entry.Regions[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// untouched reports whether existing is exactly what generate wrote.
+func untouched(entry ManifestEntry, existing []byte) bool {
+	if contentHash(existing) != entry.Hash {
+		return false
+	}
+	for name, hash := range regionHashes(existing) {
+		if hash != entry.Regions[name] {
+			return false
+		}
+	}
+	return true
+}
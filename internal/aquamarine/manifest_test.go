@@ -0,0 +1,135 @@
+package aquamarine
+
+import "testing"
+
+const (
+	genV1  = "package x\n\n// aquamarine:user Body\nreturn nil\n// aquamarine:end\n"
+	genV2  = "package x // v2\n\n// aquamarine:user Body\nreturn nil\n// aquamarine:end\n"
+	edited = "package x\n\n// aquamarine:user Body\nreturn mine\n// aquamarine:end\n"
+	hand   = "package x // mine\n\n// aquamarine:user Body\nreturn nil\n// aquamarine:end\n"
+)
+
+func TestReconcile(t *testing.T) {
+	entry := manifestEntry("f", []byte(genV1))
+	tests := []struct {
+		name      string
+		tracked   bool
+		existing  *string // nil: no file
+		generated string
+		action    FileAction
+		content   string
+	}{
+		{"created", true, nil, genV1, FileCreated, genV1},
+		{"pristine", true, ptr(genV1), genV1, FileUnchanged, ""},
+		{"region edited", true, ptr(edited), genV1, FileUnchanged, ""},
+		{"template changed", true, ptr(genV1), genV2, FileUpdated, genV2},
+		{"template changed, region edited", true, ptr(edited), genV2,
+			FileUpdated, "package x // v2\n\n// aquamarine:user Body\nreturn mine\n// aquamarine:end\n"},
+		{"hand edit", true, ptr(hand), genV1, FileKept, ""},
+		{"hand edit, template changed", true, ptr(hand), genV2, FileConflict, genV2},
+		{"untracked, as generated", false, ptr(genV1), genV1, FileUnchanged, ""},
+		{"untracked, different", false, ptr(hand), genV1, FileConflict, genV1},
+		{"edited region dropped", true, ptr(edited), "package x\n", FileConflict, "package x\n"},
+		{"untouched region dropped", true, ptr(genV1), "package x\n", FileUpdated, "package x\n"},
+	}
+	for _, tt := range tests {
+		var existing []byte
+		if tt.existing != nil {
+			existing = []byte(*tt.existing)
+		}
+		e := entry
+		if !tt.tracked {
+			e = ManifestEntry{}
+		}
+		action, content := reconcile(e, tt.tracked, existing, tt.existing != nil, []byte(tt.generated))
+		if action != tt.action || string(content) != tt.content {
+			t.Errorf("%s: got %s %q, want %s %q", tt.name, action, content, tt.action, tt.content)
+		}
+	}
+}
+
+func ptr(s string) *string { return &s }
+
+// testGenerator returns a generator planning against out, as Plan sets it
+// up, without templates.
+func testGenerator(t *testing.T, out MapOutput) *FeatureGenerator {
+	t.Helper()
+	m, err := LoadManifest(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &FeatureGenerator{Output: out, plan: &Plan{out: out, manifest: m}, generated: map[string]bool{}}
+}
+
+func TestWritePending(t *testing.T) {
+	out := MapOutput{"x.go": []byte(hand), "x.go" + ConflictSuffix: []byte(genV2)}
+	m := &Manifest{Version: manifestVersion, Files: map[string]ManifestEntry{"x.go": manifestEntry("f", []byte(genV2))}}
+	if err := m.Save(out); err != nil {
+		t.Fatal(err)
+	}
+	fg := testGenerator(t, out)
+	if err := fg.write("f", "x.go", []byte(genV2)); err != nil {
+		t.Fatal(err)
+	}
+	if c := fg.plan.Changes[0]; c.Action != FileConflict {
+		t.Errorf("hand edit with a pending %s: %s, want %s", ConflictSuffix, c.Action, FileConflict)
+	}
+
+	delete(out, "x.go"+ConflictSuffix)
+	fg = testGenerator(t, out)
+	if err := fg.write("f", "x.go", []byte(genV2)); err != nil {
+		t.Fatal(err)
+	}
+	if c := fg.plan.Changes[0]; c.Action != FileKept {
+		t.Errorf("hand edit, conflict resolved: %s, want %s", c.Action, FileKept)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	out := MapOutput{"a.go": []byte(genV1), "b.go": []byte(hand), "c.go": []byte(edited), "d.go": []byte(genV1)}
+	m := &Manifest{Version: manifestVersion, Files: map[string]ManifestEntry{
+		"a.go":    manifestEntry("f", []byte(genV1)),
+		"b.go":    manifestEntry("f", []byte(genV1)),
+		"c.go":    manifestEntry("f", []byte(genV1)),
+		"d.go":    manifestEntry("g", []byte(genV1)),
+		"gone.go": manifestEntry("f", []byte(genV1)),
+	}}
+	if err := m.Save(out); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		force bool
+		scope []string
+		want  map[string]FileAction
+	}{
+		{"default", false, nil, map[string]FileAction{"a.go": FileRemoved, "b.go": FileOrphaned, "c.go": FileOrphaned, "d.go": FileRemoved}},
+		{"forced", true, nil, map[string]FileAction{"a.go": FileRemoved, "b.go": FileRemoved, "c.go": FileRemoved, "d.go": FileRemoved}},
+		{"scoped", false, []string{"g"}, map[string]FileAction{"d.go": FileRemoved}},
+	}
+	for _, tt := range tests {
+		fg := testGenerator(t, out)
+		fg.Force, fg.Scope = tt.force, tt.scope
+		if err := fg.prune(); err != nil {
+			t.Fatal(err)
+		}
+		got := map[string]FileAction{}
+		for _, c := range fg.plan.Changes {
+			got[c.Path] = c.Action
+			if c.Forced != (tt.force && c.Path != "a.go" && c.Path != "d.go") {
+				t.Errorf("%s: %s forced = %v", tt.name, c.Path, c.Forced)
+			}
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+		for path, action := range tt.want {
+			if got[path] != action {
+				t.Errorf("%s: %s %s, want %s", tt.name, path, got[path], action)
+			}
+		}
+		if _, ok := fg.plan.manifest.Files["gone.go"]; ok == (tt.scope == nil) {
+			t.Errorf("%s: manifest entry of a deleted file kept = %v", tt.name, ok)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package aquamarine
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// initMigration is the first SQL migration of a feat, holding its whole
+// schema.
+const initMigration = "0001_init.sql"
+
+var migrationRe = regexp.MustCompile(`^(\d{4})_[a-z0-9_]+\.sql$`)
+
+// sqliteMigrations plans the SQLite migrations of a feat. The first run
+// writes initMigration with the whole schema. Applied migrations never
+// change: later runs leave the feat's migrations as they are and, when the
+// schema changed since the last one, add a numbered migration taking a
+// database from the schema recorded in the manifest to the new one.
+func (fg *FeatureGenerator) sqliteMigrations(featName string, data FeatureTemplateData) error {
+	var buf bytes.Buffer
+	if err := fg.SQLiteMigrationTemplate.Execute(&buf, data); err != nil {
+		return &TemplateError{Template: fg.SQLiteMigrationTemplate.Name(), File: initMigration, Err: err}
+	}
+	dir := path.Join("assets", "migrations", "sqlite", featName)
+	manifest := fg.plan.manifest
+	if manifest.Schemas == nil {
+		manifest.Schemas = map[string]string{}
+	}
+
+	var files []string
+	last := 0
+	for _, key := range sortedKeys(manifest.Files) {
+		name, ok := strings.CutPrefix(key, dir+"/")
+		m := migrationRe.FindStringSubmatch(name)
+		if !ok || m == nil {
+			continue
+		}
+		files = append(files, key)
+		n, _ := strconv.Atoi(m[1])
+		last = max(last, n)
+	}
+	if len(files) == 0 {
+		if len(data.Models) == 0 {
+			return nil
+		}
+		manifest.Schemas[dir] = buf.String()
+		return fg.write(featName, path.Join(dir, initMigration), buf.Bytes())
+	}
+
+	prev, ok := manifest.Schemas[dir]
+	if !ok {
+		// Generated before the manifest recorded schemas: the schema is
+		// the one of the init migration, rewritten in place until then.
+		b, _, err := fg.read(path.Join(dir, initMigration))
+		if err != nil {
+			return err
+		}
+		prev = string(b)
+	}
+	for _, key := range files {
+		fg.keep(key)
+	}
+	manifest.Schemas[dir] = buf.String()
+	m := diffSchemas(parseSchema(prev), parseSchema(buf.String()))
+	if len(m.statements) == 0 && len(m.todos) == 0 {
+		return nil
+	}
+	rel := path.Join(dir, fmt.Sprintf("%04d_%s.sql", last+1, m.name()))
+	if len(m.todos) > 0 {
+		fg.plan.Warnings = append(fg.plan.Warnings, fmt.Sprintf("%s has %s SQLite cannot apply as they are: see its TODO comments",
+			path.Join(fg.OutputDir, rel), count(len(m.todos), "schema change")))
+	}
+	return fg.write(featName, rel, m.sql(featName))
+}
+
+// keep marks key, generated by an earlier run, as still generated without
+// planning anything for it: prune leaves it and its manifest entry alone.
+func (fg *FeatureGenerator) keep(key string) {
+	fg.generated[key] = true
+}
+
+// sqlTable is a table of a generated schema: its column and constraint
+// definitions as the migration template writes them, one per line.
+type sqlTable struct {
+	Name    string
+	Entries []string
+}
+
+// column returns the name and definition of a column entry; ok is false for
+// table constraints.
+func (t sqlTable) column(entry string) (name, def string, ok bool) {
+	for _, kw := range []string{"PRIMARY KEY", "UNIQUE", "CHECK", "FOREIGN KEY", "CONSTRAINT"} {
+		if strings.HasPrefix(entry, kw) {
+			return "", "", false
+		}
+	}
+	name, def, _ = strings.Cut(entry, " ")
+	return name, def, true
+}
+
+func (t sqlTable) create() string {
+	return "CREATE TABLE IF NOT EXISTS " + t.Name + " (\n\t" + strings.Join(t.Entries, ",\n\t") + "\n);"
+}
+
+// parseSchema reads the CREATE TABLE statements of a schema written by the
+// migration template; anything else is ignored.
+func parseSchema(sql string) []sqlTable {
+	var tables []sqlTable
+	var cur *sqlTable
+	for _, line := range strings.Split(sql, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case cur == nil:
+			if name, ok := strings.CutPrefix(line, "CREATE TABLE IF NOT EXISTS "); ok && strings.HasSuffix(name, " (") {
+				tables = append(tables, sqlTable{Name: strings.TrimSuffix(name, " (")})
+				cur = &tables[len(tables)-1]
+			}
+		case line == ");":
+			cur = nil
+		case line != "":
+			cur.Entries = append(cur.Entries, strings.TrimSuffix(line, ","))
+		}
+	}
+	return tables
+}
+
+// schemaMigration is what takes a database from one schema to another:
+// statements SQLite applies as they are, and todos for the changes it
+// cannot, left to the user.
+type schemaMigration struct {
+	statements []string
+	todos      []string
+	created    []string // new tables, in order
+	tables     []string // changed tables, in order
+}
+
+func (m *schemaMigration) change(table string) {
+	if !slices.Contains(m.tables, table) {
+		m.tables = append(m.tables, table)
+	}
+}
+
+// name describes the migration in its file name: create_ when it only
+// creates tables, update_ otherwise.
+func (m *schemaMigration) name() string {
+	switch {
+	case len(m.tables) == 0 && len(m.created) == 1:
+		return "create_" + strings.Trim(m.created[0], `"`)
+	case len(m.tables) == 0:
+		return "create_tables"
+	case len(m.tables) == 1 && len(m.created) == 0:
+		return "update_" + strings.Trim(m.tables[0], `"`)
+	}
+	return "update_schema"
+}
+
+func (m *schemaMigration) sql(feat string) []byte {
+	var changes []string
+	if len(m.created) > 0 {
+		changes = append(changes, "create "+strings.Join(m.created, ", "))
+	}
+	if len(m.tables) > 0 {
+		changes = append(changes, "update "+strings.Join(m.tables, ", "))
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- %s: %s\n", feat, strings.Join(changes, "; "))
+	for _, s := range m.statements {
+		b.WriteString("\n" + s + "\n")
+	}
+	if len(m.todos) > 0 {
+		b.WriteString("\n")
+		for _, t := range m.todos {
+			b.WriteString("-- TODO: " + t + "\n")
+		}
+	}
+	return []byte(b.String())
+}
+
+// diffSchemas returns the migration from schema prev to next. New tables are
+// created and new columns added; changed and removed columns and tables,
+// and columns SQLite cannot add to a table holding rows, are todos.
+func diffSchemas(prev, next []sqlTable) *schemaMigration {
+	m := &schemaMigration{}
+	old := map[string]sqlTable{}
+	for _, t := range prev {
+		old[t.Name] = t
+	}
+	for _, t := range next {
+		o, ok := old[t.Name]
+		if !ok {
+			m.created = append(m.created, t.Name)
+			m.statements = append(m.statements, t.create())
+			continue
+		}
+		delete(old, t.Name)
+		cols := map[string]string{}
+		for _, e := range o.Entries {
+			if name, def, ok := o.column(e); ok {
+				cols[name] = def
+			}
+		}
+		for _, e := range t.Entries {
+			name, def, ok := t.column(e)
+			switch {
+			case !ok:
+				if !slices.Contains(o.Entries, e) {
+					m.change(t.Name)
+					m.todos = append(m.todos, fmt.Sprintf("constraint %q added to %s: SQLite cannot add it, rebuild the table", e, t.Name))
+				}
+			case cols[name] == "":
+				m.change(t.Name)
+				if stmt, why := addColumn(t.Name, name, def); why != "" {
+					m.todos = append(m.todos, fmt.Sprintf("column %s.%s (%s) is new, but %s", t.Name, name, def, why))
+				} else {
+					m.statements = append(m.statements, stmt)
+				}
+			case cols[name] != def:
+				m.change(t.Name)
+				m.todos = append(m.todos, fmt.Sprintf("column %s.%s changed from %s to %s: SQLite cannot alter a column, rebuild the table",
+					t.Name, name, cols[name], def))
+			}
+			delete(cols, name)
+		}
+		for _, e := range o.Entries {
+			if _, _, ok := o.column(e); !ok && !slices.Contains(t.Entries, e) {
+				m.change(t.Name)
+				m.todos = append(m.todos, fmt.Sprintf("constraint %q is no longer in the spec: SQLite cannot drop it, rebuild the table", e))
+			}
+		}
+		for _, name := range sortedKeys(cols) {
+			m.change(t.Name)
+			m.todos = append(m.todos, fmt.Sprintf(
+				"column %s.%s is no longer in the spec: ALTER TABLE %s DROP COLUMN %s; once its data is not needed",
+				t.Name, name, t.Name, name))
+		}
+	}
+	for _, t := range prev {
+		if _, ok := old[t.Name]; ok {
+			m.change(t.Name)
+			m.todos = append(m.todos, fmt.Sprintf("table %s is no longer in the spec: DROP TABLE %s; once its data is not needed", t.Name, t.Name))
+		}
+	}
+	return m
+}
+
+// zeroDefaults are the values rows already in a table get for a new NOT NULL
+// column without default, by column type.
+var zeroDefaults = map[string]string{"TEXT": "''", "INTEGER": "0", "REAL": "0", "BLOB": "x''"}
+
+// addColumn returns the statement adding a column to a table, or why SQLite
+// cannot add it to a table that may hold rows.
+func addColumn(table, name, def string) (stmt, why string) {
+	switch {
+	case strings.Contains(def, "PRIMARY KEY") || strings.Contains(def, " UNIQUE"):
+		return "", "SQLite cannot add a PRIMARY KEY or UNIQUE column: rebuild the table"
+	case strings.Contains(def, "DEFAULT CURRENT_"):
+		return "", "SQLite cannot add a column with a non-constant default: add it without, then set existing rows"
+	case !strings.Contains(def, "NOT NULL") || strings.Contains(def, " DEFAULT "):
+	case strings.Contains(def, " REFERENCES "):
+		return "", "SQLite cannot add a required foreign key: rebuild the table, giving existing rows a value"
+	case strings.Contains(def, " CHECK "):
+		return "", "existing rows need one of its values: add it with a DEFAULT"
+	default:
+		typ, _, _ := strings.Cut(def, " ")
+		zero, ok := zeroDefaults[typ]
+		if !ok {
+			return "", "existing rows need a value: add it with a DEFAULT"
+		}
+		def += " DEFAULT " + zero
+	}
+	return "ALTER TABLE " + table + " ADD COLUMN " + name + " " + def + ";", ""
+}
@@ -0,0 +1,136 @@
+package aquamarine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aquamarinepk/aquamarine/assets"
+)
+
+const migrationsSpec = `version: 0.2
+project: {name: shop, module: example.com/shop}
+runtime:
+  http: {api: {port: 8081}, web: {port: 8080}}
+  database: {engine: sqlite}
+feats:
+  - name: orders
+    kind: atom
+    models:
+      Order:
+        fields:
+          number: {type: string}
+          status: {type: enum, values: [open, paid]}
+`
+
+func TestMigrations(t *testing.T) {
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "aquamarine.yaml")
+	out := filepath.Join(dir, "out")
+	migrations := filepath.Join(out, "assets", "migrations", "sqlite", "orders")
+	generate := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(spec, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := Generate(assets.Templates(), GenerateOptions{SpecFile: spec, OutputDir: out}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read := func(name string) string {
+		t.Helper()
+		b, err := os.ReadFile(filepath.Join(migrations, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	generate(migrationsSpec)
+	first := read(initMigration)
+	generate(migrationsSpec)
+	if entries, _ := os.ReadDir(migrations); len(entries) != 1 {
+		t.Fatalf("unchanged spec: %d migrations, want 1", len(entries))
+	}
+
+	generate(strings.Replace(migrationsSpec, "[open, paid]}", `[open, paid, void]}
+          note:   {type: string, nullable: true}
+          total:  {type: int}
+      Refund:
+        fields:
+          reason: {type: string}`, 1))
+	if got := read(initMigration); got != first {
+		t.Errorf("%s rewritten:\n%s", initMigration, got)
+	}
+	got := read("0002_update_schema.sql")
+	for _, want := range []string{
+		"ALTER TABLE orders ADD COLUMN note TEXT;",
+		"ALTER TABLE orders ADD COLUMN total INTEGER NOT NULL DEFAULT 0;",
+		"CREATE TABLE IF NOT EXISTS refunds (",
+		"-- TODO: column orders.status changed from",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("0002_update_schema.sql lacks %q:\n%s", want, got)
+		}
+	}
+
+	generate(strings.Replace(migrationsSpec, "number: {type: string}", "code: {type: string}", 1))
+	got = read("0003_update_schema.sql")
+	for _, want := range []string{
+		"-- TODO: column orders.number is no longer in the spec",
+		"-- TODO: table refunds is no longer in the spec",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("0003_update_schema.sql lacks %q:\n%s", want, got)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(migrations, "0002_update_schema.sql")); err != nil {
+		t.Errorf("0002_update_schema.sql pruned: %v", err)
+	}
+}
+
+func TestMigrationsNewModel(t *testing.T) {
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "aquamarine.yaml")
+	out := filepath.Join(dir, "out")
+	for _, content := range []string{migrationsSpec, migrationsSpec + `      Tag:
+        fields:
+          label: {type: string}
+`} {
+		if err := os.WriteFile(spec, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := Generate(assets.Templates(), GenerateOptions{SpecFile: spec, OutputDir: out}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b, err := os.ReadFile(filepath.Join(out, "assets", "migrations", "sqlite", "orders", "0002_create_tags.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(b); !strings.HasPrefix(got, "-- orders: create tags\n\nCREATE TABLE IF NOT EXISTS tags (") {
+		t.Errorf("0002_create_tags.sql:\n%s", got)
+	}
+}
+
+func TestAddColumn(t *testing.T) {
+	tests := []struct {
+		def, want string
+	}{
+		{"TEXT", "ALTER TABLE t ADD COLUMN c TEXT;"},
+		{"TEXT NOT NULL", "ALTER TABLE t ADD COLUMN c TEXT NOT NULL DEFAULT '';"},
+		{"INTEGER NOT NULL DEFAULT 5", "ALTER TABLE t ADD COLUMN c INTEGER NOT NULL DEFAULT 5;"},
+		{"TEXT NOT NULL UNIQUE", ""},
+		{"TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP", ""},
+		{"TEXT NOT NULL REFERENCES users(id)", ""},
+		{"TEXT NOT NULL CHECK (c IN ('a'))", ""},
+		{"DATE NOT NULL", ""},
+	}
+	for _, tt := range tests {
+		stmt, why := addColumn("t", "c", tt.def)
+		if stmt != tt.want || (stmt == "") == (why == "") {
+			t.Errorf("addColumn(%q) = %q, %q; want %q", tt.def, stmt, why, tt.want)
+		}
+	}
+}
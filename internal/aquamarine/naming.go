@@ -0,0 +1,225 @@
+package aquamarine
+
+import (
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// templateFuncs is registered on every template, built-in or override.
+var templateFuncs = template.FuncMap{
+	"pluralize":   pluralize,
+	"singularize": singularize,
+	"camel":       camel,
+	"pascal":      pascal,
+	"snake":       toSnakeCase,
+	"kebab":       kebab,
+	"sqlIdent":    sqlIdent,
+	"sqlColumn":   sqlColumn,
+}
+
+// initialisms are written all upper case in Go names (golint's list).
+var initialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DB": true,
+	"DNS": true, "EOF": true, "GUID": true, "HTML": true, "HTTP": true, "HTTPS": true,
+	"ID": true, "IP": true, "JSON": true, "JWT": true, "LHS": true, "QPS": true,
+	"RAM": true, "RHS": true, "RPC": true, "SKU": true, "SLA": true, "SMTP": true,
+	"SQL": true, "SSH": true, "TCP": true, "TLS": true, "TTL": true, "UDP": true,
+	"UI": true, "UID": true, "URI": true, "URL": true, "UTF8": true, "UUID": true,
+	"VM": true, "XML": true, "XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// words splits an identifier in any case style into its words: "userID" is
+// [user ID], "line-item" is [line item]. A run of capitals is one word, its
+// last capital starting the next word when lower case follows ("HTTPServer"
+// is [HTTP Server]). Digits stay with the word they follow.
+func words(s string) []string {
+	var out []string
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		rs := []rune(part)
+		start := 0
+		for i := 1; i < len(rs); i++ {
+			prev, cur := rs[i-1], rs[i]
+			next := rune(0)
+			if i+1 < len(rs) {
+				next = rs[i+1]
+			}
+			if unicode.IsUpper(cur) && (!unicode.IsUpper(prev) || unicode.IsLower(next)) {
+				out = append(out, string(rs[start:i]))
+				start = i
+			}
+		}
+		out = append(out, string(rs[start:]))
+	}
+	return out
+}
+
+// pascal turns an identifier into an exported Go name: "user_id" -> "UserID".
+func pascal(s string) string {
+	var b strings.Builder
+	for _, w := range words(s) {
+		if up := strings.ToUpper(w); initialisms[up] {
+			b.WriteString(up)
+		} else {
+			b.WriteString(capitalizeFirst(w))
+		}
+	}
+	return b.String()
+}
+
+// camel turns an identifier into an unexported Go name: "URLPath" -> "urlPath".
+func camel(s string) string {
+	ws := words(s)
+	if len(ws) == 0 {
+		return ""
+	}
+	first := strings.ToLower(ws[0])
+	if !initialisms[strings.ToUpper(ws[0])] && ws[0] != strings.ToUpper(ws[0]) {
+		first = lowerFirst(ws[0])
+	}
+	return first + pascal(strings.Join(ws[1:], "_"))
+}
+
+// toSnakeCase turns an identifier into snake_case: "LineItem" -> "line_item".
+func toSnakeCase(s string) string {
+	return strings.ToLower(strings.Join(words(s), "_"))
+}
+
+// kebab turns an identifier into kebab-case: "LineItem" -> "line-item".
+func kebab(s string) string {
+	return strings.ToLower(strings.Join(words(s), "-"))
+}
+
+var (
+	irregularPlurals = map[string]string{
+		"person": "people", "man": "men", "woman": "women", "child": "children",
+		"mouse": "mice", "goose": "geese", "foot": "feet", "tooth": "teeth",
+		"ox": "oxen", "leaf": "leaves", "life": "lives", "knife": "knives",
+		"wife": "wives", "half": "halves", "shelf": "shelves", "wolf": "wolves",
+		"analysis": "analyses", "axis": "axes", "basis": "bases", "crisis": "crises",
+		"criterion": "criteria", "datum": "data", "index": "indices", "matrix": "matrices",
+		"quiz": "quizzes",
+	}
+	irregularSingulars = invert(irregularPlurals)
+	uncountable        = map[string]bool{
+		"data": true, "equipment": true, "feedback": true, "fish": true, "info": true,
+		"information": true, "metadata": true, "money": true, "news": true,
+		"series": true, "sheep": true, "species": true, "staff": true,
+	}
+)
+
+func invert(m map[string]string) map[string]string {
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[v] = k
+	}
+	return out
+}
+
+// pluralize returns the English plural of the last word of an identifier,
+// keeping its case: "Category" -> "Categories", "Person" -> "People".
+func pluralize(s string) string {
+	return inflect(s, func(w string) string {
+		if p, ok := irregularPlurals[w]; ok {
+			return p
+		}
+		switch {
+		case uncountable[w]:
+			return w
+		case strings.HasSuffix(w, "y") && len(w) > 1 && !strings.ContainsRune("aeiou", rune(w[len(w)-2])):
+			return w[:len(w)-1] + "ies"
+		case strings.HasSuffix(w, "s"), strings.HasSuffix(w, "x"), strings.HasSuffix(w, "z"),
+			strings.HasSuffix(w, "ch"), strings.HasSuffix(w, "sh"):
+			return w + "es"
+		default:
+			return w + "s"
+		}
+	})
+}
+
+// singularize undoes pluralize: "Categories" -> "Category".
+func singularize(s string) string {
+	return inflect(s, func(w string) string {
+		if p, ok := irregularSingulars[w]; ok {
+			return p
+		}
+		switch {
+		case uncountable[w], strings.HasSuffix(w, "ss"), !strings.HasSuffix(w, "s"):
+			return w
+		case strings.HasSuffix(w, "ies") && len(w) > 3:
+			return w[:len(w)-3] + "y"
+		case strings.HasSuffix(w, "ouses"):
+			return w[:len(w)-1]
+		case strings.HasSuffix(w, "sses"), strings.HasSuffix(w, "uses"), strings.HasSuffix(w, "xes"),
+			strings.HasSuffix(w, "zes"), strings.HasSuffix(w, "ches"), strings.HasSuffix(w, "shes"):
+			return w[:len(w)-2]
+		default:
+			return w[:len(w)-1]
+		}
+	})
+}
+
+// inflect applies fn to the lower-cased last word of s and puts the result
+// back in the word's original case.
+func inflect(s string, fn func(string) string) string {
+	ws := words(s)
+	if len(ws) == 0 {
+		return s
+	}
+	last := ws[len(ws)-1]
+	i := strings.LastIndex(s, last)
+	out := fn(strings.ToLower(last))
+	switch {
+	case last == strings.ToUpper(last) && len(last) > 1 && strings.HasPrefix(out, strings.ToLower(last)):
+		out = last + out[len(last):] // "API" -> "APIs"
+	case last == strings.ToUpper(last) && len(last) > 1:
+		out = strings.ToUpper(out)
+	case unicode.IsUpper([]rune(last)[0]):
+		out = capitalizeFirst(out)
+	}
+	return s[:i] + out + s[i+len(last):]
+}
+
+var sqlBareRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// sqlIdent quotes a table or column name for engine when it is not a plain
+// identifier or is a reserved word: sqlIdent "sqlite" "order" -> "\"order\"".
+// Document stores take field names as they are.
+func sqlIdent(engine, name string) string {
+	switch engine {
+	case "mongodb":
+		return name
+	default:
+		if sqlBareRe.MatchString(name) && !sqliteKeywords[strings.ToUpper(name)] {
+			return name
+		}
+		return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	}
+}
+
+// sqliteKeywords are the words SQLite reserves (sqlite.org/lang_keywords.html).
+var sqliteKeywords = keywordSet(`ABORT ACTION ADD AFTER ALL ALTER ALWAYS ANALYZE AND AS ASC
+ATTACH AUTOINCREMENT BEFORE BEGIN BETWEEN BY CASCADE CASE CAST CHECK COLLATE
+COLUMN COMMIT CONFLICT CONSTRAINT CREATE CROSS CURRENT CURRENT_DATE CURRENT_TIME
+CURRENT_TIMESTAMP DATABASE DEFAULT DEFERRABLE DEFERRED DELETE DESC DETACH
+DISTINCT DO DROP EACH ELSE END ESCAPE EXCEPT EXCLUDE EXCLUSIVE EXISTS EXPLAIN
+FAIL FILTER FIRST FOLLOWING FOR FOREIGN FROM FULL GENERATED GLOB GROUP GROUPS
+HAVING IF IGNORE IMMEDIATE IN INDEX INDEXED INITIALLY INNER INSERT INSTEAD
+INTERSECT INTO IS ISNULL JOIN KEY LAST LEFT LIKE LIMIT MATCH MATERIALIZED
+NATURAL NO NOT NOTHING NOTNULL NULL NULLS OF OFFSET ON OR ORDER OTHERS OUTER
+OVER PARTITION PLAN PRAGMA PRECEDING PRIMARY QUERY RAISE RANGE RECURSIVE
+REFERENCES REGEXP REINDEX RELEASE RENAME REPLACE RESTRICT RETURNING RIGHT
+ROLLBACK ROW ROWS SAVEPOINT SELECT SET TABLE TEMP TEMPORARY THEN TIES TO
+TRANSACTION TRIGGER UNBOUNDED UNION UNIQUE UPDATE USING VACUUM VALUES VIEW
+VIRTUAL WHEN WHERE WINDOW WITH WITHOUT`)
+
+func keywordSet(list string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range strings.Fields(list) {
+		set[w] = true
+	}
+	return set
+}
@@ -0,0 +1,115 @@
+package aquamarine
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestToSnakeCase(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"User", "user"},
+		{"LineItem", "line_item"},
+		{"userID", "user_id"},
+		{"HTTPServer", "http_server"},
+		{"already_snake", "already_snake"},
+		{"line-item", "line_item"},
+		{"Version2", "version2"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := toSnakeCase(tt.in); got != tt.want {
+			t.Errorf("toSnakeCase(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCase(t *testing.T) {
+	tests := []struct {
+		in, pascal, camel, kebab string
+	}{
+		{"id", "ID", "id", "id"},
+		{"user_id", "UserID", "userID", "user-id"},
+		{"URLPath", "URLPath", "urlPath", "url-path"},
+		{"LineItem", "LineItem", "lineItem", "line-item"},
+		{"line-item", "LineItem", "lineItem", "line-item"},
+		{"sku", "SKU", "sku", "sku"},
+		{"apiKey", "APIKey", "apiKey", "api-key"},
+		{"", "", "", ""},
+	}
+	for _, tt := range tests {
+		if got := pascal(tt.in); got != tt.pascal {
+			t.Errorf("pascal(%q) = %q, want %q", tt.in, got, tt.pascal)
+		}
+		if got := camel(tt.in); got != tt.camel {
+			t.Errorf("camel(%q) = %q, want %q", tt.in, got, tt.camel)
+		}
+		if got := kebab(tt.in); got != tt.kebab {
+			t.Errorf("kebab(%q) = %q, want %q", tt.in, got, tt.kebab)
+		}
+	}
+}
+
+func TestInflection(t *testing.T) {
+	tests := []struct {
+		singular, plural string
+	}{
+		{"User", "Users"},
+		{"Category", "Categories"},
+		{"Day", "Days"},
+		{"Address", "Addresses"},
+		{"Box", "Boxes"},
+		{"Batch", "Batches"},
+		{"Person", "People"},
+		{"LineItem", "LineItems"},
+		{"order_status", "order_statuses"},
+		{"Child", "Children"},
+		{"Metadata", "Metadata"},
+	}
+	for _, tt := range tests {
+		if got := pluralize(tt.singular); got != tt.plural {
+			t.Errorf("pluralize(%q) = %q, want %q", tt.singular, got, tt.plural)
+		}
+		if got := singularize(tt.plural); got != tt.singular {
+			t.Errorf("singularize(%q) = %q, want %q", tt.plural, got, tt.singular)
+		}
+	}
+}
+
+func TestPluralizeInitialism(t *testing.T) {
+	if got := pluralize("API"); got != "APIs" {
+		t.Errorf("pluralize(%q) = %q, want %q", "API", got, "APIs")
+	}
+}
+
+func TestIdent(t *testing.T) {
+	tests := []struct {
+		engine, in, want string
+	}{
+		{"sqlite", "users", "users"},
+		{"sqlite", "order", `"order"`},
+		{"sqlite", "Group", `"Group"`},
+		{"sqlite", "line item", `"line item"`},
+		{"sqlite", `a"b`, `"a""b"`},
+		{"mongodb", "order", "order"},
+	}
+	for _, tt := range tests {
+		if got := sqlIdent(tt.engine, tt.in); got != tt.want {
+			t.Errorf("sqlIdent(%q, %q) = %q, want %q", tt.engine, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	tmpl := template.Must(template.New("t").Funcs(templateFuncs).Parse(
+		`{{pascal .}} {{camel .}} {{snake .}} {{kebab .}} {{pluralize .}} {{sqlIdent "sqlite" (snake (pluralize .))}}`))
+	var b strings.Builder
+	if err := tmpl.Execute(&b, "orderItem"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "OrderItem orderItem order_item order-item orderItems order_items"; b.String() != want {
+		t.Errorf("got %q, want %q", b.String(), want)
+	}
+}
@@ -0,0 +1,137 @@
+package aquamarine
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// NewOptions controls the new command.
+type NewOptions struct {
+	Dir       string // project directory, created if missing
+	Module    string // Go module path; defaults to the project name
+	SkipGoMod bool   // the project lives inside an existing module
+	Dev       bool   // build against a local aquamarine checkout, not a release
+}
+
+// starterDirs is the base tree of a new project.
+var starterDirs = []string{
+	"internal/feat",
+	"internal/web",
+	"internal/platform",
+	"assets/migrations",
+	"assets/seeds",
+	"assets/queries",
+	"assets/templates",
+}
+
+// NewProject scaffolds a project in opts.Dir: the base tree, a starter
+// aquamarine.yaml (an existing one is kept) and a first generate into the
+// project itself, so it builds and serves /healthz right away.
+func NewProject(templates fs.FS, opts NewOptions) error {
+	dir := opts.Dir
+	if dir == "" {
+		dir = "."
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	name := filepath.Base(abs)
+	if !projectNameRe.MatchString(name) {
+		return fmt.Errorf("invalid project name %q: use letters, digits, '-' and '_', starting with a letter", name)
+	}
+	module := opts.Module
+	if module == "" {
+		module = name
+	}
+	if !modulePathRe.MatchString(module) {
+		return fmt.Errorf("invalid Go module path %q", module)
+	}
+
+	for _, d := range starterDirs {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0o755); err != nil {
+			return err
+		}
+	}
+
+	spec := filepath.Join(dir, DefaultSpecFile)
+	if _, err := os.Stat(spec); errors.Is(err, fs.ErrNotExist) {
+		b, err := renderStarterSpec(templates, name, module)
+		if err != nil {
+			return err
+		}
+		if err := safeWriteFile(spec, b, 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("Created %s\n", spec)
+	} else {
+		fmt.Printf("Keeping existing %s\n", spec)
+	}
+
+	if opts.SkipGoMod {
+		manifest, err := LoadManifest(DirOutput(dir))
+		if err != nil {
+			return err
+		}
+		manifest.Skip = appendUnique(manifest.Skip, "go.mod")
+		if err := manifest.Save(DirOutput(dir)); err != nil {
+			return err
+		}
+	}
+
+	profile := outputMode(opts.Dev)
+	if err := Generate(templates, GenerateOptions{Profile: profile, SpecFile: spec, OutputDir: dir}); err != nil {
+		return err
+	}
+	health, err := healthzURL(spec, profile)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println("Next steps:")
+	fmt.Printf("  cd %s\n", dir)
+	fmt.Println("  make run")
+	fmt.Printf("  curl %s\n", health)
+	return nil
+}
+
+// healthzURL returns the health check URL of the API the spec describes with
+// profile, whose port a kept spec may have changed.
+func healthzURL(spec, profile string) (string, error) {
+	cfg, _, err := LoadSpec(spec)
+	if err != nil {
+		return "", err
+	}
+	if _, err := cfg.ApplyProfile(profile); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("http://127.0.0.1:%d/healthz", cfg.Runtime.HTTP.API.Port), nil
+}
+
+func renderStarterSpec(templates fs.FS, name, module string) ([]byte, error) {
+	tmpl, err := template.ParseFS(templates, "aquamarine.yaml.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse starter spec template: %w", err)
+	}
+	var buf bytes.Buffer
+	data := ProjectTemplateData{ProjectName: name, ModulePath: module}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("cannot render starter spec: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
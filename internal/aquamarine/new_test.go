@@ -0,0 +1,102 @@
+package aquamarine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aquamarinepk/aquamarine/assets"
+)
+
+func TestNewProject(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "shop")
+	if err := NewProject(assets.Templates(), NewOptions{Dir: dir, Module: "example.com/shop"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range starterDirs {
+		if info, err := os.Stat(filepath.Join(dir, d)); err != nil || !info.IsDir() {
+			t.Errorf("%s not created", d)
+		}
+	}
+	spec := filepath.Join(dir, DefaultSpecFile)
+	cfg, _, err := LoadSpec(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Project.Name != "shop" || cfg.Project.Module != "example.com/shop" {
+		t.Errorf("starter spec project = %+v, want shop, example.com/shop", cfg.Project)
+	}
+	goMod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(goMod), "module example.com/shop\n") || !strings.Contains(string(goMod), runtimeModule+" "+RuntimeVersion) {
+		t.Errorf("go.mod does not require the runtime release:\n%s", goMod)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "main.go")); err != nil {
+		t.Error(err)
+	}
+
+	// A second run keeps the spec, edited or not.
+	edited := strings.Replace(mustRead(t, spec), "name: shop", "name: store", 1)
+	if err := os.WriteFile(spec, []byte(edited), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewProject(assets.Templates(), NewOptions{Dir: dir}); err != nil {
+		t.Fatal(err)
+	}
+	if got := mustRead(t, spec); got != edited {
+		t.Errorf("existing spec rewritten:\n%s", got)
+	}
+
+	// The next steps point at the API port of the kept spec.
+	if err := os.WriteFile(spec, []byte(strings.Replace(edited, "port: 8081", "port: 9091", 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := healthzURL(spec, outputMode(false)); err != nil || got != "http://127.0.0.1:9091/healthz" {
+		t.Errorf("healthzURL = %q, %v; want the spec's API port", got, err)
+	}
+}
+
+func TestNewProjectSkipGoMod(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "tool")
+	if err := NewProject(assets.Templates(), NewOptions{Dir: dir, SkipGoMod: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); !os.IsNotExist(err) {
+		t.Errorf("go.mod written with SkipGoMod: %v", err)
+	}
+	m, err := LoadManifest(DirOutput(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.skips("go.mod") {
+		t.Errorf("manifest skips %v, want go.mod", m.Skip)
+	}
+}
+
+func TestNewProjectErrors(t *testing.T) {
+	root := t.TempDir()
+	for _, opts := range []NewOptions{
+		{Dir: filepath.Join(root, "1shop")},
+		{Dir: filepath.Join(root, "my shop")},
+		{Dir: filepath.Join(root, "shop"), Module: "example.com/my shop"},
+	} {
+		if err := NewProject(assets.Templates(), opts); err == nil {
+			t.Errorf("%+v: want an error", opts)
+		}
+		if _, err := os.Stat(opts.Dir); !os.IsNotExist(err) {
+			t.Errorf("%+v: project dir created", opts)
+		}
+	}
+}
+
+func mustRead(t *testing.T, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,119 @@
+package aquamarine
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// featDeps returns the feats each feat depends on: the ordering.requires
+// edges, the feat's own requires, the feats its pages use and the feats its
+// fields refer to. Feats the spec does not declare are left out; CheckSpec
+// reports them.
+func (c *Config) featDeps() map[string][]string {
+	deps := map[string][]string{}
+	add := func(feat, dep string) {
+		_, ok := c.Feats[feat]
+		_, known := c.Feats[dep]
+		if ok && known && feat != dep && !slices.Contains(deps[feat], dep) {
+			deps[feat] = append(deps[feat], dep)
+		}
+	}
+	for _, e := range c.Ordering.Requires {
+		add(e[0], e[1])
+	}
+	for _, name := range declared(c.Source, "feats", c.Feats) {
+		f := c.Feats[name]
+		for _, dep := range f.Requires {
+			add(name, dep)
+		}
+		for _, p := range f.Web.Pages {
+			for _, use := range p.Uses {
+				add(name, use)
+			}
+		}
+		for _, r := range modelRefs(c.Source, name, f) {
+			add(name, r.Owner)
+		}
+	}
+	return deps
+}
+
+// FeatNames returns the feats in the order the spec declares them.
+func (c *Config) FeatNames() []string {
+	return declared(c.Source, "feats", c.Feats)
+}
+
+// ModelNames returns the models of feat in the order the spec declares
+// them, none for an unknown feat.
+func (c *Config) ModelNames(feat string) []string {
+	return declared(c.Source, "feats."+feat+".models", c.Feats[feat].Models)
+}
+
+// FeatOrder returns the feats in dependency order: every feat comes after
+// the feats it depends on (see featDeps), otherwise in declaration order.
+// Generated wiring, migrations and seeds follow it.
+func (c *Config) FeatOrder() ([]string, error) {
+	names := declared(c.Source, "feats", c.Feats)
+	deps := c.featDeps()
+	if cycle := findCycle(names, deps); cycle != nil {
+		return nil, fmt.Errorf("feat dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+	var order []string
+	done := map[string]bool{}
+	var visit func(name string)
+	visit = func(name string) {
+		if done[name] {
+			return
+		}
+		done[name] = true
+		for _, dep := range deps[name] {
+			visit(dep)
+		}
+		order = append(order, name)
+	}
+	for _, name := range names {
+		visit(name)
+	}
+	return order, nil
+}
+
+// findCycle returns the first dependency cycle found walking nodes in order,
+// with the starting node repeated at the end, or nil when the graph is
+// acyclic.
+func findCycle(nodes []string, deps map[string][]string) []string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := map[string]int{}
+	var stack []string
+	var visit func(n string) []string
+	visit = func(n string) []string {
+		state[n] = visiting
+		stack = append(stack, n)
+		for _, dep := range deps[n] {
+			switch state[dep] {
+			case visiting:
+				i := slices.Index(stack, dep)
+				return append(append([]string{}, stack[i:]...), dep)
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[n] = done
+		return nil
+	}
+	for _, n := range nodes {
+		if state[n] == unvisited {
+			if cycle := visit(n); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package aquamarine
+
+import (
+	"strings"
+	"testing"
+)
+
+const orderSpec = `version: 0.2
+project: {name: shop, module: example.com/shop}
+runtime: {http: {api: {port: 8081}, web: {port: 8080}}}
+ordering:
+  requires:
+    - [billing, orders]
+feats:
+  - {name: web, kind: web, web: {pages: [{route: "GET /", uses: [orders]}]}}
+  - {name: orders, requires: [auth]}
+  - {name: billing}
+  - {name: auth}
+  - {name: audit}
+`
+
+func TestFeatOrder(t *testing.T) {
+	cfg, diags := ParseSpec("aquamarine.yaml", []byte(orderSpec))
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	order, err := cfg.FeatOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Dependencies first, declaration order otherwise.
+	if got, want := strings.Join(order, " "), "auth orders web billing audit"; got != want {
+		t.Errorf("order = %q, want %q", got, want)
+	}
+	if diags := CheckSpec(cfg); len(diags) > 0 {
+		t.Errorf("acyclic spec: %v", diags)
+	}
+}
+
+func TestFeatOrderErrors(t *testing.T) {
+	tests := []struct {
+		from, to, path, want string
+	}{
+		{"{name: auth}", "{name: auth, requires: [orders]}", "feats.orders.requires[0]", "dependency cycle: orders -> auth -> orders"},
+		{"{name: auth}", "{name: auth, requires: [billing]}", "feats.orders.requires[0]", "dependency cycle: orders -> auth -> billing -> orders"},
+		{"{name: audit}", "{name: audit, requires: [audit]}", "feats.audit.requires[0]", `feat "audit" requires itself`},
+		{"{name: audit}", "{name: audit, requires: [ledger]}", "feats.audit.requires[0]", `unknown feat "ledger"`},
+	}
+	for _, tt := range tests {
+		cfg, diags := ParseSpec("aquamarine.yaml", []byte(strings.Replace(orderSpec, tt.from, tt.to, 1)))
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		diags = CheckSpec(cfg)
+		if len(diags) != 1 || diags[0].Path != tt.path || diags[0].Message != tt.want {
+			t.Errorf("%s: got %v, want %s: %s", tt.to, diags, tt.path, tt.want)
+		}
+	}
+
+	cfg, _ := ParseSpec("aquamarine.yaml", []byte(strings.Replace(orderSpec, "{name: auth}", "{name: auth, requires: [web]}", 1)))
+	if _, err := cfg.FeatOrder(); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("FeatOrder on a cycle: got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package aquamarine
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Output is where a generate run reads the previous tree from and writes the
+// new one to. Names are slash separated and relative to the output root.
+type Output interface {
+	// ReadFile returns the content of name, or an error wrapping
+	// fs.ErrNotExist when there is none.
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte) error
+	Remove(name string) error
+}
+
+// DirOutput is an Output rooted at a directory on disk.
+type DirOutput string
+
+func (d DirOutput) path(name string) string {
+	return filepath.Join(string(d), filepath.FromSlash(name))
+}
+
+func (d DirOutput) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(d.path(name))
+}
+
+func (d DirOutput) WriteFile(name string, data []byte) error {
+	return safeWriteFile(d.path(name), data, 0o644)
+}
+
+func (d DirOutput) Remove(name string) error {
+	return os.Remove(d.path(name))
+}
+
+// MapOutput is an in-memory Output. Seed it with a previous tree to plan a
+// regenerate against it.
+type MapOutput map[string][]byte
+
+func (m MapOutput) ReadFile(name string) ([]byte, error) {
+	b, ok := m[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	return b, nil
+}
+
+func (m MapOutput) WriteFile(name string, data []byte) error {
+	m[name] = append([]byte(nil), data...)
+	return nil
+}
+
+func (m MapOutput) Remove(name string) error {
+	if _, ok := m[name]; !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	delete(m, name)
+	return nil
+}
+
+// errArchive is returned when removing from an archive, which only ever
+// holds a fresh tree.
+var errArchive = errors.New("archive outputs are write-only")
+
+// ZipOutput writes a fresh tree into a zip archive. Nothing is read back, so
+// every file is created. Close finishes the archive.
+type ZipOutput struct {
+	zw *zip.Writer
+}
+
+func NewZipOutput(w io.Writer) *ZipOutput {
+	return &ZipOutput{zw: zip.NewWriter(w)}
+}
+
+func (z *ZipOutput) ReadFile(name string) ([]byte, error) {
+	return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+}
+
+func (z *ZipOutput) WriteFile(name string, data []byte) error {
+	w, err := z.zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func (z *ZipOutput) Remove(name string) error {
+	return &fs.PathError{Op: "remove", Path: name, Err: errArchive}
+}
+
+func (z *ZipOutput) Close() error {
+	return z.zw.Close()
+}
+
+// TarOutput writes a fresh tree into a tar stream, like ZipOutput. Close
+// finishes the stream.
+type TarOutput struct {
+	tw *tar.Writer
+}
+
+func NewTarOutput(w io.Writer) *TarOutput {
+	return &TarOutput{tw: tar.NewWriter(w)}
+}
+
+func (t *TarOutput) ReadFile(name string) ([]byte, error) {
+	return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+}
+
+func (t *TarOutput) WriteFile(name string, data []byte) error {
+	hdr := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0o644,
+		Size:     int64(len(data)),
+		ModTime:  time.Unix(0, 0), // reproducible archives
+	}
+	if err := t.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := t.tw.Write(data)
+	return err
+}
+
+func (t *TarOutput) Remove(name string) error {
+	return &fs.PathError{Op: "remove", Path: name, Err: errArchive}
+}
+
+func (t *TarOutput) Close() error {
+	return t.tw.Close()
+}
@@ -0,0 +1,163 @@
+package aquamarine
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+)
+
+// Change is a planned filesystem operation on a single generated file.
+type Change struct {
+	Path   string // relative to the output dir, slash separated
+	Action FileAction
+	Old    []byte // content on disk, nil when there is no file
+	New    []byte // content to write; for conflicts, the ConflictSuffix file
+	Forced bool   // a conflict or orphan overridden by --force
+}
+
+// Plan is the set of changes a generate run makes, computed without touching
+// the filesystem. Apply carries it out.
+type Plan struct {
+	Dir      string // names the output in messages
+	Changes  []Change
+	Warnings []string // things to look at in the planned files
+	out      Output
+	manifest *Manifest
+}
+
+// Conflicts counts the files left for the user to merge.
+func (p *Plan) Conflicts() int {
+	n := 0
+	for _, c := range p.Changes {
+		if c.Action == FileConflict {
+			n++
+		}
+	}
+	return n
+}
+
+// Apply performs the planned changes and saves the manifest.
+func (p *Plan) Apply() error {
+	for _, c := range p.Changes {
+		path := filepath.Join(p.Dir, filepath.FromSlash(c.Path))
+		switch c.Action {
+		case FileCreated, FileUpdated:
+			if err := p.out.WriteFile(c.Path, c.New); err != nil {
+				return fmt.Errorf("cannot write %s: %w", path, err)
+			}
+			if c.Forced {
+				if err := p.out.Remove(c.Path + ConflictSuffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+					return err
+				}
+			}
+		case FileConflict:
+			if err := p.out.WriteFile(c.Path+ConflictSuffix, c.New); err != nil {
+				return fmt.Errorf("cannot write %s: %w", path+ConflictSuffix, err)
+			}
+		case FileRemoved:
+			if err := p.out.Remove(c.Path); err != nil {
+				return fmt.Errorf("cannot remove %s: %w", path, err)
+			}
+		}
+	}
+	if err := p.manifest.Save(p.out); err != nil {
+		return fmt.Errorf("cannot save manifest: %w", err)
+	}
+	return nil
+}
+
+// Rehash records in the manifest the content now in the output of the files
+// the plan created, updated or left unchanged, and saves it. Generate calls
+// it after the post_generate hooks, which may rewrite generated files.
+// Hand-edited files keep their entries.
+func (p *Plan) Rehash() error {
+	for _, c := range p.Changes {
+		switch c.Action {
+		case FileCreated, FileUpdated, FileUnchanged:
+		default:
+			continue
+		}
+		entry, ok := p.manifest.Files[c.Path]
+		if !ok {
+			continue
+		}
+		b, err := p.out.ReadFile(c.Path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		// Regions keep the generated hashes: their bodies are the user's.
+		entry.Hash = contentHash(b)
+		p.manifest.Files[c.Path] = entry
+	}
+	if err := p.manifest.Save(p.out); err != nil {
+		return fmt.Errorf("cannot save manifest: %w", err)
+	}
+	return nil
+}
+
+// WriteSummary lists the planned changes, one line per file that changes or
+// needs attention.
+func (p *Plan) WriteSummary(w io.Writer) error {
+	for _, c := range p.Changes {
+		path := filepath.Join(p.Dir, filepath.FromSlash(c.Path))
+		forced := ""
+		if c.Forced {
+			forced = " (forced)"
+		}
+		var line string
+		switch c.Action {
+		case FileCreated:
+			line = fmt.Sprintf("    - Created %s", path)
+		case FileUpdated:
+			line = fmt.Sprintf("    - Updated %s%s", path, forced)
+		case FileKept:
+			line = fmt.Sprintf("    - Kept %s (edited by hand)", path)
+		case FileConflict:
+			line = fmt.Sprintf("    - Conflict %s: edited by hand, new content in %s", path, path+ConflictSuffix)
+		case FileRemoved:
+			line = fmt.Sprintf("    - Removed %s%s", path, forced)
+		case FileOrphaned:
+			line = fmt.Sprintf("    - Orphaned %s: no longer generated, edited by hand, left in place", path)
+		default:
+			continue
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	for _, warning := range p.Warnings {
+		if _, err := fmt.Fprintf(w, "    - Warning: %s\n", warning); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteDiff writes a unified diff of every planned change against what is on
+// disk, with paths relative to the output dir. Conflicts diff the file
+// against the content that would go to its ConflictSuffix file.
+func (p *Plan) WriteDiff(w io.Writer) error {
+	for _, c := range p.Changes {
+		oldName, newName := "a/"+c.Path, "b/"+c.Path
+		var err error
+		switch c.Action {
+		case FileCreated:
+			err = unifiedDiff(w, "/dev/null", newName, nil, c.New)
+		case FileUpdated:
+			err = unifiedDiff(w, oldName, newName, c.Old, c.New)
+		case FileConflict:
+			err = unifiedDiff(w, oldName, newName+ConflictSuffix, c.Old, c.New)
+		case FileRemoved:
+			err = unifiedDiff(w, oldName, "/dev/null", c.Old, nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package aquamarine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aquamarinepk/aquamarine/assets"
+)
+
+// testPlan plans, against an output generated as genV1, a run that creates
+// new.go, updates a.go, conflicts on the hand-edited b.go and removes gone.go.
+func testPlan(t *testing.T) (*Plan, MapOutput) {
+	t.Helper()
+	out := MapOutput{"a.go": []byte(genV1), "b.go": []byte(hand), "gone.go": []byte(genV1)}
+	m := &Manifest{Version: manifestVersion, Files: map[string]ManifestEntry{}}
+	for _, key := range []string{"a.go", "b.go", "gone.go"} {
+		m.Files[key] = manifestEntry("f", []byte(genV1))
+	}
+	if err := m.Save(out); err != nil {
+		t.Fatal(err)
+	}
+	fg := testGenerator(t, out)
+	for _, key := range []string{"new.go", "a.go", "b.go"} {
+		if err := fg.write("f", key, []byte(genV2)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := fg.prune(); err != nil {
+		t.Fatal(err)
+	}
+	return fg.plan, out
+}
+
+func TestPlanWriteDiff(t *testing.T) {
+	plan, _ := testPlan(t)
+	var b strings.Builder
+	if err := plan.WriteDiff(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `--- /dev/null
++++ b/new.go
+@@ -0,0 +1,5 @@
++package x // v2
++
++// aquamarine:user Body
++return nil
++// aquamarine:end
+--- a/a.go
++++ b/a.go
+@@ -1,4 +1,4 @@
+-package x
++package x // v2
+ 
+ // aquamarine:user Body
+ return nil
+--- a/b.go
++++ b/b.go.new
+@@ -1,4 +1,4 @@
+-package x // mine
++package x // v2
+ 
+ // aquamarine:user Body
+ return nil
+--- a/gone.go
++++ /dev/null
+@@ -1,5 +0,0 @@
+-package x
+-
+-// aquamarine:user Body
+-return nil
+-// aquamarine:end
+`
+	if b.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestPlanApply(t *testing.T) {
+	plan, out := testPlan(t)
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{
+		"new.go":                genV2,
+		"a.go":                  genV2,
+		"b.go":                  hand,
+		"b.go" + ConflictSuffix: genV2,
+	} {
+		if got := string(out[key]); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+	if _, ok := out["gone.go"]; ok {
+		t.Error("gone.go was not removed")
+	}
+	want := "1 file edited by hand conflicts with the spec"
+	if err := plan.Err(); err == nil || !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("Err() = %v, want %q", err, want)
+	}
+	m, err := LoadManifest(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.Files["gone.go"]; ok {
+		t.Error("manifest still tracks gone.go")
+	}
+	for _, key := range []string{"new.go", "a.go", "b.go"} {
+		if m.Files[key].Hash != manifestEntry("f", []byte(genV2)).Hash {
+			t.Errorf("manifest entry of %s is not the generated content", key)
+		}
+	}
+}
+
+func TestGenerateDryRun(t *testing.T) {
+	dir := t.TempDir()
+	opts := GenerateOptions{SpecFile: filepath.Join("testdata", "golden", "atom", "aquamarine.yaml"), OutputDir: filepath.Join(dir, "out"), DryRun: true}
+	if err := Generate(assets.Templates(), opts); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(opts.OutputDir); !os.IsNotExist(err) {
+		t.Fatalf("dry run created %s", opts.OutputDir)
+	}
+
+	// Over an existing output, a forced dry run leaves hand edits in place.
+	opts.DryRun = false
+	if err := Generate(assets.Templates(), opts); err != nil {
+		t.Fatal(err)
+	}
+	main := filepath.Join(opts.OutputDir, "main.go")
+	if err := os.WriteFile(main, []byte("package main // mine\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	before := snapshot(t, opts.OutputDir)
+	opts.DryRun, opts.Force = true, true
+	if err := Generate(assets.Templates(), opts); err != nil {
+		t.Fatal(err)
+	}
+	after := snapshot(t, opts.OutputDir)
+	if len(after) != len(before) {
+		t.Errorf("dry run changed the file set: %d files, want %d", len(after), len(before))
+	}
+	for name, b := range before {
+		if after[name] != b {
+			t.Errorf("dry run changed %s", name)
+		}
+	}
+}
+
+// snapshot returns the content of every file under dir by relative path.
+func snapshot(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		files[rel] = string(b)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
@@ -0,0 +1,105 @@
+package aquamarine
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// File is a file contributed by a plugin. Path is relative to the output dir.
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// Plugin contributes files to every generate run, from the same parsed spec
+// the templates see. A plugin implements one or more of ProjectPlugin,
+// FeaturePlugin and ModelPlugin. Its files go through the manifest like
+// rendered ones: user regions and hand edits are kept, and files a plugin
+// stops producing are pruned.
+type Plugin interface {
+	Name() string
+}
+
+// ProjectPlugin contributes project-wide files, e.g. CODEOWNERS.
+type ProjectPlugin interface {
+	Plugin
+	ProjectFiles(spec *Config) ([]File, error)
+}
+
+// FeaturePlugin contributes files per feat, e.g. an on-call runbook.
+type FeaturePlugin interface {
+	Plugin
+	FeatureFiles(spec *Config, feat string) ([]File, error)
+}
+
+// ModelPlugin contributes files per model of a feat.
+type ModelPlugin interface {
+	Plugin
+	ModelFiles(spec *Config, feat, model string) ([]File, error)
+}
+
+// GeneratePlugins plans the files of fg.Plugins, in plugin order: project
+// files, then per feat its files and those of its models.
+func (fg *FeatureGenerator) GeneratePlugins() error {
+	spec := &fg.Config
+	for _, p := range fg.Plugins {
+		if pp, ok := p.(ProjectPlugin); ok {
+			files, err := pp.ProjectFiles(spec)
+			if err := fg.pluginFiles(p, "", files, err); err != nil {
+				return err
+			}
+		}
+		for _, featName := range fg.scopedFeats() {
+			if fp, ok := p.(FeaturePlugin); ok {
+				files, err := fp.FeatureFiles(spec, featName)
+				if err := fg.pluginFiles(p, featName, files, err); err != nil {
+					return err
+				}
+			}
+			mp, ok := p.(ModelPlugin)
+			if !ok {
+				continue
+			}
+			for _, modelName := range spec.ModelNames(featName) {
+				files, err := mp.ModelFiles(spec, featName, modelName)
+				if err := fg.pluginFiles(p, featName, files, err); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// pluginFiles plans the files a plugin returned for feat, or reports the
+// error it returned instead.
+func (fg *FeatureGenerator) pluginFiles(p Plugin, feat string, files []File, err error) error {
+	if err != nil {
+		return &PluginError{Plugin: p.Name(), Err: err}
+	}
+	for _, f := range files {
+		key := filepath.ToSlash(filepath.Clean(f.Path))
+		switch {
+		case !filepath.IsLocal(f.Path) || strings.HasPrefix(key+"/", path.Dir(ManifestFile)+"/"):
+			err := fmt.Errorf("path must be inside the output dir, outside %s", path.Dir(ManifestFile))
+			return &PluginError{Plugin: p.Name(), File: f.Path, Err: err}
+		case fg.generated[key]:
+			return &PluginError{Plugin: p.Name(), File: key, Err: errors.New("file is already generated")}
+		}
+		content := f.Content
+		if strings.HasSuffix(key, ".go") {
+			formatted, err := formatGo(content, runtimeModule, fg.Config.ModulePath)
+			if err != nil {
+				return &PluginError{Plugin: p.Name(), File: key, Err: fmt.Errorf("invalid Go: %w", err)}
+			}
+			content = formatted
+		}
+		if err := fg.write(feat, key, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
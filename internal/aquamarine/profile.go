@@ -0,0 +1,71 @@
+package aquamarine
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Built-in profiles. ProfileProd is used when none is selected.
+const (
+	ProfileDev  = "dev"
+	ProfileProd = "prod"
+)
+
+// Profile returns the named profile with its defaults filled in: dev and
+// prod exist even when the spec does not declare them, and only dev builds
+// against the local checkout unless Local says otherwise.
+func (c *Config) Profile(name string) (Profile, error) {
+	p, declared := c.Profiles[name]
+	if !declared && name != ProfileDev && name != ProfileProd {
+		return Profile{}, fmt.Errorf("unknown profile %q (want one of %s)", name, strings.Join(c.ProfileNames(), ", "))
+	}
+	if p.Local == nil {
+		local := name == ProfileDev
+		p.Local = &local
+	}
+	return p, nil
+}
+
+// ApplyProfile overlays the runtime settings of the named profile on the
+// spec and returns the profile.
+func (c *Config) ApplyProfile(name string) (Profile, error) {
+	p, err := c.Profile(name)
+	if err != nil {
+		return Profile{}, err
+	}
+	c.Runtime = c.Runtime.overlay(p.Runtime)
+	return p, nil
+}
+
+// ProfileNames lists the built-in and declared profiles, sorted.
+func (c *Config) ProfileNames() []string {
+	names := []string{ProfileDev, ProfileProd}
+	for name := range c.Profiles {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// overlay returns rt with the values set in o replacing its own.
+func (rt RuntimeConfig) overlay(o RuntimeConfig) RuntimeConfig {
+	set := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	set(&rt.HTTP.API.Host, o.HTTP.API.Host)
+	set(&rt.HTTP.Web.Host, o.HTTP.Web.Host)
+	set(&rt.Database.Engine, o.Database.Engine)
+	set(&rt.Database.DSN, o.Database.DSN)
+	if o.HTTP.API.Port != 0 {
+		rt.HTTP.API.Port = o.HTTP.API.Port
+	}
+	if o.HTTP.Web.Port != 0 {
+		rt.HTTP.Web.Port = o.HTTP.Web.Port
+	}
+	return rt
+}
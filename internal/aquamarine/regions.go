@@ -0,0 +1,145 @@
+package aquamarine
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// User regions are blocks of a generated file that belong to the user. They
+// are delimited by marker lines, in whatever comment syntax the file uses:
+//
+//	// aquamarine:user Login
+//	return LoginResponse{}, ErrNotImplemented
+//	// aquamarine:end
+//
+// On regeneration the body of each region is carried over from the file on
+// disk, and region bodies are left out of the content hash so that editing
+// them does not count as a hand edit of the file.
+const (
+	regionBegin = "aquamarine:user"
+	regionEnd   = "aquamarine:end"
+)
+
+// region is a user region found in a file.
+type region struct {
+	name  string
+	start int // index of the first body line
+	end   int // index of the end marker line
+}
+
+// splitLines splits content into lines, keeping line terminators so that the
+// pieces join back into the exact original bytes.
+func splitLines(content []byte) []string {
+	return strings.SplitAfter(string(content), "\n")
+}
+
+// parseRegions returns the well-formed user regions of lines. Unterminated or
+// nested markers end the scan; whatever was found so far is returned.
+func parseRegions(lines []string) []region {
+	var regions []region
+	open := -1
+	name := ""
+	for i, line := range lines {
+		marker, arg := parseMarker(line)
+		switch marker {
+		case regionBegin:
+			if open >= 0 {
+				return regions
+			}
+			name = arg
+			open = i + 1
+		case regionEnd:
+			if open < 0 {
+				return regions
+			}
+			regions = append(regions, region{name: name, start: open, end: i})
+			open = -1
+		}
+	}
+	return regions
+}
+
+// parseMarker recognizes a line holding nothing but a region marker in a
+// comment, returning the marker and its argument.
+func parseMarker(line string) (string, string) {
+	s := strings.TrimSpace(line)
+	for _, c := range []string{"//", "#", "--", "<!--", "/*", "{{/*"} {
+		if strings.HasPrefix(s, c) {
+			s = strings.TrimSpace(s[len(c):])
+			break
+		}
+	}
+	for _, c := range []string{"*/}}", "-->", "*/"} {
+		s = strings.TrimSpace(strings.TrimSuffix(s, c))
+	}
+	fields := strings.Fields(s)
+	switch {
+	case len(fields) == 2 && fields[0] == regionBegin:
+		return regionBegin, fields[1]
+	case len(fields) == 1 && fields[0] == regionEnd:
+		return regionEnd, ""
+	}
+	return "", ""
+}
+
+// regionBodies maps region names to their bodies.
+func regionBodies(content []byte) map[string]string {
+	lines := splitLines(content)
+	bodies := map[string]string{}
+	for _, r := range parseRegions(lines) {
+		bodies[r.name] = strings.Join(lines[r.start:r.end], "")
+	}
+	return bodies
+}
+
+// mergeRegions returns generated with the body of every user region replaced
+// by the body of the same region in existing, when there is one.
+func mergeRegions(generated, existing []byte) []byte {
+	user := regionBodies(existing)
+	if len(user) == 0 {
+		return generated
+	}
+	lines := splitLines(generated)
+	var out bytes.Buffer
+	last := 0
+	for _, r := range parseRegions(lines) {
+		body, ok := user[r.name]
+		if !ok {
+			continue
+		}
+		out.WriteString(strings.Join(lines[last:r.start], ""))
+		out.WriteString(body)
+		last = r.end
+	}
+	out.WriteString(strings.Join(lines[last:], ""))
+	return out.Bytes()
+}
+
+// contentHash hashes content with user region bodies blanked.
+func contentHash(content []byte) string {
+	lines := splitLines(content)
+	h := sha256.New()
+	last := 0
+	for _, r := range parseRegions(lines) {
+		h.Write([]byte(strings.Join(lines[last:r.start], "")))
+		last = r.end
+	}
+	h.Write([]byte(strings.Join(lines[last:], "")))
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// regionHashes hashes the body of each user region.
+func regionHashes(content []byte) map[string]string {
+	bodies := regionBodies(content)
+	if len(bodies) == 0 {
+		return nil
+	}
+	hashes := make(map[string]string, len(bodies))
+	for name, body := range bodies {
+		sum := sha256.Sum256([]byte(body))
+		hashes[name] = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	return hashes
+}
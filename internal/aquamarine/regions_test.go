@@ -0,0 +1,84 @@
+package aquamarine
+
+import "testing"
+
+func TestMergeRegions(t *testing.T) {
+	tests := []struct {
+		name, generated, existing, want string
+	}{
+		{
+			name:      "no regions on disk",
+			generated: "a\n// aquamarine:user X\ngen\n// aquamarine:end\n",
+			existing:  "a\n",
+			want:      "a\n// aquamarine:user X\ngen\n// aquamarine:end\n",
+		},
+		{
+			name:      "body carried over",
+			generated: "b\n// aquamarine:user X\ngen\n// aquamarine:end\nc\n",
+			existing:  "a\n// aquamarine:user X\nmine\nmore\n// aquamarine:end\n",
+			want:      "b\n// aquamarine:user X\nmine\nmore\n// aquamarine:end\nc\n",
+		},
+		{
+			name:      "new region keeps its generated body",
+			generated: "// aquamarine:user X\ngen\n// aquamarine:end\n// aquamarine:user Y\ngen\n// aquamarine:end\n",
+			existing:  "// aquamarine:user X\nmine\n// aquamarine:end\n",
+			want:      "// aquamarine:user X\nmine\n// aquamarine:end\n// aquamarine:user Y\ngen\n// aquamarine:end\n",
+		},
+		{
+			name:      "regions matched by name",
+			generated: "// aquamarine:user B\n// aquamarine:end\n// aquamarine:user A\n// aquamarine:end\n",
+			existing:  "// aquamarine:user A\na\n// aquamarine:end\n// aquamarine:user B\nb\n// aquamarine:end\n",
+			want:      "// aquamarine:user B\nb\n// aquamarine:end\n// aquamarine:user A\na\n// aquamarine:end\n",
+		},
+		{
+			name:      "other comment syntax",
+			generated: "<p>\n  <!-- aquamarine:user Body -->\n  <!-- aquamarine:end -->\n</p>\n",
+			existing:  "<div>\n  <!-- aquamarine:user Body -->\n  <b>hi</b>\n  <!-- aquamarine:end -->\n</div>\n",
+			want:      "<p>\n  <!-- aquamarine:user Body -->\n  <b>hi</b>\n  <!-- aquamarine:end -->\n</p>\n",
+		},
+		{
+			name:      "unterminated region on disk",
+			generated: "// aquamarine:user X\ngen\n// aquamarine:end\n// aquamarine:user Y\ngen\n// aquamarine:end\n",
+			existing:  "// aquamarine:user X\nmine\n// aquamarine:end\n// aquamarine:user Y\nlost\n",
+			want:      "// aquamarine:user X\nmine\n// aquamarine:end\n// aquamarine:user Y\ngen\n// aquamarine:end\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := string(mergeRegions([]byte(tt.generated), []byte(tt.existing))); got != tt.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	a := []byte("a\n// aquamarine:user X\none\n// aquamarine:end\nb\n")
+	b := []byte("a\n// aquamarine:user X\ntwo\nthree\n// aquamarine:end\nb\n")
+	if contentHash(a) != contentHash(b) {
+		t.Error("region bodies change the content hash")
+	}
+	if contentHash(a) == contentHash([]byte("a\n// aquamarine:user X\none\n// aquamarine:end\nc\n")) {
+		t.Error("content outside regions does not change the hash")
+	}
+	if regionHashes(a)["X"] == regionHashes(b)["X"] {
+		t.Error("region hashes ignore the body")
+	}
+}
+
+func TestDroppedRegions(t *testing.T) {
+	generated := []byte("// aquamarine:user X\ngen\n// aquamarine:end\n// aquamarine:user Y\ngen\n// aquamarine:end\n")
+	entry := manifestEntry("f", generated)
+	tests := []struct {
+		name, existing, next string
+		want                 bool
+	}{
+		{"kept", "// aquamarine:user X\nmine\n// aquamarine:end\n", "// aquamarine:user X\n// aquamarine:end\n", false},
+		{"dropped untouched", string(generated), "// aquamarine:user X\ngen\n// aquamarine:end\n", false},
+		{"dropped edited", "// aquamarine:user X\ngen\n// aquamarine:end\n// aquamarine:user Y\nmine\n// aquamarine:end\n", "// aquamarine:user X\ngen\n// aquamarine:end\n", true},
+		{"renamed edited", "// aquamarine:user X\nmine\n// aquamarine:end\n", "// aquamarine:user Z\n// aquamarine:end\n", true},
+	}
+	for _, tt := range tests {
+		if got := droppedRegions(entry, []byte(tt.existing), []byte(tt.next)); got != tt.want {
+			t.Errorf("%s: droppedRegions = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package aquamarine
+
+import (
+	"slices"
+	"strings"
+)
+
+// foreignKey is a belongs_to link, declared or implied by a has_many: each
+// Model holds the id of one Target in its <Name>_id field.
+type foreignKey struct {
+	Model, Name, Target string
+	Nullable            bool
+	Implied             bool // declared by a has_many of Target
+}
+
+func (fk foreignKey) field() string {
+	return fk.Name + "_id"
+}
+
+// origin names the relation declaring fk, for messages.
+func (fk foreignKey) origin() string {
+	if fk.Implied {
+		return "the has_many of " + fk.Target
+	}
+	return "relation " + fk.Model + "." + fk.Name
+}
+
+// joinTable is a many_to_many link: the table pairs the ids of Owner and
+// Target values.
+type joinTable struct {
+	Owner, Name, Target string
+}
+
+func (jt joinTable) table() string {
+	return toSnakeCase(jt.Owner) + "_" + toSnakeCase(jt.Name)
+}
+
+func (jt joinTable) ownerColumn() string {
+	return toSnakeCase(jt.Owner) + "_id"
+}
+
+func (jt joinTable) targetColumn() string {
+	return toSnakeCase(singularize(jt.Name)) + "_id"
+}
+
+// featLinks resolves the relations of the models of feat, declared under fp
+// in src, in declaration order. A has_many names the belongs_to it mirrors
+// (via, or the single one of its model pointing back); without one it
+// implies a key named after the owning model. Relations to unknown models
+// are left out; CheckSpec reports them.
+func featLinks(src SourceMap, fp string, feat Feature) ([]foreignKey, []joinTable) {
+	var fks []foreignKey
+	var joins []joinTable
+	models := declared(src, fp+".models", feat.Models)
+	for _, model := range models {
+		rels := feat.Models[model].Relations
+		for _, name := range declared(src, fp+".models."+model+".relations", rels) {
+			r := rels[name]
+			if _, ok := feat.Models[r.Model]; !ok {
+				continue
+			}
+			switch r.Type {
+			case "belongs_to":
+				fks = append(fks, foreignKey{Model: model, Name: name, Target: r.Model, Nullable: r.Nullable})
+			case "many_to_many":
+				joins = append(joins, joinTable{Owner: model, Name: name, Target: r.Model})
+			case "has_many":
+				if r.Via == "" && len(belongsTo(feat.Models[r.Model], model)) == 0 {
+					fks = append(fks, foreignKey{Model: r.Model, Name: camel(model), Target: model, Implied: true})
+				}
+			}
+		}
+	}
+	return fks, joins
+}
+
+// belongsTo returns the names of the belongs_to relations of m to target.
+func belongsTo(m Model, target string) []string {
+	var names []string
+	for _, name := range sortedKeys(m.Relations) {
+		if r := m.Relations[name]; r.Type == "belongs_to" && r.Model == target {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// modelRef is a uuid field of a model of Feat holding the id of a Target of
+// the Owner feat (ref: owner.Target).
+type modelRef struct {
+	Feat, Model, Field string
+	Owner, Target      string
+}
+
+// port names the read-only interface through which Feat reaches the Owner
+// feat's Target values: AuthUserReader.
+func (r modelRef) port() string {
+	return pascal(r.Owner) + r.Target + "Reader"
+}
+
+// method names the port method, which the Owner feat's service implements.
+func (r modelRef) method() string {
+	return "Has" + r.Target
+}
+
+// modelRefs returns the ref fields of the models of feat, in declaration
+// order.
+func modelRefs(src SourceMap, featName string, feat Feature) []modelRef {
+	var refs []modelRef
+	fp := join("feats", featName)
+	for _, model := range declared(src, fp+".models", feat.Models) {
+		fields := feat.Models[model].Fields
+		for _, field := range declared(src, fp+".models."+model+".fields", fields) {
+			if ref := fields[field].Ref; ref != "" {
+				owner, target, _ := strings.Cut(ref, ".")
+				refs = append(refs, modelRef{Feat: featName, Model: model, Field: field, Owner: owner, Target: target})
+			}
+		}
+	}
+	return refs
+}
+
+// validRefs returns the refs of every feat to a model of another feat with
+// a repo. Others are left out; CheckSpec reports them.
+func (c *Config) validRefs() []modelRef {
+	var refs []modelRef
+	for _, name := range declared(c.Source, "feats", c.Feats) {
+		for _, r := range modelRefs(c.Source, name, c.Feats[name]) {
+			owner := c.Feats[r.Owner]
+			if _, ok := owner.Models[r.Target]; ok && r.Owner != name && !childModels(owner)[r.Target] {
+				refs = append(refs, r)
+			}
+		}
+	}
+	return refs
+}
+
+// referenced returns the models of feat other feats refer to, sorted.
+func (c *Config) referenced(feat string) []string {
+	var models []string
+	for _, r := range c.validRefs() {
+		if r.Owner == feat && !slices.Contains(models, r.Target) {
+			models = append(models, r.Target)
+		}
+	}
+	slices.Sort(models)
+	return models
+}
@@ -0,0 +1,142 @@
+package aquamarine
+
+import (
+	"cmp"
+	"strings"
+	"testing"
+)
+
+const relationsSpec = `version: 0.2
+project: {name: blog, module: example.com/blog}
+runtime: {http: {api: {port: 8081}, web: {port: 8080}}}
+feats:
+  - name: blog
+    models:
+      User:
+        fields:
+          email: {type: email}
+        relations:
+          posts: {type: has_many, model: Post}
+      Role:
+        fields:
+          name: {type: string}
+      Post:
+        fields:
+          title: {type: string}
+`
+
+func TestRelationErrors(t *testing.T) {
+	tests := []struct {
+		fields, relations, path, want string
+	}{
+		{"", "{author: {type: belongs_to, model: Writer}}", "models.Post.relations.author.model", `relation "author" refers to unknown model "Writer"`},
+		{"", "{author: {type: owns, model: User}}", "models.Post.relations.author.type", `unknown relation type "owns"`},
+		{"", "{author: {type: belongs_to, model: User, via: posts}}", "models.Post.relations.author.via", "via only applies to has_many relations"},
+		{"", "{tags: {type: has_many, model: User, nullable: true}}", "models.Post.relations.tags.nullable", "nullable only applies to belongs_to relations"},
+		{"", "{author: {type: belongs_to, model: User}, editor: {type: belongs_to, model: User}}", "models.User.relations.posts", "Post belongs to User through author and editor: set via to one of them"},
+		{"", "{user: {type: belongs_to, model: Role}}", "models.Post.relations.user", "Post.user_id is added by both the has_many of User and relation Post.user"},
+		{"user_id: {type: uuid}", "{}", "models.User.relations", "Post.user_id is a declared field, but the has_many of User adds it"},
+		{"", "{roles: {type: many_to_many, model: Role}}", "", ""},
+	}
+	for _, tt := range tests {
+		spec := relationsSpec + "          " + cmp.Or(tt.fields, "body: {type: text}") + "\n        relations: " + tt.relations + "\n"
+		cfg, diags := ParseSpec("aquamarine.yaml", []byte(spec))
+		if !diags.HasErrors() {
+			diags = CheckSpec(cfg)
+		}
+		if tt.want == "" {
+			if len(diags) > 0 {
+				t.Errorf("%s: got %v, want no diagnostics", tt.relations, diags)
+			}
+			continue
+		}
+		if len(diags) != 1 || !strings.HasSuffix(diags[0].Path, tt.path) || !strings.Contains(diags[0].Message, tt.want) {
+			t.Errorf("%s: got %v, want %s: %s", tt.relations, diags, tt.path, tt.want)
+		}
+	}
+}
+
+func TestRefErrors(t *testing.T) {
+	const spec = `version: 0.2
+project: {name: social, module: example.com/social}
+runtime: {http: {api: {port: 8081}, web: {port: 8080}}}
+feats:
+  - name: profile
+    models:
+      Profile: {fields: {user_id: {type: uuid, ref: auth.User}}}
+  - name: auth
+    models:
+      User: {fields: {email: {type: email}}}
+`
+	tests := []struct {
+		from, to, path, want string
+	}{
+		{"ref: auth.User", "ref: auth.user", "feats.profile.models.Profile.fields.user_id.ref", `invalid ref "auth.user" (want feat.Model)`},
+		{"type: uuid, ref", "type: string, ref", "feats.profile.models.Profile.fields.user_id.ref", "ref only applies to uuid fields, not string"},
+		{"ref: auth.User", "ref: users.User", "feats.profile.models.Profile.fields.user_id.ref", `ref to unknown feat "users"`},
+		{"ref: auth.User", "ref: auth.Role", "feats.profile.models.Profile.fields.user_id.ref", `ref to unknown model "Role" of feat auth`},
+		{"ref: auth.User", "ref: profile.Profile", "feats.profile.models.Profile.fields.user_id.ref", "ref profile.Profile is in this feat: use a belongs_to relation"},
+		{"{email: {type: email}}", "{email: {type: email}, profile_id: {type: uuid, ref: profile.Profile}}", "feats.profile.models.Profile.fields.user_id.ref", "dependency cycle: profile -> auth -> profile"},
+	}
+	for _, tt := range tests {
+		cfg, diags := ParseSpec("aquamarine.yaml", []byte(strings.Replace(spec, tt.from, tt.to, 1)))
+		if !diags.HasErrors() {
+			diags = CheckSpec(cfg)
+		}
+		if len(diags) != 1 || diags[0].Path != tt.path || diags[0].Message != tt.want {
+			t.Errorf("%s: got %v, want %s: %s", tt.to, diags, tt.path, tt.want)
+		}
+	}
+
+	cfg, diags := ParseSpec("aquamarine.yaml", []byte(spec))
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	if order, err := cfg.FeatOrder(); err != nil || strings.Join(order, ",") != "auth,profile" {
+		t.Errorf("FeatOrder() = %v, %v; want auth before the profile feat referring to it", order, err)
+	}
+}
+
+func TestAggregateErrors(t *testing.T) {
+	const spec = `version: 0.2
+project: {name: orders, module: example.com/orders}
+runtime: {http: {api: {port: 8081}, web: {port: 8080}}}
+feats:
+  - name: ordering
+    models:
+      Order: {fields: {customer: {type: string}}}
+      LineItem: {fields: {quantity: {type: int}}}
+      Product: {fields: {sku: {type: string}}}
+    aggregates:
+      Order:
+        children:
+          items: {of: LineItem}
+`
+	tests := []struct {
+		from, to, path, want string
+	}{
+		{"Order:\n        children", "Cart:\n        children", "feats.ordering.aggregates.Cart", `aggregate "Cart" is not a model of the feat`},
+		{"{of: LineItem}", "{of: Item}", "feats.ordering.aggregates.Order.children.items.of", `child collection "items" refers to unknown model "Item"`},
+		{"{of: LineItem}", "{of: LineItem}\n          lines: {of: Order}", "feats.ordering.aggregates.Order.children.lines.of", `child collection "lines" holds Order, an aggregate root`},
+		{"items: {of", "item: {of", "feats.ordering.aggregates.Order.children.item", `child collection name "item" must be plural, as it names a list`},
+		{"{customer: {type: string}}", "{customer: {type: string}, version: {type: int}}", "feats.ordering.aggregates.Order", `version field "version" clashes with a field of Order: set version_field to another name`},
+		{"{quantity: {type: int}}", "{quantity: {type: int}, order_id: {type: uuid}}", "feats.ordering.aggregates.Order.children.items", "LineItem already has order_id, the column of table order_items holding the Order id"},
+		{"{sku: {type: string}}", "{sku: {type: string}}, relations: {items: {type: has_many, model: LineItem}}", "feats.ordering.models.Product.relations.items", `relation "items" refers to LineItem, which lives in a child collection`},
+		{"{quantity: {type: int}}", "{quantity: {type: int}}, relations: {product: {type: belongs_to, model: Product}}", "", ""},
+	}
+	for _, tt := range tests {
+		cfg, diags := ParseSpec("aquamarine.yaml", []byte(strings.Replace(spec, tt.from, tt.to, 1)))
+		if !diags.HasErrors() {
+			diags = CheckSpec(cfg)
+		}
+		if tt.want == "" {
+			if len(diags) > 0 {
+				t.Errorf("%s: got %v, want no diagnostics", tt.to, diags)
+			}
+			continue
+		}
+		if len(diags) != 1 || diags[0].Path != tt.path || diags[0].Message != tt.want {
+			t.Errorf("%s: got %v, want %s: %s", tt.to, diags, tt.path, tt.want)
+		}
+	}
+}
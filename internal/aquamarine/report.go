@@ -0,0 +1,196 @@
+package aquamarine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Report output formats accepted by the validate command.
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+)
+
+// WriteReport renders diagnostics for spec in the requested format.
+func WriteReport(w io.Writer, format, spec string, diags Diagnostics) error {
+	switch format {
+	case FormatText, "":
+		return writeTextReport(w, spec, diags)
+	case FormatJSON:
+		return writeJSONReport(w, spec, diags)
+	case FormatSARIF:
+		return writeSARIFReport(w, diags)
+	default:
+		return fmt.Errorf("unknown format %q (want %s|%s|%s)", format, FormatText, FormatJSON, FormatSARIF)
+	}
+}
+
+func writeTextReport(w io.Writer, spec string, diags Diagnostics) error {
+	for _, d := range diags {
+		if _, err := fmt.Fprintln(w, d.String()); err != nil {
+			return err
+		}
+	}
+	errs := len(diags.Errors())
+	warns := len(diags) - errs
+	if errs == 0 {
+		_, err := fmt.Fprintf(w, "%s: ok (%s)\n", spec, count(warns, "warning"))
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s: %s, %s\n", spec, count(errs, "error"), count(warns, "warning"))
+	return err
+}
+
+// count returns n and noun, in the plural unless n is 1: "1 error", "0 errors".
+func count(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+type jsonReport struct {
+	Spec        string      `json:"spec"`
+	Valid       bool        `json:"valid"`
+	Diagnostics Diagnostics `json:"diagnostics"`
+}
+
+func writeJSONReport(w io.Writer, spec string, diags Diagnostics) error {
+	if diags == nil {
+		diags = Diagnostics{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(jsonReport{Spec: spec, Valid: !diags.HasErrors(), Diagnostics: diags})
+}
+
+// SARIF 2.1.0 subset, enough for code scanning uploads.
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           *sarifRegion  `json:"region,omitempty"`
+}
+
+type sarifArtifact struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+// sarifSrcRoot is the base of relative artifact URIs: the directory validate
+// ran in, which code scanning maps to the repository root.
+const sarifSrcRoot = "%SRCROOT%"
+
+// sarifArtifactFor locates file for SARIF readers: a slash-separated URI
+// relative to the working directory, or a file URI outside it.
+func sarifArtifactFor(file string) sarifArtifact {
+	rel := filepath.Clean(file)
+	if filepath.IsAbs(rel) {
+		if wd, err := os.Getwd(); err == nil {
+			if r, err := filepath.Rel(wd, rel); err == nil {
+				rel = r
+			}
+		}
+	}
+	if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		abs, _ := filepath.Abs(file)
+		p := filepath.ToSlash(abs)
+		if !strings.HasPrefix(p, "/") {
+			p = "/" + p // a Windows drive letter
+		}
+		return sarifArtifact{URI: (&url.URL{Scheme: "file", Path: p}).String()}
+	}
+	return sarifArtifact{URI: (&url.URL{Path: filepath.ToSlash(rel)}).String(), URIBaseID: sarifSrcRoot}
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+func writeSARIFReport(w io.Writer, diags Diagnostics) error {
+	rules := map[string]bool{}
+	results := make([]sarifResult, 0, len(diags))
+	for _, d := range diags {
+		rules[d.Rule] = true
+		loc := sarifPhysicalLocation{ArtifactLocation: sarifArtifactFor(d.File)}
+		if d.Line > 0 {
+			loc.Region = &sarifRegion{StartLine: d.Line, StartColumn: d.Column}
+		}
+		msg := d.Message
+		if d.Path != "" {
+			msg = d.Path + ": " + msg
+		}
+		results = append(results, sarifResult{
+			RuleID:    d.Rule,
+			Level:     string(d.Severity),
+			Message:   sarifMessage{Text: msg},
+			Locations: []sarifLocation{{PhysicalLocation: loc}},
+		})
+	}
+	ids := make([]string, 0, len(rules))
+	for id := range rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	driver := sarifDriver{
+		Name:           "aquamarine",
+		InformationURI: "https://github.com/aquamarinepk/aquamarine",
+		Rules:          make([]sarifRule, 0, len(ids)),
+	}
+	for _, id := range ids {
+		driver.Rules = append(driver.Rules, sarifRule{ID: id})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
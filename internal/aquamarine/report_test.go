@@ -0,0 +1,113 @@
+package aquamarine
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var reportDiags = Diagnostics{
+	{Rule: RuleSchema, File: "aquamarine.yaml", Line: 3, Column: 5, Path: "feats.orders.kind", Severity: SeverityError, Message: `unknown kind "x"`},
+	{Rule: RuleHandler, File: "feats/auth.yaml", Path: "api", Severity: SeverityWarning, Message: "no routes"},
+}
+
+func TestTextReport(t *testing.T) {
+	tests := []struct {
+		diags Diagnostics
+		want  string
+	}{
+		{nil, "aquamarine.yaml: ok (0 warnings)\n"},
+		{reportDiags[1:], "feats/auth.yaml: warning: api: no routes\naquamarine.yaml: ok (1 warning)\n"},
+		{reportDiags[:1], "aquamarine.yaml:3:5: feats.orders.kind: unknown kind \"x\"\naquamarine.yaml: 1 error, 0 warnings\n"},
+		{append(reportDiags, reportDiags[0]), "aquamarine.yaml: 2 errors, 1 warning\n"},
+	}
+	for _, tt := range tests {
+		var b bytes.Buffer
+		if err := WriteReport(&b, FormatText, "aquamarine.yaml", tt.diags); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasSuffix(b.String(), tt.want) {
+			t.Errorf("got\n%s\nwant it to end in\n%s", b.String(), tt.want)
+		}
+	}
+}
+
+func TestJSONReport(t *testing.T) {
+	for _, diags := range []Diagnostics{nil, reportDiags} {
+		var b bytes.Buffer
+		if err := WriteReport(&b, FormatJSON, "aquamarine.yaml", diags); err != nil {
+			t.Fatal(err)
+		}
+		var got jsonReport
+		if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Spec != "aquamarine.yaml" || got.Valid != !diags.HasErrors() || len(got.Diagnostics) != len(diags) {
+			t.Errorf("got %+v for %v", got, diags)
+		}
+		if diags == nil && !strings.Contains(b.String(), `"diagnostics": []`) {
+			t.Errorf("no diagnostics must be an empty list:\n%s", b.String())
+		}
+	}
+}
+
+func TestSARIFReport(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteReport(&b, FormatSARIF, "aquamarine.yaml", reportDiags); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(b.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("got %+v", log)
+	}
+	run := log.Runs[0]
+	if rules := run.Tool.Driver.Rules; len(rules) != 2 || rules[0].ID != RuleSchema || rules[1].ID != RuleHandler {
+		t.Errorf("rules = %+v, want schema and unknown-handler in name order", rules)
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("results = %+v", run.Results)
+	}
+	r := run.Results[0]
+	loc := r.Locations[0].PhysicalLocation
+	if r.RuleID != RuleSchema || r.Level != "error" || r.Message.Text != `feats.orders.kind: unknown kind "x"` ||
+		loc.ArtifactLocation != (sarifArtifact{URI: "aquamarine.yaml", URIBaseID: sarifSrcRoot}) || loc.Region == nil || loc.Region.StartLine != 3 || loc.Region.StartColumn != 5 {
+		t.Errorf("result = %+v at %+v", r, loc)
+	}
+	if r := run.Results[1]; r.Level != "warning" || r.Locations[0].PhysicalLocation.Region != nil {
+		t.Errorf("result without a line = %+v", r)
+	}
+}
+
+func TestSARIFArtifact(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent, _ := filepath.Abs("..")
+	tests := []struct {
+		file string
+		want sarifArtifact
+	}{
+		{"feats/auth.yaml", sarifArtifact{URI: "feats/auth.yaml", URIBaseID: sarifSrcRoot}},
+		{"./feats/../aquamarine.yaml", sarifArtifact{URI: "aquamarine.yaml", URIBaseID: sarifSrcRoot}},
+		{filepath.Join(wd, "feats", "my auth.yaml"), sarifArtifact{URI: "feats/my%20auth.yaml", URIBaseID: sarifSrcRoot}},
+		{filepath.Join("..", "aquamarine.yaml"), sarifArtifact{URI: "file://" + filepath.ToSlash(filepath.Join(parent, "aquamarine.yaml"))}},
+	}
+	for _, tt := range tests {
+		if got := sarifArtifactFor(tt.file); got != tt.want {
+			t.Errorf("sarifArtifactFor(%q) = %+v, want %+v", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestReportFormat(t *testing.T) {
+	if err := WriteReport(&bytes.Buffer{}, "xml", "aquamarine.yaml", nil); err == nil || !strings.Contains(err.Error(), `unknown format "xml"`) {
+		t.Errorf("got %v", err)
+	}
+}
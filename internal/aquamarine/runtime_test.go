@@ -0,0 +1,80 @@
+package aquamarine
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aquamarinepk/aquamarine/assets"
+)
+
+// TestRuntime generates every testdata/golden/<case> that has a
+// testdata/runtime/<case> tree in dev mode, adds that tree's tests to the
+// output and runs them: they exercise the generated code against a real
+// database. Skipped in -short mode and when the generated app's
+// dependencies cannot be fetched.
+func TestRuntime(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds generated apps")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("no go tool")
+	}
+	cases, err := filepath.Glob("testdata/runtime/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range cases {
+		name := filepath.Base(dir)
+		t.Run(name, func(t *testing.T) {
+			cfg, _, err := LoadSpec(filepath.Join("testdata", "golden", name, DefaultSpecFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			out := t.TempDir()
+			fg, err := NewFeatureGenerator(*cfg, out, true, assets.Templates())
+			if err != nil {
+				t.Fatal(err)
+			}
+			plan, err := fg.Plan()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := plan.Apply(); err != nil {
+				t.Fatal(err)
+			}
+			err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+				rel, err := filepath.Rel(dir, p)
+				if err != nil {
+					return err
+				}
+				b, err := os.ReadFile(p)
+				if err != nil {
+					return err
+				}
+				return safeWriteFile(filepath.Join(out, rel), b, 0o644)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cmd := exec.Command("go", "test", "./...")
+			cmd.Dir = out
+			cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+			b, err := cmd.CombinedOutput()
+			if err != nil {
+				if s := string(b); strings.Contains(s, "cannot find module") || strings.Contains(s, "module lookup disabled") || strings.Contains(s, "dial tcp") {
+					t.Skipf("dependencies unavailable:\n%s", b)
+				}
+				t.Fatalf("go test in the generated app:\n%s", b)
+			}
+			t.Logf("%s", b)
+		})
+	}
+}
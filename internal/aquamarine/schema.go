@@ -0,0 +1,233 @@
+package aquamarine
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// jsonSchema is a JSON Schema (draft-07) node.
+type jsonSchema map[string]any
+
+// Schema returns a JSON Schema for aquamarine.yaml at SpecVersion. It is
+// derived from Config by reflection, keyed by yaml tags, then refined with
+// what the spec decoder enforces on top of the Go types: enums, name
+// patterns, required keys and the shorthand of validations as names.
+func Schema() jsonSchema {
+	b := &schemaBuilder{defs: map[string]jsonSchema{}}
+	root := b.object(reflect.TypeOf(Config{}))
+	root["$schema"] = "http://json-schema.org/draft-07/schema#"
+	root["title"] = "aquamarine.yaml"
+	root["description"] = "Aquamarine spec " + SpecVersion + ": an app as feats, models and routes"
+
+	// Feats are a list of named feats.
+	named := jsonSchema{"allOf": []any{ref("Feature"), jsonSchema{"required": []string{"name"}}}}
+	b.defs["Config"]["properties"].(map[string]any)["feats"] = jsonSchema{
+		"oneOf": []any{
+			jsonSchema{"type": "array", "items": named},
+			jsonSchema{"type": "null"},
+		},
+	}
+
+	// Validations are a bare name or a single {name: value} pair.
+	var bare, valued []string
+	for _, name := range sortedKeys(validationArgs) {
+		if validationArgs[name] == "" {
+			bare = append(bare, name)
+		} else {
+			valued = append(valued, name)
+		}
+	}
+	pairs := map[string]any{}
+	for _, name := range valued {
+		if validationArgs[name] == "int" {
+			pairs[name] = jsonSchema{"type": "integer"}
+		} else {
+			pairs[name] = jsonSchema{"type": "string", "format": "regex"}
+		}
+	}
+	b.defs["Validation"] = jsonSchema{
+		"oneOf": []any{
+			jsonSchema{"type": "string", "enum": bare},
+			jsonSchema{"type": "object", "properties": pairs, "additionalProperties": false, "minProperties": 1, "maxProperties": 1},
+		},
+	}
+
+	for path, fn := range schemaRefinements {
+		typ, key, _ := strings.Cut(path, ".")
+		fn(b.defs[typ]["properties"].(map[string]any)[key].(jsonSchema))
+	}
+	for typ, keys := range schemaRequired {
+		b.defs[typ]["required"] = keys
+	}
+
+	// The spec's runtime needs both ports; a profile's only overlays it.
+	props := b.defs["Config"]["properties"].(map[string]any)
+	props["runtime"] = jsonSchema{"allOf": []any{props["runtime"], jsonSchema{
+		"required": []string{"http"},
+		"properties": map[string]any{"http": jsonSchema{
+			"required": []string{"api", "web"},
+			"properties": map[string]any{
+				"api": jsonSchema{"required": []string{"port"}},
+				"web": jsonSchema{"required": []string{"port"}},
+			},
+		}},
+	}}}
+
+	root["definitions"] = b.defs
+	delete(b.defs, "Config")
+	return root
+}
+
+// WriteSchema writes Schema as indented JSON.
+func WriteSchema(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(Schema())
+}
+
+// schemaRefinements adjust the reflected schema of Type.key properties.
+var schemaRefinements = map[string]func(jsonSchema){
+	"Config.version":          version,
+	"ProjectConfig.name":      setPattern(projectNameRe.String()),
+	"ProjectConfig.module":    setPattern(modulePathRe.String()),
+	"APIConfig.port":          port,
+	"WebConfig.port":          port,
+	"DatabaseConfig.engine":   enum(allowedEngines),
+	"Config.profiles":         keys(profileNameRe.String()),
+	"Feature.name":            setPattern(featNameRe.String()),
+	"Feature.kind":            enum(allowedKinds),
+	"Feature.models":          keys(typeNameRe.String()),
+	"Feature.repo_impl":       items(enum(allowedEngines)),
+	"Feature.aggregates":      keys(typeNameRe.String()),
+	"ServiceConfig.methods":   items(setPattern(typeNameRe.String())),
+	"RouteConfig.method":      enum(allowedMethods),
+	"RouteConfig.path":        setPattern("^/"),
+	"RouteConfig.handler":     setPattern(typeNameRe.String()),
+	"PageConfig.route":        setPattern(`^(` + strings.Join(allowedMethods, "|") + `) /`),
+	"Model.fields":            keys(identRe.String()),
+	"Field.type":              enum(fieldTypes),
+	"Field.of":                enum(listElemTypes()),
+	"Field.default":           scalar,
+	"Field.ref":               setPattern(refRe.String()),
+	"Model.relations":         keys(identRe.String()),
+	"Relation.type":           enum(relationTypes),
+	"Relation.model":          setPattern(typeNameRe.String()),
+	"Aggregate.fields":        keys(identRe.String()),
+	"Aggregate.version_field": setPattern(identRe.String()),
+	"Aggregate.children":      keys(identRe.String()),
+	"TemplatesConfig.packs":   items(setPattern(`^(\.|.*[/\\]|[a-z0-9][a-z0-9._-]*$)`)),
+	"OrderingConfig.requires": items(items(setPattern(featNameRe.String()))),
+	"Feature.requires":        items(setPattern(featNameRe.String())),
+}
+
+// schemaRequired lists the keys the decoder requires, per type.
+var schemaRequired = map[string][]string{
+	"Config":        {"version", "project", "runtime"},
+	"ProjectConfig": {"name", "module"},
+	"Field":         {"type"},
+	"RouteConfig":   {"method", "path", "handler"},
+	"PageConfig":    {"route"},
+	"ChildConfig":   {"of"},
+	"Relation":      {"type", "model"},
+}
+
+// schemaBuilder reflects Go types into schemas, one definition per struct.
+type schemaBuilder struct {
+	defs map[string]jsonSchema
+}
+
+func (b *schemaBuilder) typ(t reflect.Type) jsonSchema {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return b.typ(t.Elem())
+	case reflect.Struct:
+		if _, ok := b.defs[t.Name()]; !ok {
+			b.object(t)
+		}
+		return ref(t.Name())
+	case reflect.Map:
+		return jsonSchema{"type": "object", "additionalProperties": b.typ(t.Elem())}
+	case reflect.Slice:
+		return jsonSchema{"type": "array", "items": b.typ(t.Elem())}
+	case reflect.Array:
+		return jsonSchema{"type": "array", "items": b.typ(t.Elem()), "minItems": t.Len(), "maxItems": t.Len()}
+	case reflect.Bool:
+		return jsonSchema{"type": "boolean"}
+	case reflect.Int, reflect.Int64:
+		return jsonSchema{"type": "integer"}
+	default:
+		return jsonSchema{"type": "string"}
+	}
+}
+
+// object defines the schema of struct t under its name and returns it.
+// Fields without a yaml key are skipped.
+func (b *schemaBuilder) object(t reflect.Type) jsonSchema {
+	s := jsonSchema{"type": "object", "additionalProperties": false}
+	b.defs[t.Name()] = s
+	props := map[string]any{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+		props[key] = b.typ(f.Type)
+	}
+	s["properties"] = props
+	return s
+}
+
+func ref(name string) jsonSchema {
+	return jsonSchema{"$ref": "#/definitions/" + name}
+}
+
+func pattern(re string) jsonSchema {
+	return jsonSchema{"type": "string", "pattern": re}
+}
+
+func setPattern(re string) func(jsonSchema) {
+	return func(s jsonSchema) { s["pattern"] = re }
+}
+
+func enum(values []string) func(jsonSchema) {
+	return func(s jsonSchema) { s["enum"] = values }
+}
+
+func keys(re string) func(jsonSchema) {
+	return func(s jsonSchema) { s["propertyNames"] = pattern(re) }
+}
+
+func items(fn func(jsonSchema)) func(jsonSchema) {
+	return func(s jsonSchema) { fn(s["items"].(jsonSchema)) }
+}
+
+// version is SpecVersion, also as a number: YAML reads 0.2 as one. Older
+// specs are for aquamarine upgrade.
+func version(s jsonSchema) {
+	delete(s, "type")
+	n, _ := strconv.ParseFloat(SpecVersion, 64)
+	s["anyOf"] = []any{
+		pattern(`^` + regexp.QuoteMeta(SpecVersion) + `(\.\d+)?(-[0-9A-Za-z.-]+)?$`),
+		jsonSchema{"type": "number", "enum": []any{n}},
+	}
+}
+
+// port also accepts a ${VAR} reference, expanded when the spec is loaded.
+func port(s jsonSchema) {
+	delete(s, "type")
+	s["anyOf"] = []any{
+		jsonSchema{"type": "integer", "minimum": 1, "maximum": 65535},
+		jsonSchema{"type": "string", "pattern": `\$\{`},
+	}
+}
+
+// scalar is any YAML scalar: the decoder reads it as text and checks it
+// against the field type.
+func scalar(s jsonSchema) {
+	s["type"] = []string{"string", "number", "boolean"}
+}
@@ -0,0 +1,226 @@
+package aquamarine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TestSchema checks the golden specs against the schema, and that it rejects
+// what the decoder rejects.
+func TestSchema(t *testing.T) {
+	var schema map[string]any
+	b, err := json.Marshal(Schema())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatal(err)
+	}
+	v := schemaValidator{defs: schema["definitions"].(map[string]any)}
+
+	specs, err := filepath.Glob("testdata/golden/*/aquamarine.yaml")
+	if err != nil || len(specs) == 0 {
+		t.Fatalf("no golden specs: %v", err)
+	}
+	for _, spec := range specs {
+		b, err := os.ReadFile(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc any
+		if err := yaml.Unmarshal(b, &doc); err != nil {
+			t.Fatal(err)
+		}
+		if err := v.check(schema, doc, ""); err != nil {
+			t.Errorf("%s: %v", spec, err)
+		}
+	}
+
+	base := "version: 0.2\nproject: {name: x, module: example.com/x}\nruntime: {http: {api: {port: 8081}, web: {port: 8080}}}\n"
+	for _, bad := range []string{
+		"feats: [{name: a, colour: red}]",
+		"feats: [{name: a, kind: service}]",
+		"feats: [{kind: atom}]",
+		"feats: [{name: a, model: {fields: {}}}]",
+		"feats: {a: {models: {user: {}}}}",
+		"feats: [{name: a, models: {User: {fields: {id: {}}}}}]",
+		"feats: [{name: a, models: {User: {fields: {id: {type: uuid, validations: [min]}}}}}]",
+		"feats: [{name: a, api: {routes: [{method: GET, path: me, handler: Me}]}}]",
+		"profiles: {Staging: {}}",
+		"profiles: {staging: {runtime: {http: {api: {port: 70000}}}}}",
+	} {
+		var doc any
+		if err := yaml.Unmarshal([]byte(base+bad), &doc); err != nil {
+			t.Fatal(err)
+		}
+		if v.check(schema, doc, "") == nil {
+			t.Errorf("schema accepts %s", bad)
+		}
+		if _, diags := ParseSpec("bad.yaml", []byte(base+bad)); !diags.HasErrors() {
+			t.Errorf("decoder accepts %s", bad)
+		}
+	}
+}
+
+// schemaValidator checks a document against the subset of draft-07 Schema
+// produces.
+type schemaValidator struct {
+	defs map[string]any
+}
+
+func (v schemaValidator) check(s map[string]any, doc any, path string) error {
+	if r, ok := s["$ref"].(string); ok {
+		return v.check(v.defs[filepath.Base(r)].(map[string]any), doc, path)
+	}
+	if all, ok := s["allOf"].([]any); ok {
+		for _, sub := range all {
+			if err := v.check(sub.(map[string]any), doc, path); err != nil {
+				return err
+			}
+		}
+	}
+	if some, ok := s["anyOf"].([]any); ok {
+		matched := false
+		for _, sub := range some {
+			matched = matched || v.check(sub.(map[string]any), doc, path) == nil
+		}
+		if !matched {
+			return fmt.Errorf("%s: matches none of anyOf", path)
+		}
+	}
+	if one, ok := s["oneOf"].([]any); ok {
+		matched := 0
+		for _, sub := range one {
+			if v.check(sub.(map[string]any), doc, path) == nil {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return fmt.Errorf("%s: matches %d of oneOf", path, matched)
+		}
+	}
+	if typ, ok := s["type"]; ok && !hasType(typ, doc) {
+		return fmt.Errorf("%s: %v is not of type %v", path, doc, typ)
+	}
+	if enum, ok := s["enum"].([]any); ok && !slices.Contains(enum, doc) {
+		return fmt.Errorf("%s: %v not in %v", path, doc, enum)
+	}
+	if p, ok := s["pattern"].(string); ok {
+		if str, ok := doc.(string); ok && !regexp.MustCompile(p).MatchString(str) {
+			return fmt.Errorf("%s: %q does not match %s", path, str, p)
+		}
+	}
+	switch doc := doc.(type) {
+	case int:
+		if min, ok := s["minimum"].(float64); ok && float64(doc) < min {
+			return fmt.Errorf("%s: %d below %v", path, doc, min)
+		}
+		if max, ok := s["maximum"].(float64); ok && float64(doc) > max {
+			return fmt.Errorf("%s: %d above %v", path, doc, max)
+		}
+	case []any:
+		if items, ok := s["items"].(map[string]any); ok {
+			for i, item := range doc {
+				if err := v.check(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+		if n, ok := s["minItems"].(float64); ok && len(doc) < int(n) {
+			return fmt.Errorf("%s: fewer than %v items", path, n)
+		}
+	case map[string]any:
+		for _, req := range asStrings(s["required"]) {
+			if _, ok := doc[req]; !ok {
+				return fmt.Errorf("%s: %s is required", path, req)
+			}
+		}
+		if n, ok := s["maxProperties"].(float64); ok && len(doc) > int(n) {
+			return fmt.Errorf("%s: more than %v keys", path, n)
+		}
+		props, _ := s["properties"].(map[string]any)
+		for key, val := range doc {
+			kp := join(path, key)
+			if names, ok := s["propertyNames"].(map[string]any); ok {
+				if err := v.check(names, key, kp); err != nil {
+					return err
+				}
+			}
+			sub, ok := props[key].(map[string]any)
+			if !ok {
+				switch extra := s["additionalProperties"].(type) {
+				case bool:
+					if !extra {
+						return fmt.Errorf("%s: unknown key", kp)
+					}
+					continue
+				case map[string]any:
+					sub = extra
+				default:
+					continue
+				}
+			}
+			if err := v.check(sub, val, kp); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func hasType(typ, doc any) bool {
+	for _, t := range asStrings(typ) {
+		switch doc.(type) {
+		case string:
+			if t == "string" {
+				return true
+			}
+		case int:
+			if t == "integer" || t == "number" {
+				return true
+			}
+		case float64:
+			if t == "number" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case []any:
+			if t == "array" {
+				return true
+			}
+		case map[string]any:
+			if t == "object" {
+				return true
+			}
+		case nil:
+			if t == "null" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func asStrings(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var out []string
+		for _, s := range v {
+			out = append(out, s.(string))
+		}
+		return out
+	}
+	return nil
+}
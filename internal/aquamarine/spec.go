@@ -0,0 +1,1246 @@
+package aquamarine
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultSpecFile is the spec looked up when no path is given.
+const DefaultSpecFile = "aquamarine.yaml"
+
+const defaultHost = "127.0.0.1"
+
+var (
+	allowedKinds   = []string{"atom", "domain", "web"}
+	allowedMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+	allowedEngines = []string{"sqlite", "mongodb"}
+	relationTypes  = []string{"belongs_to", "has_many", "many_to_many"}
+	fieldTypes     = fieldTypeNames()
+)
+
+// validationArgs tells which argument, if any, each validation rule takes.
+var validationArgs = map[string]string{
+	"required":   "",
+	"unique":     "",
+	"email":      "",
+	"min":        "int",
+	"max":        "int",
+	"min_length": "int",
+	"max_length": "int",
+	"pattern":    "regexp",
+}
+
+var (
+	semverRe      = regexp.MustCompile(`^\d+\.\d+(\.\d+)?(-[0-9A-Za-z.-]+)?$`)
+	projectNameRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+	modulePathRe  = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._~-]*(/[A-Za-z0-9._~-]+)*$`)
+	featNameRe    = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	typeNameRe    = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+	identRe       = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	refRe         = regexp.MustCompile(`^[a-z][a-z0-9_]*\.[A-Z][A-Za-z0-9]*$`)
+	packNameRe    = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+	profileNameRe = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+	hostnameRe    = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9.-]*[A-Za-z0-9])?$`)
+)
+
+// LoadSpec reads, decodes and validates the spec at path. On failure the
+// returned error is a Diagnostics value holding every error found; otherwise
+// the warnings, such as an outdated spec version, come back with the Config.
+// An unset ${VAR} is an error, as the output generated from the spec would
+// lack it.
+func LoadSpec(path string) (*Config, Diagnostics, error) {
+	return loadSpec(path, specOptions{})
+}
+
+// inspectSpec is LoadSpec for commands that only read the spec, such as
+// graph: an unset ${VAR} leaves its value empty instead of failing the load.
+func inspectSpec(path string) (*Config, error) {
+	cfg, _, err := loadSpec(path, specOptions{optionalEnv: true})
+	return cfg, err
+}
+
+func loadSpec(path string, opts specOptions) (*Config, Diagnostics, error) {
+	cfg, diags := readSpec(path, opts)
+	if diags.HasErrors() {
+		return nil, nil, diags.Errors()
+	}
+	return cfg, diags, nil
+}
+
+// specOptions tune how a spec is read.
+type specOptions struct {
+	edited      map[string][]byte // unsaved content in place of files on disk
+	optionalEnv bool              // an unset ${VAR} is a warning and its value is left empty
+}
+
+// readSpec reads and decodes the spec at path and the feat files it
+// includes.
+func readSpec(path string, opts specOptions) (*Config, Diagnostics) {
+	src := diskSource{dir: filepath.Dir(path), edited: opts.edited}
+	b, err := src.ReadFile(path)
+	if err != nil {
+		return nil, Diagnostics{{Rule: RuleSyntax, File: path, Severity: SeverityError, Message: err.Error()}}
+	}
+	return parseSpec(path, b, src, opts)
+}
+
+// ParseSpec decodes and validates spec content. file is only used to label
+// diagnostics. The returned Config is nil only when the YAML itself is
+// unreadable. A spec with include globs needs LoadSpec, which can read the
+// files they match.
+func ParseSpec(file string, data []byte) (*Config, Diagnostics) {
+	return parseSpec(file, data, nil, specOptions{})
+}
+
+func parseSpec(file string, data []byte, src specSource, opts specOptions) (*Config, Diagnostics) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, Diagnostics{syntaxDiagnostic(file, err)}
+	}
+	d := &specDecoder{
+		diagnosticList: diagnosticList{rule: RuleSchema},
+		file:           file,
+		source:         SourceMap{},
+		featPos:        map[string]Pos{},
+		optionalEnv:    opts.optionalEnv,
+	}
+	root := resolve(&doc)
+	if root == nil || isNull(root) {
+		d.errorf(Pos{File: file, Line: 1, Column: 1}, "", "spec is empty")
+		return nil, d.diags
+	}
+	cfg := d.config(root)
+	d.includes(cfg, src)
+	sortDiagnostics(d.diags)
+	return cfg, d.diags
+}
+
+// specSource gives the decoder the files matched by include globs.
+type specSource interface {
+	Glob(pattern string) ([]string, error)
+	ReadFile(name string) ([]byte, error)
+}
+
+// diskSource resolves include globs against dir. Content in edited takes
+// the place of the file of the same name.
+type diskSource struct {
+	dir    string
+	edited map[string][]byte
+}
+
+func (s diskSource) Glob(pattern string) ([]string, error) {
+	return filepath.Glob(filepath.Join(s.dir, pattern))
+}
+
+func (s diskSource) ReadFile(name string) ([]byte, error) {
+	if b, ok := s.edited[name]; ok {
+		return b, nil
+	}
+	return os.ReadFile(name)
+}
+
+// specDecoder walks the yaml.v3 node tree so every value keeps its position.
+type specDecoder struct {
+	diagnosticList
+	file    string
+	rank    int // of file, see Pos.Rank
+	source  SourceMap
+	featPos map[string]Pos // where each feat was first declared
+	legacy  bool           // not a SpecVersion spec: 0.1 shorthands are let through
+
+	optionalEnv bool // an unset ${VAR} is a warning, see specOptions
+}
+
+func (d *specDecoder) pos(n *yaml.Node) Pos {
+	p := nodePos(d.file, n)
+	p.Rank = d.rank
+	return p
+}
+
+// nearest returns the position of path or of its closest declared ancestor.
+func (d *specDecoder) nearest(path string) Pos {
+	if p, ok := d.source.nearest(path); ok {
+		return p
+	}
+	return Pos{File: d.file, Line: 1, Column: 1, Rank: d.rank}
+}
+
+func (d *specDecoder) required(path string) {
+	what := path[strings.LastIndexAny(path, ".]")+1:]
+	d.errorf(d.nearest(path), path, "%s is required", what)
+}
+
+func (d *specDecoder) config(n *yaml.Node) *Config {
+	cfg := &Config{Feats: map[string]Feature{}, Source: d.source}
+	if _, v := lookup(n, "version"); v != nil && semverRe.MatchString(v.Value) {
+		d.legacy = specMinor(v.Value) != SpecVersion
+	}
+	d.mapping(n, "", func(key string, k, v *yaml.Node) {
+		switch key {
+		case "version":
+			cfg.Version = d.str(v, key)
+			switch _, err := upgradesFrom(cfg.Version); {
+			case cfg.Version == "":
+			case !semverRe.MatchString(cfg.Version):
+				d.errorf(d.pos(v), key, "invalid version %q (want MAJOR.MINOR[.PATCH])", cfg.Version)
+			case err != nil:
+				d.errorf(d.pos(v), key, "%v", err)
+			case specMinor(cfg.Version) != SpecVersion:
+				d.warnf(d.pos(v), key, "spec version %s is outdated: run aquamarine upgrade to rewrite it to %s", cfg.Version, SpecVersion)
+			}
+		case "project":
+			cfg.Project = d.project(v, key)
+		case "runtime":
+			cfg.Runtime = d.runtime(v, key)
+		case "ordering":
+			cfg.Ordering = d.ordering(v, key)
+		case "templates":
+			cfg.Templates = d.templates(v, key)
+		case "include":
+			d.sequence(v, key, func(i int, item *yaml.Node) {
+				ip := fmt.Sprintf("%s[%d]", key, i)
+				p := d.str(item, ip)
+				if _, err := filepath.Match(p, ""); err != nil || filepath.IsAbs(p) {
+					d.errorf(d.pos(item), ip, "invalid include glob %q (want a pattern relative to the spec file)", p)
+					return
+				}
+				if p != "" {
+					cfg.Include = append(cfg.Include, p)
+				}
+			})
+		case "hooks":
+			cfg.Hooks = d.hooks(v, key)
+		case "profiles":
+			cfg.Profiles = d.profiles(v, key)
+		case "feats":
+			d.feats(cfg, v, key)
+		default:
+			d.unknown(k, "")
+		}
+	})
+
+	if cfg.Version == "" {
+		d.required("version")
+	}
+	if cfg.Project.Name == "" {
+		d.required("project.name")
+	}
+	if cfg.Project.Module == "" {
+		d.required("project.module")
+	}
+	d.checkRuntime(&cfg.Runtime)
+	for _, name := range sortedKeys(cfg.Profiles) {
+		d.checkPorts(cfg.Runtime.overlay(cfg.Profiles[name].Runtime), join("profiles."+name, "runtime.http.web.port"))
+	}
+	return cfg
+}
+
+func (d *specDecoder) project(n *yaml.Node, path string) ProjectConfig {
+	var p ProjectConfig
+	d.mapping(n, path, func(key string, k, v *yaml.Node) {
+		kp := join(path, key)
+		switch key {
+		case "name":
+			p.Name = d.str(v, kp)
+			if p.Name != "" && !projectNameRe.MatchString(p.Name) {
+				d.errorf(d.pos(v), kp, "invalid project name %q", p.Name)
+			}
+		case "module":
+			p.Module = d.str(v, kp)
+			if p.Module != "" && !modulePathRe.MatchString(p.Module) {
+				d.errorf(d.pos(v), kp, "invalid Go module path %q", p.Module)
+			}
+		default:
+			d.unknown(k, path)
+		}
+	})
+	return p
+}
+
+func (d *specDecoder) runtime(n *yaml.Node, path string) RuntimeConfig {
+	var rt RuntimeConfig
+	d.mapping(n, path, func(key string, k, v *yaml.Node) {
+		kp := join(path, key)
+		switch key {
+		case "http":
+			d.mapping(v, kp, func(key string, k, v *yaml.Node) {
+				ep := join(kp, key)
+				switch key {
+				case "api":
+					rt.HTTP.API.Host, rt.HTTP.API.Port = d.endpoint(v, ep)
+				case "web":
+					rt.HTTP.Web.Host, rt.HTTP.Web.Port = d.endpoint(v, ep)
+				default:
+					d.unknown(k, kp)
+				}
+			})
+		case "database":
+			d.mapping(v, kp, func(key string, k, v *yaml.Node) {
+				dp := join(kp, key)
+				switch key {
+				case "engine":
+					rt.Database.Engine = d.oneOf(v, dp, "engine", allowedEngines)
+				case "dsn":
+					rt.Database.DSN = d.runtimeStr(v, dp)
+				default:
+					d.unknown(k, kp)
+				}
+			})
+		default:
+			d.unknown(k, path)
+		}
+	})
+	return rt
+}
+
+func (d *specDecoder) endpoint(n *yaml.Node, path string) (string, int) {
+	var host string
+	var port int
+	d.mapping(n, path, func(key string, k, v *yaml.Node) {
+		kp := join(path, key)
+		switch key {
+		case "host":
+			host = d.str(v, kp)
+			if host != "" && net.ParseIP(host) == nil && !hostnameRe.MatchString(host) {
+				d.errorf(d.pos(v), kp, "invalid host %q", host)
+			}
+		case "port":
+			var ok bool
+			if port, ok = d.integer(v, kp); ok && (port < 1 || port > 65535) {
+				d.errorf(d.pos(v), kp, "port %d out of range 1-65535", port)
+			}
+		default:
+			d.unknown(k, path)
+		}
+	})
+	return host, port
+}
+
+// checkRuntime enforces required runtime values and fills defaults.
+func (d *specDecoder) checkRuntime(rt *RuntimeConfig) {
+	for _, ep := range []struct {
+		name string
+		host *string
+	}{
+		{"api", &rt.HTTP.API.Host},
+		{"web", &rt.HTTP.Web.Host},
+	} {
+		p := "runtime.http." + ep.name + ".port"
+		if _, ok := d.source[p]; !ok {
+			d.required(p)
+		}
+		if *ep.host == "" {
+			*ep.host = defaultHost
+		}
+	}
+	d.checkPorts(*rt, "runtime.http.web.port")
+	if rt.Database.Engine == "" {
+		rt.Database.Engine = "sqlite"
+	}
+}
+
+// checkPorts reports api and web servers listening on the same address, at
+// path.
+func (d *specDecoder) checkPorts(rt RuntimeConfig, path string) {
+	api, web := rt.HTTP.API, rt.HTTP.Web
+	if api.Port != 0 && api.Port == web.Port && hostsOverlap(api.Host, web.Host) {
+		d.errorf(d.nearest(path), path, "api and web servers both listen on %s:%d", web.Host, web.Port)
+	}
+}
+
+func hostsOverlap(a, b string) bool {
+	return a == b || a == "0.0.0.0" || b == "0.0.0.0"
+}
+
+func (d *specDecoder) ordering(n *yaml.Node, path string) OrderingConfig {
+	var o OrderingConfig
+	d.mapping(n, path, func(key string, k, v *yaml.Node) {
+		kp := join(path, key)
+		switch key {
+		case "requires":
+			d.sequence(v, kp, func(i int, item *yaml.Node) {
+				ip := fmt.Sprintf("%s[%d]", kp, i)
+				pair := d.strList(item, ip)
+				if len(pair) != 2 {
+					d.errorf(d.pos(item), ip, "expected [feat, dependency], got %d items", len(pair))
+					return
+				}
+				o.Requires = append(o.Requires, [2]string{pair[0], pair[1]})
+			})
+		default:
+			d.unknown(k, path)
+		}
+	})
+	return o
+}
+
+func (d *specDecoder) templates(n *yaml.Node, path string) TemplatesConfig {
+	var t TemplatesConfig
+	d.mapping(n, path, func(key string, k, v *yaml.Node) {
+		kp := join(path, key)
+		switch key {
+		case "dir":
+			t.Dir = d.str(v, kp)
+		case "packs":
+			d.sequence(v, kp, func(i int, item *yaml.Node) {
+				ip := fmt.Sprintf("%s[%d]", kp, i)
+				p := d.str(item, ip)
+				if p != "" && !isPackPath(p) && !packNameRe.MatchString(p) {
+					d.errorf(d.pos(item), ip, "invalid template pack %q (want a name or a ./relative path)", p)
+					return
+				}
+				t.Packs = append(t.Packs, p)
+			})
+		default:
+			d.unknown(k, path)
+		}
+	})
+	return t
+}
+
+func (d *specDecoder) hooks(n *yaml.Node, path string) HooksConfig {
+	var h HooksConfig
+	d.mapping(n, path, func(key string, k, v *yaml.Node) {
+		kp := join(path, key)
+		switch key {
+		case "pre_generate":
+			h.PreGenerate = d.strList(v, kp)
+		case "post_generate":
+			h.PostGenerate = d.strList(v, kp)
+		default:
+			d.unknown(k, path)
+		}
+	})
+	return h
+}
+
+func (d *specDecoder) profiles(n *yaml.Node, path string) map[string]Profile {
+	profiles := map[string]Profile{}
+	d.mapping(n, path, func(name string, k, v *yaml.Node) {
+		pp := join(path, name)
+		if !profileNameRe.MatchString(name) {
+			d.errorf(d.pos(k), pp, "invalid profile name %q", name)
+		}
+		var p Profile
+		d.mapping(v, pp, func(key string, k, v *yaml.Node) {
+			kp := join(pp, key)
+			switch key {
+			case "local":
+				local := d.boolean(v, kp)
+				p.Local = &local
+			case "output":
+				p.Output = d.str(v, kp)
+			case "runtime":
+				p.Runtime = d.runtime(v, kp)
+			default:
+				d.unknown(k, pp)
+			}
+		})
+		profiles[name] = p
+	})
+	return profiles
+}
+
+func (d *specDecoder) feats(cfg *Config, n *yaml.Node, path string) {
+	switch n = resolve(n); {
+	case isNull(n):
+	case n.Kind == yaml.SequenceNode:
+		for i, item := range n.Content {
+			d.namedFeat(cfg, resolve(item), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case n.Kind == yaml.MappingNode:
+		d.replaced(n, path, "feats keyed by name", "a list of feats")
+		d.mapping(n, path, func(key string, k, v *yaml.Node) {
+			fp := join(path, key)
+			f := d.feature(v, fp)
+			switch {
+			case f.Name == "":
+				f.Name = key
+				d.featName(k, fp, key)
+			case f.Name != key:
+				d.errorf(d.nearest(join(fp, "name")), join(fp, "name"), "feat name %q does not match its key %q", f.Name, key)
+			}
+			d.addFeat(cfg, f, k, fp)
+		})
+	default:
+		d.errorf(d.pos(n), path, "expected a list of feats, got %s", kindName(n))
+	}
+}
+
+// namedFeat decodes a feat declaring its own name, a list item or an
+// included file. fallback is its path when the name cannot key it.
+func (d *specDecoder) namedFeat(cfg *Config, item *yaml.Node, fallback string) {
+	fp := fallback
+	if name := scalarValue(item, "name"); validFeatName(name) {
+		if _, dup := d.featPos[name]; !dup {
+			fp = join("feats", name)
+		}
+	}
+	d.mark(fp, item)
+	f := d.feature(item, fp)
+	if f.Name == "" {
+		d.required(join(fp, "name"))
+	}
+	d.addFeat(cfg, f, item, fp)
+}
+
+// featName reports name, found at n, unless it is a valid feat name.
+func (d *specDecoder) featName(n *yaml.Node, path, name string) {
+	if !validFeatName(name) {
+		d.errorf(d.pos(n), path, "invalid feat name %q (want lowercase Go package name)", name)
+	}
+}
+
+// validFeatName reports whether name can name a feat: it is the name of the
+// feat's Go package, so a Go keyword cannot.
+func validFeatName(name string) bool {
+	return featNameRe.MatchString(name) && !token.IsKeyword(name)
+}
+
+func (d *specDecoder) addFeat(cfg *Config, f Feature, at *yaml.Node, fp string) {
+	if f.Name == "" {
+		return
+	}
+	if first, ok := d.featPos[f.Name]; ok {
+		d.errorf(d.pos(at), fp, "duplicate feat name %q (first declared at %s:%d)", f.Name, first.File, first.Line)
+		return
+	}
+	d.featPos[f.Name] = d.pos(at)
+	cfg.Feats[f.Name] = f
+}
+
+// includes merges the files matched by the include globs, in glob order,
+// after the feats of the spec itself. Each file holds one feat, in the form
+// of a feats list item; a file matched twice is read once.
+func (d *specDecoder) includes(cfg *Config, src specSource) {
+	root := d.file
+	defer func() { d.file, d.rank = root, 0 }()
+	seen := map[string]bool{filepath.Clean(root): true}
+	for i, pattern := range cfg.Include {
+		ip := fmt.Sprintf("include[%d]", i)
+		if src == nil {
+			d.errorf(d.nearest(ip), ip, "include needs the spec loaded from its file")
+			continue
+		}
+		files, err := src.Glob(pattern)
+		if err != nil {
+			d.errorf(d.nearest(ip), ip, "include %q: %v", pattern, err)
+			continue
+		}
+		if len(files) == 0 {
+			d.warnf(d.nearest(ip), ip, "include %q matches no files", pattern)
+		}
+		for _, file := range files {
+			if seen[filepath.Clean(file)] {
+				continue
+			}
+			seen[filepath.Clean(file)] = true
+			b, err := src.ReadFile(file)
+			if err != nil {
+				d.errorf(d.nearest(ip), ip, "%v", err)
+				continue
+			}
+			d.file, d.rank = file, len(seen)-1
+			var doc yaml.Node
+			if err := yaml.Unmarshal(b, &doc); err != nil {
+				d.diags = append(d.diags, syntaxDiagnostic(file, err))
+				continue
+			}
+			n := resolve(&doc)
+			if n == nil || isNull(n) {
+				d.errorf(Pos{File: file, Line: 1, Column: 1, Rank: d.rank}, "", "included file is empty (want one feat)")
+				continue
+			}
+			d.namedFeat(cfg, n, "feats["+file+"]")
+		}
+	}
+}
+
+// replaced reports a 0.1 shorthand in a newer spec. It is decoded all the
+// same, so the error does not cascade.
+func (d *specDecoder) replaced(at *yaml.Node, path, old, now string) {
+	if !d.legacy {
+		d.errorf(d.pos(at), path, "%s was replaced by %s in spec version 0.2", old, now)
+	}
+}
+
+func (d *specDecoder) feature(n *yaml.Node, path string) Feature {
+	f := Feature{Models: map[string]Model{}}
+	addModel := func(name string, m Model, at *yaml.Node, mp string) {
+		if _, ok := f.Models[name]; ok {
+			d.errorf(d.pos(at), mp, "duplicate model %q", name)
+			return
+		}
+		f.Models[name] = m
+	}
+
+	d.mapping(n, path, func(key string, k, v *yaml.Node) {
+		kp := join(path, key)
+		switch key {
+		case "name":
+			f.Name = d.str(v, kp)
+			if f.Name != "" {
+				d.featName(v, kp, f.Name)
+			}
+		case "kind":
+			f.Kind = d.oneOf(v, kp, "kind", allowedKinds)
+		case "model":
+			d.replaced(k, kp, "model", "an entry of models")
+			name := scalarValue(v, "name")
+			if name == "" {
+				d.required(join(kp, "name"))
+				return
+			}
+			mp := join(path, "models."+name)
+			d.mark(mp, v)
+			addModel(name, d.model(v, mp, true), v, mp)
+		case "models":
+			d.mapping(v, join(path, "models"), func(name string, k, v *yaml.Node) {
+				mp := join(path, "models."+name)
+				if !typeNameRe.MatchString(name) {
+					d.errorf(d.pos(k), mp, "invalid model name %q (want exported Go identifier)", name)
+				}
+				addModel(name, d.model(v, mp, false), k, mp)
+			})
+		case "service":
+			f.Service = d.service(v, kp)
+		case "api":
+			f.API = d.api(v, kp)
+		case "web":
+			d.mapping(v, kp, func(key string, k, v *yaml.Node) {
+				switch key {
+				case "pages":
+					f.Web.Pages = append(f.Web.Pages, d.pages(v, join(path, "web.pages"), len(f.Web.Pages))...)
+				default:
+					d.unknown(k, kp)
+				}
+			})
+		case "pages":
+			d.replaced(k, kp, "pages", "web.pages")
+			f.Web.Pages = append(f.Web.Pages, d.pages(v, join(path, "web.pages"), len(f.Web.Pages))...)
+		case "repo_impl":
+			d.sequence(v, kp, func(i int, item *yaml.Node) {
+				ip := fmt.Sprintf("%s[%d]", kp, i)
+				if e := d.oneOf(item, ip, "repo implementation", allowedEngines); e != "" {
+					f.RepoImpl = append(f.RepoImpl, e)
+				}
+			})
+		case "auth":
+			f.Auth = &AuthConfig{}
+			d.mapping(v, kp, func(key string, k, v *yaml.Node) {
+				switch key {
+				case "enabled":
+					f.Auth.Enabled = d.boolean(v, join(kp, key))
+				default:
+					d.unknown(k, kp)
+				}
+			})
+		case "aggregates":
+			f.Aggregates = d.aggregates(v, kp)
+		case "requires":
+			d.sequence(v, kp, func(i int, item *yaml.Node) {
+				ip := fmt.Sprintf("%s[%d]", kp, i)
+				switch dep := d.str(item, ip); {
+				case dep == "":
+				case !validFeatName(dep):
+					d.errorf(d.pos(item), ip, "invalid feat name %q", dep)
+				default:
+					f.Requires = append(f.Requires, dep)
+				}
+			})
+		default:
+			d.unknown(k, path)
+		}
+	})
+
+	if f.Kind == "" {
+		f.Kind = "domain"
+	}
+	return f
+}
+
+func (d *specDecoder) model(n *yaml.Node, path string, named bool) Model {
+	var m Model
+	d.mapping(n, path, func(key string, k, v *yaml.Node) {
+		kp := join(path, key)
+		switch {
+		case key == "name" && named:
+			if name := d.str(v, kp); !typeNameRe.MatchString(name) {
+				d.errorf(d.pos(v), kp, "invalid model name %q (want exported Go identifier)", name)
+			}
+		case key == "fields":
+			m.Fields = d.fields(v, kp)
+		case key == "relations":
+			m.Relations = d.relations(v, kp)
+		case key == "options":
+			m.Options = &ModelOptions{}
+			d.mapping(v, kp, func(key string, k, v *yaml.Node) {
+				switch key {
+				case "audit":
+					m.Options.Audit = d.boolean(v, join(kp, key))
+				default:
+					d.unknown(k, kp)
+				}
+			})
+		default:
+			d.unknown(k, path)
+		}
+	})
+	return m
+}
+
+func (d *specDecoder) fields(n *yaml.Node, path string) map[string]Field {
+	fields := map[string]Field{}
+	d.mapping(n, path, func(name string, k, v *yaml.Node) {
+		fp := join(path, name)
+		if !identRe.MatchString(name) {
+			d.errorf(d.pos(k), fp, "invalid field name %q", name)
+		}
+		var f Field
+		var defaultNode *yaml.Node
+		d.mapping(v, fp, func(key string, k, v *yaml.Node) {
+			kp := join(fp, key)
+			switch key {
+			case "type":
+				f.Type = d.oneOf(v, kp, "field type", fieldTypes)
+			case "of":
+				f.Of = d.oneOf(v, kp, "list element type", listElemTypes())
+			case "values":
+				f.Values = d.enumValues(v, kp)
+			case "ref":
+				if f.Ref = d.str(v, kp); f.Ref != "" && !refRe.MatchString(f.Ref) {
+					d.errorf(d.pos(v), kp, "invalid ref %q (want feat.Model)", f.Ref)
+				}
+			case "nullable":
+				f.Nullable = d.boolean(v, kp)
+			case "default":
+				f.Default, defaultNode = d.str(v, kp), v
+			case "validations":
+				f.Validations = d.validations(v, kp)
+			default:
+				d.unknown(k, fp)
+			}
+		})
+		if _, ok := d.source[join(fp, "type")]; !ok {
+			d.required(join(fp, "type"))
+		}
+		d.checkField(f, fp, defaultNode)
+		fields[name] = f
+	})
+	return fields
+}
+
+func (d *specDecoder) relations(n *yaml.Node, path string) map[string]Relation {
+	rels := map[string]Relation{}
+	d.mapping(n, path, func(name string, k, v *yaml.Node) {
+		rp := join(path, name)
+		if !identRe.MatchString(name) {
+			d.errorf(d.pos(k), rp, "invalid relation name %q", name)
+		}
+		var r Relation
+		d.mapping(v, rp, func(key string, k, v *yaml.Node) {
+			kp := join(rp, key)
+			switch key {
+			case "type":
+				r.Type = d.oneOf(v, kp, "relation type", relationTypes)
+			case "model":
+				if r.Model = d.str(v, kp); r.Model != "" && !typeNameRe.MatchString(r.Model) {
+					d.errorf(d.pos(v), kp, "invalid model name %q (want exported Go identifier)", r.Model)
+				}
+			case "via":
+				r.Via = d.str(v, kp)
+			case "nullable":
+				r.Nullable = d.boolean(v, kp)
+			default:
+				d.unknown(k, rp)
+			}
+		})
+		for _, key := range []string{"type", "model"} {
+			if _, ok := d.source[join(rp, key)]; !ok {
+				d.required(join(rp, key))
+			}
+		}
+		switch {
+		case r.Via != "" && r.Type != "has_many":
+			d.errorf(d.nearest(join(rp, "via")), join(rp, "via"), "via only applies to has_many relations")
+		case r.Nullable && r.Type != "belongs_to":
+			d.errorf(d.nearest(join(rp, "nullable")), join(rp, "nullable"), "nullable only applies to belongs_to relations")
+		}
+		rels[name] = r
+	})
+	return rels
+}
+
+// checkField reports keys of f that do not fit its type: of outside lists,
+// defaults the type cannot hold and validations it cannot check.
+func (d *specDecoder) checkField(f Field, path string, defaultNode *yaml.Node) {
+	if f.Type == "" {
+		return
+	}
+	switch {
+	case f.Type == "list" && f.Of == "":
+		if _, ok := d.source[join(path, "of")]; !ok {
+			d.required(join(path, "of"))
+		}
+	case f.Type != "list" && f.Of != "":
+		d.errorf(d.nearest(join(path, "of")), join(path, "of"), "of only applies to list fields, not %s", f.Type)
+	case f.Type == "enum" && len(f.Values) == 0:
+		if _, ok := d.source[join(path, "values")]; !ok {
+			d.required(join(path, "values"))
+		}
+	case f.Type != "enum" && f.Values != nil:
+		d.errorf(d.nearest(join(path, "values")), join(path, "values"), "values only apply to enum fields, not %s", f.Type)
+	case f.Type != "uuid" && f.Ref != "":
+		d.errorf(d.nearest(join(path, "ref")), join(path, "ref"), "ref only applies to uuid fields, not %s", f.Type)
+	}
+	if defaultNode != nil && f.Default != "" {
+		why := checkDefault(f.Type, f.Default)
+		if f.Type == "enum" && len(f.Values) > 0 && !slices.Contains(f.Values, f.Default) {
+			why = "want one of " + strings.Join(f.Values, "|")
+		}
+		if why != "" {
+			d.errorf(d.pos(defaultNode), join(path, "default"), "invalid default %q: %s", f.Default, why)
+		}
+	}
+	for i, v := range f.Validations {
+		if !validationApplies(v.Name, f.Type) {
+			vp := fmt.Sprintf("%s[%d]", join(path, "validations"), i)
+			d.errorf(d.nearest(vp), vp, "validation %q does not apply to %s fields", v.Name, f.Type)
+		}
+	}
+}
+
+// enumValues reads the values of an enum: distinct, each naming a Go
+// constant once written in PascalCase (in_review is InReview).
+func (d *specDecoder) enumValues(n *yaml.Node, path string) []string {
+	values := []string{}
+	consts := map[string]string{}
+	d.sequence(n, path, func(i int, item *yaml.Node) {
+		ip := fmt.Sprintf("%s[%d]", path, i)
+		v := d.str(item, ip)
+		c := pascal(v)
+		switch {
+		case v == "":
+			d.errorf(d.pos(item), ip, "enum value must not be empty")
+		case !identRe.MatchString(c):
+			d.errorf(d.pos(item), ip, "enum value %q does not make a Go identifier", v)
+		case consts[c] != "":
+			d.errorf(d.pos(item), ip, "enum value %q clashes with %q (both are %s)", v, consts[c], c)
+		default:
+			consts[c] = v
+			values = append(values, v)
+		}
+	})
+	return values
+}
+
+func (d *specDecoder) validations(n *yaml.Node, path string) []Validation {
+	var vs []Validation
+	d.sequence(n, path, func(i int, item *yaml.Node) {
+		ip := fmt.Sprintf("%s[%d]", path, i)
+		var v Validation
+		valueNode := item
+		switch item.Kind {
+		case yaml.ScalarNode:
+			v.Name = item.Value
+		case yaml.MappingNode:
+			if len(item.Content) != 2 {
+				d.errorf(d.pos(item), ip, "validation must be a name or a single {name: value} pair")
+				return
+			}
+			v.Name = item.Content[0].Value
+			valueNode = item.Content[1]
+			v.Value = d.str(valueNode, ip)
+		default:
+			d.errorf(d.pos(item), ip, "validation must be a name or a single {name: value} pair")
+			return
+		}
+
+		arg, known := validationArgs[v.Name]
+		switch {
+		case !known:
+			d.errorf(d.pos(item), ip, "unknown validation %q", v.Name)
+		case arg == "" && v.Value != "":
+			d.errorf(d.pos(valueNode), ip, "validation %q takes no value", v.Name)
+		case arg != "" && v.Value == "":
+			d.errorf(d.pos(item), ip, "validation %q requires a value", v.Name)
+		case arg == "int":
+			if _, err := strconv.Atoi(v.Value); err != nil {
+				d.errorf(d.pos(valueNode), ip, "validation %q expects an integer, got %q", v.Name, v.Value)
+			}
+		case arg == "regexp":
+			if _, err := regexp.Compile(v.Value); err != nil {
+				d.errorf(d.pos(valueNode), ip, "validation %q has an invalid pattern: %v", v.Name, err)
+			}
+		}
+		vs = append(vs, v)
+	})
+	return vs
+}
+
+func (d *specDecoder) service(n *yaml.Node, path string) ServiceConfig {
+	var s ServiceConfig
+	d.mapping(n, path, func(key string, k, v *yaml.Node) {
+		kp := join(path, key)
+		switch key {
+		case "methods":
+			seen := map[string]bool{}
+			d.sequence(v, kp, func(i int, item *yaml.Node) {
+				ip := fmt.Sprintf("%s[%d]", kp, i)
+				m := d.str(item, ip)
+				switch {
+				case m == "":
+				case !typeNameRe.MatchString(m):
+					d.errorf(d.pos(item), ip, "invalid method name %q (want exported Go identifier)", m)
+				case seen[m]:
+					d.errorf(d.pos(item), ip, "duplicate method %q", m)
+				default:
+					seen[m] = true
+					s.Methods = append(s.Methods, m)
+				}
+			})
+		default:
+			d.unknown(k, path)
+		}
+	})
+	return s
+}
+
+func (d *specDecoder) api(n *yaml.Node, path string) APIFeatureConfig {
+	var a APIFeatureConfig
+	d.mapping(n, path, func(key string, k, v *yaml.Node) {
+		kp := join(path, key)
+		switch key {
+		case "routes":
+			d.sequence(v, kp, func(i int, item *yaml.Node) {
+				a.Routes = append(a.Routes, d.route(item, fmt.Sprintf("%s[%d]", kp, i)))
+			})
+		default:
+			d.unknown(k, path)
+		}
+	})
+	return a
+}
+
+func (d *specDecoder) route(n *yaml.Node, path string) RouteConfig {
+	var r RouteConfig
+	d.mapping(n, path, func(key string, k, v *yaml.Node) {
+		kp := join(path, key)
+		switch key {
+		case "method":
+			r.Method = d.oneOf(v, kp, "HTTP method", allowedMethods)
+		case "path":
+			r.Path = d.str(v, kp)
+			if r.Path != "" && !strings.HasPrefix(r.Path, "/") {
+				d.errorf(d.pos(v), kp, "route path %q must start with /", r.Path)
+			}
+			if p := routePath(r.Path); p != r.Path {
+				d.errorf(d.pos(v), kp, "route path %q: write path parameters in braces (%s)", r.Path, p)
+			}
+		case "handler":
+			r.Handler = d.str(v, kp)
+			if r.Handler != "" && !typeNameRe.MatchString(r.Handler) {
+				d.errorf(d.pos(v), kp, "invalid handler name %q (want exported Go identifier)", r.Handler)
+			}
+		default:
+			d.unknown(k, path)
+		}
+	})
+	for _, key := range []string{"method", "path", "handler"} {
+		if _, ok := d.source[join(path, key)]; !ok {
+			d.required(join(path, key))
+		}
+	}
+	return r
+}
+
+func (d *specDecoder) pages(n *yaml.Node, path string, offset int) []PageConfig {
+	var pages []PageConfig
+	d.sequence(n, path, func(i int, item *yaml.Node) {
+		ip := fmt.Sprintf("%s[%d]", path, offset+i)
+		d.mark(ip, item)
+		var p PageConfig
+		d.mapping(item, ip, func(key string, k, v *yaml.Node) {
+			kp := join(ip, key)
+			switch key {
+			case "route":
+				p.Route = d.str(v, kp)
+				method, rpath, ok := strings.Cut(p.Route, " ")
+				switch {
+				case !ok || !strings.HasPrefix(rpath, "/"):
+					d.errorf(d.pos(v), kp, "page route %q must look like \"GET /path\"", p.Route)
+				case !slices.Contains(allowedMethods, method):
+					d.errorf(d.pos(v), kp, "unknown HTTP method %q (want one of %s)", method, strings.Join(allowedMethods, "|"))
+				}
+			case "uses":
+				p.Uses = d.strList(v, kp)
+			default:
+				d.unknown(k, ip)
+			}
+		})
+		if p.Route == "" {
+			d.required(join(ip, "route"))
+		}
+		pages = append(pages, p)
+	})
+	return pages
+}
+
+func (d *specDecoder) aggregates(n *yaml.Node, path string) map[string]Aggregate {
+	aggs := map[string]Aggregate{}
+	d.mapping(n, path, func(name string, k, v *yaml.Node) {
+		ap := join(path, name)
+		if !typeNameRe.MatchString(name) {
+			d.errorf(d.pos(k), ap, "invalid aggregate name %q (want exported Go identifier)", name)
+		}
+		var a Aggregate
+		d.mapping(v, ap, func(key string, k, v *yaml.Node) {
+			kp := join(ap, key)
+			switch key {
+			case "fields":
+				a.Fields = d.fields(v, kp)
+			case "version_field":
+				a.VersionField = d.str(v, kp)
+				if a.VersionField != "" && !identRe.MatchString(a.VersionField) {
+					d.errorf(d.pos(v), kp, "invalid version field name %q", a.VersionField)
+				}
+			case "audit":
+				a.Audit = d.boolean(v, kp)
+			case "children":
+				a.Children = map[string]ChildConfig{}
+				d.mapping(v, kp, func(child string, k, v *yaml.Node) {
+					cp := join(kp, child)
+					if !identRe.MatchString(child) {
+						d.errorf(d.pos(k), cp, "invalid child collection name %q", child)
+					}
+					var c ChildConfig
+					d.mapping(v, cp, func(key string, k, v *yaml.Node) {
+						switch key {
+						case "of":
+							c.Of = d.str(v, join(cp, key))
+						case "audit":
+							c.Audit = d.boolean(v, join(cp, key))
+						default:
+							d.unknown(k, cp)
+						}
+					})
+					if c.Of == "" {
+						d.required(join(cp, "of"))
+					}
+					a.Children[child] = c
+				})
+			default:
+				d.unknown(k, ap)
+			}
+		})
+		aggs[name] = a
+	})
+	return aggs
+}
+
+// mapping iterates the pairs of a mapping node in declaration order, recording
+// positions and reporting duplicate keys. A null node is treated as empty.
+func (d *specDecoder) mapping(n *yaml.Node, path string, fn func(key string, k, v *yaml.Node)) {
+	n = resolve(n)
+	if isNull(n) {
+		return
+	}
+	if n.Kind != yaml.MappingNode {
+		d.errorf(d.pos(n), path, "expected a mapping, got %s", kindName(n))
+		return
+	}
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], resolve(n.Content[i+1])
+		if seen[k.Value] {
+			d.errorf(d.pos(k), path, "duplicate key %q", k.Value)
+			continue
+		}
+		seen[k.Value] = true
+		d.mark(join(path, k.Value), v)
+		fn(k.Value, k, v)
+	}
+}
+
+// sequence iterates the items of a sequence node. A null node is treated as empty.
+func (d *specDecoder) sequence(n *yaml.Node, path string, fn func(i int, item *yaml.Node)) {
+	n = resolve(n)
+	if isNull(n) {
+		return
+	}
+	if n.Kind != yaml.SequenceNode {
+		d.errorf(d.pos(n), path, "expected a list, got %s", kindName(n))
+		return
+	}
+	for i, item := range n.Content {
+		item = resolve(item)
+		d.mark(fmt.Sprintf("%s[%d]", path, i), item)
+		fn(i, item)
+	}
+}
+
+func (d *specDecoder) mark(path string, n *yaml.Node) {
+	if _, ok := d.source[path]; !ok {
+		d.source[path] = d.pos(n)
+	}
+}
+
+func (d *specDecoder) unknown(k *yaml.Node, path string) {
+	d.errorf(d.pos(k), path, "unknown key %q", k.Value)
+}
+
+func (d *specDecoder) str(n *yaml.Node, path string) string {
+	s, _ := d.scalar(n, path)
+	return s
+}
+
+// scalar returns the value of n with its ${VAR} references expanded. ok is
+// false when n is not a usable scalar, its problem reported; an unset VAR
+// under optionalEnv is only a warning, and callers skip their own checks.
+func (d *specDecoder) scalar(n *yaml.Node, path string) (s string, ok bool) {
+	n = resolve(n)
+	if isNull(n) {
+		return "", true
+	}
+	if n.Kind != yaml.ScalarNode {
+		d.errorf(d.pos(n), path, "expected a scalar, got %s", kindName(n))
+		return "", false
+	}
+	s, err := expandEnv(n.Value)
+	var unset *unsetEnvError
+	switch {
+	case errors.As(err, &unset) && d.optionalEnv:
+		d.warnf(d.pos(n), path, "%v", err)
+		return "", false
+	case err != nil:
+		d.errorf(d.pos(n), path, "%v", err)
+		return "", false
+	}
+	return s, true
+}
+
+// runtimeStr returns the value of n as written: its ${VAR} references are
+// only checked, to be expanded by the generated app when it starts, so
+// secrets such as a database password stay out of the generated files.
+func (d *specDecoder) runtimeStr(n *yaml.Node, path string) string {
+	n = resolve(n)
+	if isNull(n) {
+		return ""
+	}
+	if n.Kind != yaml.ScalarNode {
+		d.errorf(d.pos(n), path, "expected a scalar, got %s", kindName(n))
+		return ""
+	}
+	if err := checkEnvRefs(n.Value); err != nil {
+		d.errorf(d.pos(n), path, "%v", err)
+	}
+	return n.Value
+}
+
+func (d *specDecoder) integer(n *yaml.Node, path string) (int, bool) {
+	s, ok := d.scalar(n, path)
+	if !ok {
+		return 0, false
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		d.errorf(d.pos(n), path, "expected an integer, got %q", s)
+		return 0, false
+	}
+	return i, true
+}
+
+func (d *specDecoder) boolean(n *yaml.Node, path string) bool {
+	s, ok := d.scalar(n, path)
+	if !ok {
+		return false
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		d.errorf(d.pos(n), path, "expected true or false, got %q", s)
+	}
+	return b
+}
+
+func (d *specDecoder) strList(n *yaml.Node, path string) []string {
+	var out []string
+	d.sequence(n, path, func(i int, item *yaml.Node) {
+		if s := d.str(item, fmt.Sprintf("%s[%d]", path, i)); s != "" {
+			out = append(out, s)
+		}
+	})
+	return out
+}
+
+func (d *specDecoder) oneOf(n *yaml.Node, path, what string, allowed []string) string {
+	s, _ := d.scalar(n, path)
+	if s != "" && !slices.Contains(allowed, s) {
+		d.errorf(d.pos(n), path, "unknown %s %q (want one of %s)", what, s, strings.Join(allowed, "|"))
+		return ""
+	}
+	return s
+}
+
+func resolve(n *yaml.Node) *yaml.Node {
+	for n != nil && (n.Kind == yaml.AliasNode || n.Kind == yaml.DocumentNode) {
+		if n.Kind == yaml.AliasNode {
+			n = n.Alias
+			continue
+		}
+		if len(n.Content) == 0 {
+			return nil
+		}
+		n = n.Content[0]
+	}
+	return n
+}
+
+func isNull(n *yaml.Node) bool {
+	return n == nil || (n.Kind == yaml.ScalarNode && n.ShortTag() == "!!null")
+}
+
+// scalarValue returns the scalar value of key in mapping n, or "".
+func scalarValue(n *yaml.Node, key string) string {
+	n = resolve(n)
+	if n == nil || n.Kind != yaml.MappingNode {
+		return ""
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			if v := resolve(n.Content[i+1]); v != nil && v.Kind == yaml.ScalarNode {
+				return v.Value
+			}
+		}
+	}
+	return ""
+}
+
+func kindName(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	case yaml.ScalarNode:
+		return fmt.Sprintf("%q", n.Value)
+	default:
+		return "an unexpected node"
+	}
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
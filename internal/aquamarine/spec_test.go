@@ -0,0 +1,327 @@
+package aquamarine
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aquamarinepk/aquamarine/assets"
+)
+
+const includeSpec = `version: 0.2
+project: {name: shop, module: example.com/shop}
+runtime: {http: {api: {port: 8081}, web: {port: 8080}}}
+include: [feats/*.yaml]
+feats:
+  - name: web
+    kind: web
+    web: {pages: [{route: "GET /", uses: [orders, auth]}]}
+`
+
+func writeSpecFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := safeWriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestIncludes(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"aquamarine.yaml":   includeSpec,
+		"feats/orders.yaml": "name: orders\nkind: atom\nmodels: {Order: {fields: {total: {type: int}}}}\n",
+		"feats/auth.yaml":   "name: auth\nmodels: {User: {fields: {email: {type: email}}}}\n",
+	})
+	spec := filepath.Join(dir, "aquamarine.yaml")
+	cfg, _, err := LoadSpec(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Join(declared(cfg.Source, "feats", cfg.Feats), " ")
+	if want := "web auth orders"; got != want {
+		t.Errorf("feat order = %q, want %q (spec first, then includes by name)", got, want)
+	}
+	for i, feat := range []string{"web", "auth", "orders"} {
+		if p := cfg.Source["feats."+feat]; p.Rank != i {
+			t.Errorf("feats.%s at %+v, want rank %d", feat, p, i)
+		}
+	}
+	if diags := ValidateSpec(spec); diags.HasErrors() {
+		t.Errorf("cross references across files: %v", diags)
+	}
+
+	e, err := LoadSpecEditor(featFile(spec, "auth"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Spec = spec
+	if err := e.AddModel("auth", "Role", []FieldArg{{Name: "name", Type: "string"}}); err != nil {
+		t.Fatal(err)
+	}
+	if diags := e.Check(); diags.HasErrors() {
+		t.Fatalf("edit of an included file: %v", diags)
+	}
+	if want := filepath.Join(dir, "feats", "auth.yaml"); e.File != want {
+		t.Errorf("editing %s, want %s", e.File, want)
+	}
+	if !strings.Contains(string(e.Bytes()), "Role:") {
+		t.Errorf("Role not added:\n%s", e.Bytes())
+	}
+}
+
+func TestIncludeErrors(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"aquamarine.yaml":   includeSpec,
+		"feats/orders.yaml": "name: orders\n",
+		"feats/web.yaml":    "name: web\nkind: web\n",
+		"feats/broken.yaml": "name: broken\ncolour: red\n",
+	})
+	diags := ValidateSpec(filepath.Join(dir, "aquamarine.yaml"))
+	var dup, unknown bool
+	for _, d := range diags.Errors() {
+		switch {
+		case strings.Contains(d.Message, `duplicate feat name "web"`):
+			dup = d.File == filepath.Join(dir, "feats", "web.yaml") && strings.Contains(d.Message, "aquamarine.yaml:6")
+		case strings.Contains(d.Message, `unknown key "colour"`):
+			unknown = d.File == filepath.Join(dir, "feats", "broken.yaml") && d.Line == 2
+		}
+	}
+	if !dup || !unknown {
+		t.Errorf("want errors in the included files, got:\n%v", diags)
+	}
+
+	if _, diags := ParseSpec("aquamarine.yaml", []byte(includeSpec)); !diags.HasErrors() {
+		t.Error("ParseSpec: include without files should be an error")
+	}
+
+	os.WriteFile(filepath.Join(dir, "aquamarine.yaml"), []byte(strings.Replace(includeSpec, "feats/*.yaml", "missing/*.yaml", 1)), 0o644)
+	diags = ValidateSpec(filepath.Join(dir, "aquamarine.yaml"))
+	if len(diags) == 0 || diags[0].Severity != SeverityWarning {
+		t.Errorf("include matching nothing: got %v, want a warning", diags)
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("AQ_HOST", "db.internal")
+	t.Setenv("AQ_EMPTY", "")
+	tests := []struct {
+		in, want string
+		err      bool
+	}{
+		{"postgres://${AQ_HOST}/app", "postgres://db.internal/app", false},
+		{"${AQ_EMPTY:-fallback}", "fallback", false},
+		{"${AQ_UNSET:-file:app.db}", "file:app.db", false},
+		{"${AQ_EMPTY}", "", false},
+		{"pa$$word", "pa$$word", false},
+		{"$${AQ_HOST}", "${AQ_HOST}", false},
+		{"${AQ_UNSET}", "", true},
+		{"${AQ_HOST", "", true},
+		{"${1BAD}", "", true},
+	}
+	for _, tt := range tests {
+		got, err := expandEnv(tt.in)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("expandEnv(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestSpecWarnings(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{"aquamarine.yaml": `version: 0.1
+project: {name: shop, module: example.com/shop}
+runtime: {http: {api: {port: 8081}, web: {port: 8080}}}
+include: [feats/*.yaml]
+feats: {}
+`})
+	spec := filepath.Join(dir, "aquamarine.yaml")
+	_, diags, err := LoadSpec(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diags) != 2 || diags.HasErrors() {
+		t.Fatalf("LoadSpec: got %v, want warnings for the version and the empty include", diags)
+	}
+
+	warnings, err := Diff(assets.Templates(), io.Discard, GenerateOptions{SpecFile: spec, OutputDir: filepath.Join(dir, "out")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Join(warnings, "\n")
+	for _, want := range []string{"version: spec version 0.1 is outdated", `include[0]: include "feats/*.yaml" matches no files`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Diff warnings lack %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "warning:") {
+		t.Errorf("Diff warnings repeat their label:\n%s", got)
+	}
+}
+
+func TestOptionalEnv(t *testing.T) {
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "aquamarine.yaml")
+	content := `version: 0.2
+project: {name: shop, module: example.com/shop}
+runtime:
+  http: {api: {port: "${AQ_UNSET_PORT}"}, web: {port: 8080}}
+  database: {dsn: "postgres://app:${AQ_UNSET_SECRET}@db/shop"}
+feats: []
+`
+	if err := os.WriteFile(spec, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := LoadSpec(spec); err == nil || !strings.Contains(err.Error(), "AQ_UNSET_PORT is not set") {
+		t.Errorf("LoadSpec: got %v, want the unset port variable as an error", err)
+	}
+	diags := ValidateSpec(spec)
+	if diags.HasErrors() || len(diags) != 1 || diags[0].Path != "runtime.http.api.port" {
+		t.Errorf("ValidateSpec: got %v, want a single warning for the unset port variable", diags)
+	}
+	cfg, err := inspectSpec(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Runtime.Database.DSN != "postgres://app:${AQ_UNSET_SECRET}@db/shop" {
+		t.Errorf("dsn = %q, want it kept as written for the app to expand", cfg.Runtime.Database.DSN)
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	t.Setenv("AQ_DSN", "file:/var/lib/shop.db")
+	spec := `version: 0.2
+project: {name: shop, module: example.com/shop}
+runtime:
+  http: {api: {port: "${AQ_API_PORT:-8081}"}, web: {port: 8080}}
+profiles:
+  dev:
+    runtime: {database: {dsn: "file:dev.db"}}
+  staging:
+    output: deploy/staging
+    runtime:
+      http: {api: {host: 0.0.0.0}}
+      database: {dsn: "${AQ_DSN}"}
+feats: []
+`
+	cfg, diags := ParseSpec("aquamarine.yaml", []byte(spec))
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	if cfg.Runtime.HTTP.API.Port != 8081 {
+		t.Errorf("api port = %d, want the ${AQ_API_PORT:-8081} default", cfg.Runtime.HTTP.API.Port)
+	}
+
+	dev := *cfg
+	p, err := dev.ApplyProfile(ProfileDev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !*p.Local || dev.Runtime.Database.DSN != "file:dev.db" || dev.Runtime.HTTP.API.Host != defaultHost {
+		t.Errorf("dev: local %v, runtime %+v", *p.Local, dev.Runtime)
+	}
+
+	staging := *cfg
+	p, err = staging.ApplyProfile("staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *p.Local || p.Output != "deploy/staging" || staging.Runtime.HTTP.API.Host != "0.0.0.0" || staging.Runtime.Database.DSN != "${AQ_DSN}" {
+		t.Errorf("staging: profile %+v, runtime %+v", p, staging.Runtime)
+	}
+	if cfg.Runtime.Database.DSN != "" {
+		t.Error("ApplyProfile on a copy changed the original runtime")
+	}
+
+	if _, err := cfg.ApplyProfile("qa"); err == nil || !strings.Contains(err.Error(), "dev, prod, staging") {
+		t.Errorf("unknown profile: got %v", err)
+	}
+
+	overlap := strings.Replace(spec, "api: {host: 0.0.0.0}", "web: {port: 8081}", 1)
+	if _, diags := ParseSpec("aquamarine.yaml", []byte(overlap)); !diags.HasErrors() {
+		t.Error("a profile with api and web on the same port: want an error")
+	}
+}
+
+func TestSpecErrors(t *testing.T) {
+	const head = "version: 0.2\nproject: {name: shop, module: example.com/shop}\n"
+	const rt = "runtime: {http: {api: {port: 8081}, web: {port: 8080}}}\n"
+	tests := []struct {
+		name, spec string
+		path       string
+		line, col  int
+		want       string
+	}{
+		{"unknown key", head + rt + "feats:\n  - name: orders\n    colour: red\n",
+			"feats.orders", 6, 5, `unknown key "colour"`},
+		{"duplicate feat", head + rt + "feats:\n  - name: orders\n  - {name: orders}\n",
+			"feats[1]", 6, 5, `duplicate feat name "orders" (first declared at aquamarine.yaml:5)`},
+		{"keyword feat in a list", head + rt + "feats:\n  - name: type\n",
+			"feats[0].name", 5, 11, `invalid feat name "type"`},
+		{"keyword feat in a map", head + rt + "feats:\n  func: {kind: atom}\n",
+			"feats.func", 5, 3, `invalid feat name "func"`},
+		{"uppercase feat key", head + rt + "feats:\n  Orders: {kind: atom}\n",
+			"feats.Orders", 5, 3, `invalid feat name "Orders"`},
+		{"invalid method", head + rt + "feats:\n  - name: orders\n    service: {methods: [Pay, pay]}\n",
+			"feats.orders.service.methods[1]", 6, 30, `invalid method name "pay"`},
+		{"duplicate method", head + rt + "feats:\n  - name: orders\n    service:\n      methods:\n        - Pay\n        - Pay\n",
+			"feats.orders.service.methods[1]", 9, 11, `duplicate method "Pay"`},
+		{"unknown engine", head + "runtime:\n  http: {api: {port: 8081}, web: {port: 8080}}\n  database: {engine: oracle}\n",
+			"runtime.database.engine", 5, 22, `unknown engine "oracle" (want one of sqlite|mongodb)`},
+		{"unknown repo implementation", head + rt + "feats:\n  - name: orders\n    repo_impl: [sqlite, redis]\n",
+			"feats.orders.repo_impl[1]", 6, 25, `unknown repo implementation "redis"`},
+		{"port out of range", head + "runtime: {http: {api: {port: 80810}, web: {port: 8080}}}\n",
+			"runtime.http.api.port", 3, 30, "port 80810 out of range 1-65535"},
+		{"shared port", head + "runtime:\n  http:\n    api: {port: 8080}\n    web: {port: 8080}\n",
+			"runtime.http.web.port", 6, 17, "api and web servers both listen on 127.0.0.1:8080"},
+		{"missing port", head + "runtime: {http: {api: {port: 8081}, web: {}}}\n",
+			"runtime.http.web.port", 3, 42, "port is required"},
+		{"route parameter", head + rt + "feats:\n  - name: orders\n    api: {routes: [{method: GET, path: '/orders/:id', handler: GetOrder}]}\n",
+			"feats.orders.api.routes[0].path", 6, 40, "write path parameters in braces (/orders/{id})"},
+	}
+	for _, tt := range tests {
+		_, diags := ParseSpec("aquamarine.yaml", []byte(tt.spec))
+		errs := diags.Errors()
+		if len(errs) > 0 && errs[0].Path == "feats" {
+			errs = errs[1:] // feats keyed by name, replaced in 0.2 but still checked
+		}
+		if len(errs) != 1 {
+			t.Errorf("%s: got %v, want one error", tt.name, diags)
+			continue
+		}
+		e := errs[0]
+		if e.Path != tt.path || e.Line != tt.line || e.Column != tt.col || !strings.Contains(e.Message, tt.want) {
+			t.Errorf("%s: got %s:%d:%d %s: %s, want %s:%d:%d %s: %s", tt.name,
+				e.File, e.Line, e.Column, e.Path, e.Message, e.File, tt.line, tt.col, tt.path, tt.want)
+		}
+	}
+}
+
+func TestSpecSource(t *testing.T) {
+	spec := `version: 0.2
+project: {name: shop, module: example.com/shop}
+runtime: {http: {api: {port: 8081}, web: {port: 8080}}}
+feats:
+  - name: orders
+    models:
+      Order:
+        fields:
+          total: {type: int}
+`
+	cfg, diags := ParseSpec("aquamarine.yaml", []byte(spec))
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	for path, want := range map[string]Pos{
+		"feats.orders":                           {File: "aquamarine.yaml", Line: 5, Column: 5},
+		"feats.orders.models.Order":              {File: "aquamarine.yaml", Line: 8, Column: 9},
+		"feats.orders.models.Order.fields.total": {File: "aquamarine.yaml", Line: 9, Column: 18},
+	} {
+		if got := cfg.Source[path]; got != want {
+			t.Errorf("Source[%s] = %+v, want %+v", path, got, want)
+		}
+	}
+}
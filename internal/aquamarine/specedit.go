@@ -0,0 +1,530 @@
+package aquamarine
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SpecEditor applies small additions to a spec file. Targets are located in
+// the yaml.v3 node tree and the new nodes, encoded by yaml.v3, are spliced
+// into the original text: comments, blank lines, alignment and key order of
+// everything else are left exactly as written.
+//
+// File is the spec itself or, for a spec with includes, the feat file being
+// edited; Spec then names the spec including it.
+type SpecEditor struct {
+	File  string
+	Spec  string   // the including spec; File itself when empty
+	lines []string // original text, line terminators included
+	root  *yaml.Node
+	ends  map[*yaml.Node]int // last line of each node as written
+	edits []textEdit
+}
+
+// blockStyle is yaml.v3's default, unnamed, node style.
+const blockStyle yaml.Style = 0
+
+// textEdit replaces lines [start, end) of the original text.
+type textEdit struct {
+	start, end int
+	lines      []string
+}
+
+// FieldArg is a field given on the command line as name:type[:validations],
+// validations being comma separated, with arguments after '=' (min=8). A
+// type ending in ? is nullable (bio:text?) and []T is a list of T
+// (tags:[]string).
+type FieldArg struct {
+	Name        string
+	Type        string
+	Of          string
+	Nullable    bool
+	Validations []Validation
+}
+
+// ParseFieldArg parses a name:type[:validations] field argument.
+func ParseFieldArg(arg string) (FieldArg, error) {
+	parts := strings.SplitN(arg, ":", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return FieldArg{}, fmt.Errorf("invalid field %q (want name:type[:validations])", arg)
+	}
+	f := FieldArg{Name: parts[0], Type: parts[1]}
+	f.Type, f.Nullable = strings.CutSuffix(f.Type, "?")
+	if of, ok := strings.CutPrefix(f.Type, "[]"); ok {
+		f.Type, f.Of = "list", of
+	}
+	if len(parts) == 3 && parts[2] != "" {
+		for _, v := range strings.Split(parts[2], ",") {
+			name, value, _ := strings.Cut(v, "=")
+			f.Validations = append(f.Validations, Validation{Name: name, Value: value})
+		}
+	}
+	return f, nil
+}
+
+// LoadSpecEditor reads the spec at path for editing.
+func LoadSpecEditor(path string) (*SpecEditor, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return newSpecEditor(path, b)
+}
+
+func newSpecEditor(path string, b []byte) (*SpecEditor, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, Diagnostics{syntaxDiagnostic(path, err)}
+	}
+	root := resolve(&doc)
+	if root == nil || root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: spec must be a mapping", path)
+	}
+	e := &SpecEditor{File: path, lines: splitLines(b), root: root, ends: map[*yaml.Node]int{}}
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		e.ends[n] = lastLine(n)
+		for _, c := range n.Content {
+			walk(c)
+		}
+	}
+	walk(root)
+	return e, nil
+}
+
+// Bytes returns the edited spec text.
+func (e *SpecEditor) Bytes() []byte {
+	lines := append([]string{}, e.lines...)
+	edits := make([]int, len(e.edits))
+	for i := range edits {
+		edits[i] = i
+	}
+	// Bottom up, so earlier line numbers stay valid. Inserts at the same
+	// line are applied last first, which keeps them in the order made.
+	sort.SliceStable(edits, func(i, j int) bool {
+		a, b := e.edits[edits[i]], e.edits[edits[j]]
+		if a.start != b.start {
+			return a.start > b.start
+		}
+		return edits[i] > edits[j]
+	})
+	for _, i := range edits {
+		ed := e.edits[i]
+		lines = append(lines[:ed.start], append(append([]string{}, ed.lines...), lines[ed.end:]...)...)
+	}
+	return []byte(strings.Join(lines, ""))
+}
+
+// Check validates the edited spec, cross references and includes included.
+func (e *SpecEditor) Check() Diagnostics {
+	spec := e.Spec
+	if spec == "" {
+		spec = e.File
+	}
+	cfg, diags := readSpec(spec, specOptions{edited: map[string][]byte{e.File: e.Bytes()}, optionalEnv: true})
+	if cfg != nil {
+		diags = append(diags, CheckSpec(cfg)...)
+	}
+	sortDiagnostics(diags)
+	return diags
+}
+
+// Save writes the edited spec back to its file.
+func (e *SpecEditor) Save() error {
+	return os.WriteFile(e.File, e.Bytes(), 0o644)
+}
+
+// AddFeature appends a feat with the given kind and empty models.
+func (e *SpecEditor) AddFeature(name, kind string, models []string) error {
+	if _, err := e.feature(name); err == nil {
+		return fmt.Errorf("feat %q already exists", name)
+	}
+	body := mappingNode(blockStyle, "kind", scalarNode(kind))
+	if len(models) > 0 {
+		ms := mappingNode(blockStyle)
+		for _, m := range models {
+			setKey(ms, m, mappingNode(yaml.FlowStyle, "fields", mappingNode(yaml.FlowStyle)))
+		}
+		setKey(body, "models", ms)
+	}
+
+	if _, feats := lookup(e.root, "feats"); feats != nil && feats.Kind == yaml.MappingNode {
+		e.addEntries(e.root, "feats", mappingNode(blockStyle, name, body))
+	} else {
+		e.addItem(e.root, "feats", withName(name, body))
+	}
+	return nil
+}
+
+// AddModel adds a model with fields to feat, or adds fields to an existing
+// model.
+func (e *SpecEditor) AddModel(feat, model string, fields []FieldArg) error {
+	f, err := e.feature(feat)
+	if err != nil {
+		return err
+	}
+	if _, single := lookup(f, "model"); single != nil {
+		if scalarValue(single, "name") != model {
+			return fmt.Errorf("feat %q declares a single model; run aquamarine upgrade to turn its model: into models:, then add %s", feat, model)
+		}
+		return e.addFields(single, fields)
+	}
+	_, models := lookup(f, "models")
+	if _, m := lookup(models, model); m != nil {
+		return e.addFields(m, fields)
+	}
+	fs := fieldsNode(fields)
+	if len(fields) == 0 {
+		fs.Style = yaml.FlowStyle
+	}
+	e.addEntries(f, "models", mappingNode(blockStyle, model, mappingNode(blockStyle, "fields", fs)))
+	return nil
+}
+
+func (e *SpecEditor) addFields(model *yaml.Node, fields []FieldArg) error {
+	_, existing := lookup(model, "fields")
+	for _, fa := range fields {
+		if _, v := lookup(existing, fa.Name); v != nil {
+			return fmt.Errorf("field %q already exists", fa.Name)
+		}
+	}
+	if len(fields) > 0 {
+		e.addEntries(model, "fields", fieldsNode(fields))
+	}
+	return nil
+}
+
+// AddEndpoint adds an API route to feat. The handler is also listed in
+// service.methods when the feat declares them.
+func (e *SpecEditor) AddEndpoint(feat, method, path, handler string) error {
+	f, err := e.feature(feat)
+	if err != nil {
+		return err
+	}
+	route := mappingNode(yaml.FlowStyle,
+		"method", scalarNode(method),
+		"path", scalarNode(path),
+		"handler", scalarNode(handler),
+	)
+
+	_, api := lookup(f, "api")
+	if api == nil {
+		routes := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{route}}
+		e.appendEntry(nil, f, "api", mappingNode(blockStyle, "routes", routes))
+	} else {
+		_, routes := lookup(api, "routes")
+		if routes != nil {
+			for _, r := range routes.Content {
+				if strings.EqualFold(scalarValue(r, "method"), method) && scalarValue(r, "path") == path {
+					return fmt.Errorf("feat %q already has a route %s %s", feat, method, path)
+				}
+			}
+		}
+		e.addItem(api, "routes", route)
+	}
+
+	if _, svc := lookup(f, "service"); svc != nil {
+		_, methods := lookup(svc, "methods")
+		if methods != nil && methods.Kind == yaml.SequenceNode {
+			for _, m := range methods.Content {
+				if m.Value == handler {
+					return nil
+				}
+			}
+			e.addItem(svc, "methods", scalarNode(handler))
+		}
+	}
+	return nil
+}
+
+// feature returns the mapping of the named feat, in list or map form, or
+// the whole file when it is an included feat file.
+func (e *SpecEditor) feature(name string) (*yaml.Node, error) {
+	_, feats := lookup(e.root, "feats")
+	switch {
+	case feats == nil && scalarValue(e.root, "name") == name:
+		return e.root, nil
+	case feats == nil:
+	case feats.Kind == yaml.SequenceNode:
+		for _, item := range feats.Content {
+			if scalarValue(item, "name") == name {
+				return resolve(item), nil
+			}
+		}
+	case feats.Kind == yaml.MappingNode:
+		if _, f := lookup(feats, name); f != nil && f.Kind == yaml.MappingNode {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown feat %q", name)
+}
+
+// addEntries adds the entries of add to the mapping under parent.container,
+// creating the container when missing.
+func (e *SpecEditor) addEntries(parent *yaml.Node, container string, add *yaml.Node) {
+	k, m := lookup(parent, container)
+	switch {
+	case m == nil:
+		e.appendEntry(nil, parent, container, add)
+	case m.Kind == yaml.MappingNode && m.Style&yaml.FlowStyle == 0 && len(m.Content) > 0:
+		for i := 0; i+1 < len(add.Content); i += 2 {
+			e.appendEntry(k, m, add.Content[i].Value, add.Content[i+1])
+		}
+	default:
+		if m.Kind != yaml.MappingNode {
+			m.Kind, m.Tag, m.Value, m.Content = yaml.MappingNode, "!!map", "", nil
+		}
+		m.Style = blockStyle
+		m.Content = append(m.Content, add.Content...)
+		e.rewrite(m)
+	}
+}
+
+// addItem appends item to the sequence under parent.container, creating
+// the container when missing. Flow sequences stay on one line.
+func (e *SpecEditor) addItem(parent *yaml.Node, container string, item *yaml.Node) {
+	_, s := lookup(parent, container)
+	switch {
+	case s == nil:
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{item}}
+		e.appendEntry(nil, parent, container, seq)
+	case s.Kind == yaml.SequenceNode && s.Style&yaml.FlowStyle == 0 && len(s.Content) > 0:
+		e.appendItem(s, item)
+	default:
+		if s.Kind != yaml.SequenceNode {
+			s.Kind, s.Tag, s.Value, s.Content = yaml.SequenceNode, "!!seq", "", nil
+		}
+		if len(s.Content) == 0 {
+			s.Style = blockStyle
+		}
+		s.Content = append(s.Content, item)
+		e.rewrite(s)
+	}
+}
+
+// appendEntry inserts key: value after the last entry of mapping m.
+// keyNode is the key holding m in its parent, used to indent the first entry
+// of an empty mapping; it may be nil when m has entries.
+func (e *SpecEditor) appendEntry(keyNode, m *yaml.Node, key string, value *yaml.Node) {
+	if m.Style&yaml.FlowStyle != 0 || (len(m.Content) == 0 && keyNode == nil) {
+		setKey(m, key, value)
+		e.rewrite(m)
+		return
+	}
+	indent := ""
+	if len(m.Content) > 0 {
+		indent = e.indentAt(m.Content[0])
+	} else {
+		indent = e.indentAt(keyNode) + "  "
+	}
+	at := lastLine(m)
+	setKey(m, key, value)
+	e.insert(at, indent, encodeNode(mappingNode(blockStyle, key, value)))
+}
+
+// appendItem inserts item after the last item of a block sequence.
+func (e *SpecEditor) appendItem(seq, item *yaml.Node) {
+	indent := e.dashIndent(seq.Content[0])
+	at := lastLine(seq)
+	seq.Content = append(seq.Content, item)
+	one := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{item}}
+	e.insert(at, indent, encodeNode(one))
+}
+
+// rewrite re-encodes the text of a changed node. Flow collections around it
+// become block style, up to the nearest entry or item of a block collection,
+// whose lines are replaced. Comments trailing that entry stay in the text.
+func (e *SpecEditor) rewrite(n *yaml.Node) {
+	path := nodePath(e.root, n)
+	for i := len(path) - 1; i > 0; i-- {
+		node, parent := path[i], path[i-1]
+		if parent.Style&yaml.FlowStyle != 0 {
+			parent.Style = blockStyle
+			continue
+		}
+		end := e.lastLine(node)
+		clearFootComments(node)
+		switch parent.Kind {
+		case yaml.MappingNode:
+			for j := 1; j < len(parent.Content); j += 2 {
+				if parent.Content[j] != node {
+					continue
+				}
+				k := parent.Content[j-1]
+				entry := mappingNode(blockStyle, k.Value, node)
+				entry.Content[0].LineComment = k.LineComment
+				if node.Kind != yaml.ScalarNode && node.Style&yaml.FlowStyle == 0 && node.LineComment != "" {
+					// Block collections do not carry a line comment; keep it on the key.
+					entry.Content[0].LineComment, node.LineComment = node.LineComment, ""
+				}
+				e.replace(k.Line, max(end, k.Line), e.indentAt(k), encodeNode(entry))
+			}
+		case yaml.SequenceNode:
+			one := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{node}}
+			e.replace(node.Line, end, e.dashIndent(node), encodeNode(one))
+		}
+		return
+	}
+}
+
+// replace swaps source lines first..last (1-based, inclusive).
+func (e *SpecEditor) replace(first, last int, indent string, lines []string) {
+	e.edits = append(e.edits, textEdit{start: first - 1, end: last, lines: indentLines(indent, lines)})
+}
+
+func (e *SpecEditor) insert(at int, indent string, lines []string) {
+	if at > 0 && !strings.HasSuffix(e.lines[at-1], "\n") {
+		e.lines[at-1] += "\n"
+	}
+	e.edits = append(e.edits, textEdit{start: at, end: at, lines: indentLines(indent, lines)})
+}
+
+// dashIndent returns the indentation of the "- " introducing item.
+func (e *SpecEditor) dashIndent(item *yaml.Node) string {
+	line := e.lines[item.Line-1]
+	dash := strings.LastIndex(line[:item.Column-1], "-")
+	return line[:max(dash, 0)]
+}
+
+// indentAt returns the indentation of n, which may follow a "- " on its line.
+func (e *SpecEditor) indentAt(n *yaml.Node) string {
+	return strings.Repeat(" ", n.Column-1)
+}
+
+// lastLine returns the last source line (1-based) spanned by n, including
+// the lines of nodes moved into it, or removed from it, since it was read.
+func (e *SpecEditor) lastLine(n *yaml.Node) int {
+	last := max(n.Line, e.ends[n])
+	for _, c := range n.Content {
+		last = max(last, e.lastLine(c))
+	}
+	return last
+}
+
+// lastLine returns the last source line (1-based) spanned by n.
+func lastLine(n *yaml.Node) int {
+	last := n.Line
+	for _, c := range n.Content {
+		last = max(last, lastLine(c))
+	}
+	return last
+}
+
+// nodePath returns the nodes from root down to n, or nil.
+func nodePath(root, n *yaml.Node) []*yaml.Node {
+	if root == n {
+		return []*yaml.Node{root}
+	}
+	for _, c := range root.Content {
+		if p := nodePath(c, n); p != nil {
+			return append([]*yaml.Node{root}, p...)
+		}
+	}
+	return nil
+}
+
+// clearFootComments drops the comments trailing n, which are left in place in
+// the text rather than re-encoded.
+func clearFootComments(n *yaml.Node) {
+	for n != nil {
+		n.FootComment = ""
+		if len(n.Content) == 0 {
+			return
+		}
+		n = n.Content[len(n.Content)-1]
+	}
+}
+
+func lookup(m *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	m = resolve(m)
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i], resolve(m.Content[i+1])
+		}
+	}
+	return nil, nil
+}
+
+func setKey(m *yaml.Node, key string, value *yaml.Node) {
+	m.Content = append(m.Content, scalarNode(key), value)
+}
+
+func withName(name string, body *yaml.Node) *yaml.Node {
+	body.Content = append([]*yaml.Node{scalarNode("name"), scalarNode(name)}, body.Content...)
+	return body
+}
+
+func scalarNode(v string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}
+}
+
+func mappingNode(style yaml.Style, kv ...any) *yaml.Node {
+	m := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Style: style}
+	for i := 0; i+1 < len(kv); i += 2 {
+		setKey(m, kv[i].(string), kv[i+1].(*yaml.Node))
+	}
+	return m
+}
+
+func fieldsNode(fields []FieldArg) *yaml.Node {
+	m := mappingNode(blockStyle)
+	for _, f := range fields {
+		setKey(m, f.Name, fieldNode(f))
+	}
+	return m
+}
+
+func fieldNode(f FieldArg) *yaml.Node {
+	n := mappingNode(yaml.FlowStyle, "type", scalarNode(f.Type))
+	if f.Of != "" {
+		setKey(n, "of", scalarNode(f.Of))
+	}
+	if f.Nullable {
+		b := scalarNode("true")
+		b.Tag = "!!bool"
+		setKey(n, "nullable", b)
+	}
+	if len(f.Validations) == 0 {
+		return n
+	}
+	vs := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+	for _, v := range f.Validations {
+		if v.Value == "" {
+			vs.Content = append(vs.Content, scalarNode(v.Name))
+			continue
+		}
+		arg := scalarNode(v.Value)
+		if _, err := strconv.Atoi(v.Value); err == nil {
+			arg.Tag = "!!int"
+		}
+		vs.Content = append(vs.Content, mappingNode(yaml.FlowStyle, v.Name, arg))
+	}
+	setKey(n, "validations", vs)
+	return n
+}
+
+func encodeNode(n *yaml.Node) []string {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	_ = enc.Encode(n) // in-memory nodes built above always encode
+	_ = enc.Close()
+	return splitDiffLines(buf.Bytes())
+}
+
+func indentLines(indent string, lines []string) []string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = indent + l
+	}
+	return out
+}
@@ -0,0 +1,229 @@
+package aquamarine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSpecEditor(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		edit func(*SpecEditor) error
+		want string
+	}{
+		{
+			name: "feat appended to a block list, comments kept",
+			spec: `# shop
+feats:
+  # the first feat
+  - name: orders # orders feat
+    kind: atom   # aligned
+
+    models: {Order: {fields: {total: {type: int}}}}
+# trailing
+`,
+			edit: func(e *SpecEditor) error { return e.AddFeature("billing", "domain", []string{"Invoice"}) },
+			want: `# shop
+feats:
+  # the first feat
+  - name: orders # orders feat
+    kind: atom   # aligned
+
+    models: {Order: {fields: {total: {type: int}}}}
+  - name: billing
+    kind: domain
+    models:
+      Invoice: {fields: {}}
+# trailing
+`,
+		},
+		{
+			name: "feat added to a map of feats",
+			spec: "feats:\n  orders: {kind: atom}\n",
+			edit: func(e *SpecEditor) error { return e.AddFeature("billing", "atom", nil) },
+			want: "feats:\n  orders: {kind: atom}\n  billing:\n    kind: atom\n",
+		},
+		{
+			name: "model added to block models",
+			spec: `feats:
+  - name: orders
+    models:
+      Order:
+        fields:
+          total: {type: int} # cents
+`,
+			edit: func(e *SpecEditor) error {
+				return e.AddModel("orders", "Refund", []FieldArg{{Name: "reason", Type: "text", Nullable: true}})
+			},
+			want: `feats:
+  - name: orders
+    models:
+      Order:
+        fields:
+          total: {type: int} # cents
+      Refund:
+        fields:
+          reason: {type: text, nullable: true}
+`,
+		},
+		{
+			name: "fields added to flow fields turn them to block style",
+			spec: `feats:
+  - name: orders # keep me
+    models:
+      Order: {fields: {total: {type: int}}} # and me
+`,
+			edit: func(e *SpecEditor) error {
+				return e.AddModel("orders", "Order", []FieldArg{{Name: "code", Type: "string", Validations: []Validation{{Name: "required"}, {Name: "max", Value: "8"}}}})
+			},
+			want: `feats:
+  - name: orders # keep me
+    models:
+      Order: # and me
+        fields:
+          total: {type: int}
+          code: {type: string, validations: [required, {max: 8}]}
+`,
+		},
+		{
+			name: "route added to flow routes, kept on one line, and block service methods",
+			spec: `feats:
+  - name: orders
+    api:
+      routes: [{method: GET, path: /orders, handler: ListOrders}]
+    service:
+      methods:
+        - ListOrders
+`,
+			edit: func(e *SpecEditor) error {
+				return e.AddEndpoint("orders", "POST", "/orders/{id}/pay", "CreateOrdersPay")
+			},
+			want: `feats:
+  - name: orders
+    api:
+      routes: [{method: GET, path: /orders, handler: ListOrders}, {method: POST, path: '/orders/{id}/pay', handler: CreateOrdersPay}]
+    service:
+      methods:
+        - ListOrders
+        - CreateOrdersPay
+`,
+		},
+		{
+			name: "api created",
+			spec: "feats:\n  - name: orders\n    kind: domain\n",
+			edit: func(e *SpecEditor) error { return e.AddEndpoint("orders", "GET", "/me", "GetMe") },
+			want: "feats:\n  - name: orders\n    kind: domain\n    api:\n      routes:\n        - {method: GET, path: /me, handler: GetMe}\n",
+		},
+	}
+	for _, tt := range tests {
+		e, err := newSpecEditor("aquamarine.yaml", []byte(tt.spec))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tt.edit(e); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got := string(e.Bytes()); got != tt.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSpecEditorErrors(t *testing.T) {
+	spec := `feats:
+  - name: orders
+    model: {name: Order, fields: {total: {type: int}}}
+    api:
+      routes: [{method: GET, path: /orders, handler: ListOrders}]
+`
+	tests := []struct {
+		name string
+		edit func(*SpecEditor) error
+		want string
+	}{
+		{"existing feat", func(e *SpecEditor) error { return e.AddFeature("orders", "atom", nil) }, `feat "orders" already exists`},
+		{"unknown feat", func(e *SpecEditor) error { return e.AddModel("billing", "Invoice", nil) }, `unknown feat "billing"`},
+		{"single model", func(e *SpecEditor) error { return e.AddModel("orders", "Refund", nil) }, "run aquamarine upgrade"},
+		{"existing field", func(e *SpecEditor) error {
+			return e.AddModel("orders", "Order", []FieldArg{{Name: "total", Type: "int"}})
+		}, `field "total" already exists`},
+		{"existing route", func(e *SpecEditor) error { return e.AddEndpoint("orders", "GET", "/orders", "List") }, "already has a route GET /orders"},
+	}
+	for _, tt := range tests {
+		e, err := newSpecEditor("aquamarine.yaml", []byte(spec))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tt.edit(e); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestHandlerName(t *testing.T) {
+	tests := []struct {
+		method, path, want string
+	}{
+		{"GET", "/me", "GetMe"},
+		{"POST", "/orders/{id}/items", "CreateOrdersItems"},
+		{"POST", "/invoices/:id/pay", "CreateInvoicesPay"},
+		{"PATCH", "/user-settings", "UpdateUserSettings"},
+		{"DELETE", "/", "Delete"},
+	}
+	for _, tt := range tests {
+		if got := handlerName(tt.method, tt.path); got != tt.want {
+			t.Errorf("handlerName(%s, %s) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+	if got := routePath("/invoices/:id/lines/:line"); got != "/invoices/{id}/lines/{line}" {
+		t.Errorf("routePath = %q", got)
+	}
+}
+
+func TestAddEndpointInclude(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"aquamarine.yaml":   includeSpec,
+		"feats/orders.yaml": "name: orders\nkind: atom\nmodels: {Order: {fields: {total: {type: int}}}}\n",
+		"feats/auth.yaml":   "name: auth\nmodels: {User: {fields: {email: {type: email}}}}\n",
+	})
+	spec := filepath.Join(dir, "aquamarine.yaml")
+	before, err := os.ReadFile(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := AddOptions{SpecFile: spec, NoGenerate: true}
+	if err := AddEndpoint(nil, opts, "orders", "post", "/orders/:id/pay", ""); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "feats", "orders.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{method: POST, path: '/orders/{id}/pay', handler: CreateOrdersPay}"; !strings.Contains(string(b), want) {
+		t.Errorf("feats/orders.yaml lacks %s:\n%s", want, b)
+	}
+	if after, _ := os.ReadFile(spec); string(after) != string(before) {
+		t.Errorf("including spec edited:\n%s", after)
+	}
+
+	e, err := LoadSpecEditor(featFile(spec, "orders"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Spec = spec
+	if err := e.AddEndpoint("orders", "GET", "/orders/:id", "GetOrder"); err != nil {
+		t.Fatal(err)
+	}
+	if errs := e.Check().Errors(); len(errs) != 1 || !strings.Contains(errs[0].Message, "write path parameters in braces (/orders/{id})") {
+		t.Errorf("route path with :id: got %v", errs)
+	}
+
+	err = AddModel(nil, opts, "orders", "Order", []FieldArg{{Name: "total", Type: "string"}})
+	if err == nil || !strings.Contains(err.Error(), `field "total" already exists`) {
+		t.Errorf("duplicate field: got %v", err)
+	}
+}
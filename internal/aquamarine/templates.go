@@ -0,0 +1,147 @@
+package aquamarine
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PacksEnv lists directories searched for named template packs, before the
+// user config dir (e.g. ~/.config/aquamarine/packs).
+const PacksEnv = "AQUAMARINE_PACKS"
+
+// overlayFS serves each file from the first layer that has it. Directories
+// list the union of the layers.
+type overlayFS []fs.FS
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	for _, l := range o {
+		f, err := l.Open(name)
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	seen := map[string]fs.DirEntry{}
+	found := false
+	for i := len(o) - 1; i >= 0; i-- {
+		entries, err := fs.ReadDir(o[i], name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		found = true
+		for _, e := range entries {
+			seen[e.Name()] = e
+		}
+	}
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	entries := make([]fs.DirEntry, 0, len(seen))
+	for _, n := range sortedKeys(seen) {
+		entries = append(entries, seen[n])
+	}
+	return entries, nil
+}
+
+// SpecTemplates layers template dirs over base, file by file. From highest
+// precedence: override (the --templates flag), the spec's templates.dir,
+// then its packs, last listed first. Spec paths are relative to specDir.
+// The warnings name the templates of those dirs that override none of
+// base, usually misspelled.
+func SpecTemplates(base fs.FS, spec *Config, specDir, override string) (fs.FS, []string, error) {
+	var dirs []string
+	if override != "" {
+		dirs = append(dirs, override)
+	}
+	if spec.Templates.Dir != "" {
+		dirs = append(dirs, filepath.Join(specDir, spec.Templates.Dir))
+	}
+	for i := len(spec.Templates.Packs) - 1; i >= 0; i-- {
+		dir, err := findPack(spec.Templates.Packs[i], specDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		dirs = append(dirs, dir)
+	}
+	if len(dirs) == 0 {
+		return base, nil, nil
+	}
+
+	var warnings []string
+	layers := overlayFS{}
+	for _, dir := range dirs {
+		if info, err := os.Stat(dir); err != nil {
+			return nil, nil, fmt.Errorf("templates: %w", err)
+		} else if !info.IsDir() {
+			return nil, nil, fmt.Errorf("templates: %s is not a directory", dir)
+		}
+		layer := os.DirFS(dir)
+		unknown, err := unknownTemplates(layer, base)
+		if err != nil {
+			return nil, nil, fmt.Errorf("templates: %s: %w", dir, err)
+		}
+		for _, name := range unknown {
+			warnings = append(warnings, fmt.Sprintf("%s overrides no built-in template", filepath.Join(dir, name)))
+		}
+		layers = append(layers, layer)
+	}
+	return append(layers, base), warnings, nil
+}
+
+// isPackPath tells a pack given as a path from a pack name.
+func isPackPath(pack string) bool {
+	return strings.HasPrefix(pack, ".") || strings.ContainsAny(pack, `/\`)
+}
+
+// findPack resolves a pack path against specDir, or looks a pack name up in
+// the PacksEnv dirs, then in the user config dir.
+func findPack(pack, specDir string) (string, error) {
+	if isPackPath(pack) {
+		if filepath.IsAbs(pack) {
+			return pack, nil
+		}
+		return filepath.Join(specDir, pack), nil
+	}
+	search := filepath.SplitList(os.Getenv(PacksEnv))
+	if dir, err := os.UserConfigDir(); err == nil {
+		search = append(search, filepath.Join(dir, "aquamarine", "packs"))
+	}
+	for _, dir := range search {
+		if info, err := os.Stat(filepath.Join(dir, pack)); err == nil && info.IsDir() {
+			return filepath.Join(dir, pack), nil
+		}
+	}
+	return "", fmt.Errorf("template pack %q not found in %s (set %s to add search dirs)", pack, strings.Join(search, ", "), PacksEnv)
+}
+
+// unknownTemplates lists the templates of layer that base does not have,
+// usually misspelled overrides.
+func unknownTemplates(layer, base fs.FS) ([]string, error) {
+	var unknown []string
+	err := fs.WalkDir(layer, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(name, ".tmpl") {
+			return nil
+		}
+		if _, err := fs.Stat(base, name); err != nil {
+			unknown = append(unknown, name)
+		}
+		return nil
+	})
+	return unknown, err
+}
@@ -0,0 +1,77 @@
+package aquamarine
+
+import (
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSpecTemplates(t *testing.T) {
+	base := fstest.MapFS{
+		"model.tmpl":   {Data: []byte("base model")},
+		"handler.tmpl": {Data: []byte("base handler")},
+		"main.tmpl":    {Data: []byte("base main")},
+	}
+	root := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		if err := safeWriteFile(filepath.Join(root, rel), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("packs/house/model.tmpl", "house model")
+	write("packs/house/handler.tmpl", "house handler")
+	write("spec/shared/handler.tmpl", "shared handler")
+	write("spec/templates/model.tmpl", "local model")
+	write("flag/main.tmpl", "flag main")
+	write("flag/mian.tmpl", "typo")
+	t.Setenv(PacksEnv, filepath.Join(root, "packs"))
+
+	spec := &Config{Templates: TemplatesConfig{Dir: "templates", Packs: []string{"house", "./shared"}}}
+	got, warnings, err := SpecTemplates(base, spec, filepath.Join(root, "spec"), filepath.Join(root, "flag"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(root, "flag", "mian.tmpl") + " overrides no built-in template"
+	if len(warnings) != 1 || warnings[0] != want {
+		t.Errorf("warnings = %q, want [%q]", warnings, want)
+	}
+	for name, want := range map[string]string{
+		"model.tmpl":   "local model",    // templates.dir over packs
+		"handler.tmpl": "shared handler", // later pack over earlier
+		"main.tmpl":    "flag main",      // --templates over everything
+	} {
+		b, err := fs.ReadFile(got, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != want {
+			t.Errorf("%s = %q, want %q", name, b, want)
+		}
+	}
+
+	entries, err := fs.ReadDir(got, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Errorf("ReadDir lists %d entries, want the 4 templates once each", len(entries))
+	}
+
+	spec.Templates.Packs = []string{"missing"}
+	if _, _, err := SpecTemplates(base, spec, filepath.Join(root, "spec"), ""); err == nil {
+		t.Error("missing pack: want an error")
+	}
+}
+
+func TestSpecTemplatesNone(t *testing.T) {
+	base := fstest.MapFS{}
+	got, warnings, err := SpecTemplates(base, &Config{}, ".", "")
+	if err != nil || warnings != nil {
+		t.Fatal(warnings, err)
+	}
+	if _, ok := got.(fstest.MapFS); !ok {
+		t.Errorf("no overrides: got %T, want the base FS", got)
+	}
+}
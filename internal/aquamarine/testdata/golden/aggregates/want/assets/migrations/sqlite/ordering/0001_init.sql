@@ -0,0 +1,38 @@
+-- ordering: initial schema
+
+CREATE TABLE IF NOT EXISTS orders (
+	id TEXT PRIMARY KEY,
+	customer TEXT NOT NULL,
+	total REAL NOT NULL,
+	placed_at TIMESTAMP,
+	revision INTEGER NOT NULL DEFAULT 1,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	created_by TEXT NOT NULL,
+	updated_by TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS products (
+	id TEXT PRIMARY KEY,
+	sku TEXT NOT NULL,
+	name TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS order_items (
+	id TEXT PRIMARY KEY,
+	order_id TEXT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	quantity INTEGER NOT NULL,
+	product_id TEXT NOT NULL REFERENCES products (id)
+);
+
+CREATE TABLE IF NOT EXISTS order_notes (
+	id TEXT PRIMARY KEY,
+	order_id TEXT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	body TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL,
+	updated_at TIMESTAMP NOT NULL,
+	created_by TEXT NOT NULL,
+	updated_by TEXT NOT NULL
+);
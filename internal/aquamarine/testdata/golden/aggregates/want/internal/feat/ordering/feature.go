@@ -0,0 +1,92 @@
+package ordering
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"example.com/orders/internal/platform"
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// Errors returned by the ordering feat.
+var (
+	ErrNotFound       = errors.New("ordering: not found")
+	ErrNotImplemented = errors.New("ordering: not implemented")
+)
+
+// ConflictError reports the update of an aggregate root saved by someone
+// else since it was read: the stored version is no longer the one the
+// update started from.
+type ConflictError struct {
+	Model   string
+	ID      uuid.UUID
+	Version int // the version the update started from
+	Current int // the stored version
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("ordering: %s %s is at version %d, not %d", e.Model, e.ID, e.Current, e.Version)
+}
+
+// Deps lists what the ordering feat needs from the rest of the app.
+type Deps struct {
+	OrderRepo   OrderRepo
+	ProductRepo ProductRepo
+}
+
+// Feature wires the ordering handlers. It is registered through am.Setup.
+type Feature struct {
+	xp             platform.XParams
+	deps           Deps
+	orderHandler   *OrderHandler
+	productHandler *ProductHandler
+}
+
+// New builds the ordering feat from its dependencies.
+func New(xp platform.XParams, deps Deps) (*Feature, error) {
+	f := &Feature{xp: xp, deps: deps}
+	f.orderHandler = NewOrderHandler(deps.OrderRepo, xp)
+	f.productHandler = NewProductHandler(deps.ProductRepo, xp)
+	return f, nil
+}
+
+// RegisterAPIRoutes mounts the ordering JSON endpoints.
+func (f *Feature) RegisterAPIRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Route("/ordering/orders", f.orderHandler.Routes)
+		r.Route("/ordering/products", f.productHandler.Routes)
+	})
+}
+
+// writeError maps feat errors to JSON error responses.
+func writeError(w http.ResponseWriter, log am.Logger, err error) {
+	var verrs am.ValidationErrors
+	var conflict *ConflictError
+	switch {
+	case errors.As(err, &verrs):
+		am.Error(w, http.StatusUnprocessableEntity, "validation_failed", "Validation failed", verrs...)
+	case errors.As(err, &conflict):
+		am.Error(w, http.StatusConflict, "conflict", "Resource was changed by another request")
+	case errors.Is(err, ErrNotFound):
+		am.Error(w, http.StatusNotFound, "not_found", "Resource not found")
+	case errors.Is(err, ErrNotImplemented):
+		am.Error(w, http.StatusNotImplemented, "not_implemented", "Not implemented")
+	default:
+		log.Error("request failed: ", err)
+		am.Error(w, http.StatusInternalServerError, "internal_error", "Internal error")
+	}
+}
+
+// parseID reads the {id} URL parameter.
+func parseID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		am.Error(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,17 @@
+package ordering
+
+import (
+	"github.com/google/uuid"
+)
+
+// LineItem is a model of the ordering feat.
+type LineItem struct {
+	ID        uuid.UUID `json:"id" bson:"_id"`
+	Quantity  int       `json:"quantity" bson:"quantity"`
+	ProductID uuid.UUID `json:"product_id" bson:"product_id"`
+}
+
+// NewLineItem returns a LineItem with a fresh ID.
+func NewLineItem() *LineItem {
+	return &LineItem{ID: uuid.New()}
+}
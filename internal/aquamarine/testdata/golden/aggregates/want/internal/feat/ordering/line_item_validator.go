@@ -0,0 +1,27 @@
+package ordering
+
+import (
+	"context"
+
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// LineItemValidator checks LineItem values before they are stored.
+type LineItemValidator struct{}
+
+// NewLineItemValidator creates a LineItemValidator.
+func NewLineItemValidator() *LineItemValidator {
+	return &LineItemValidator{}
+}
+
+// Validate returns every rule m breaks.
+func (v *LineItemValidator) Validate(ctx context.Context, m *LineItem) am.ValidationErrors {
+	var errs am.ValidationErrors
+	if !(m.Quantity >= 1) {
+		errs = append(errs, am.ValidationError{Field: "quantity", Code: "min", Message: "quantity must be at least 1"})
+	}
+	if !(am.IsRequiredUUID(m.ProductID)) {
+		errs = append(errs, am.ValidationError{Field: "product_id", Code: "required", Message: "product_id is required"})
+	}
+	return errs
+}
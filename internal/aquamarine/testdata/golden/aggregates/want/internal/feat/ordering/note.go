@@ -0,0 +1,34 @@
+package ordering
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// Note is a model of the ordering feat.
+type Note struct {
+	ID        uuid.UUID `json:"id" bson:"_id"`
+	Body      string    `json:"body" bson:"body"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+	CreatedBy uuid.UUID `json:"created_by" bson:"created_by"`
+	UpdatedBy uuid.UUID `json:"updated_by" bson:"updated_by"`
+}
+
+// NewNote returns a Note with a fresh ID.
+func NewNote() *Note {
+	return &Note{ID: uuid.New()}
+}
+
+// BeforeCreate sets audit fields for a new Note.
+func (m *Note) BeforeCreate() {
+	am.SetAuditFieldsBeforeCreate(&m.CreatedAt, &m.UpdatedAt, &m.CreatedBy, &m.UpdatedBy)
+}
+
+// BeforeUpdate refreshes audit fields before a Note is saved.
+func (m *Note) BeforeUpdate() {
+	am.SetAuditFieldsBeforeUpdate(&m.UpdatedAt, &m.UpdatedBy)
+}
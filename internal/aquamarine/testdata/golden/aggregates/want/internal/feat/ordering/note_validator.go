@@ -0,0 +1,24 @@
+package ordering
+
+import (
+	"context"
+
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// NoteValidator checks Note values before they are stored.
+type NoteValidator struct{}
+
+// NewNoteValidator creates a NoteValidator.
+func NewNoteValidator() *NoteValidator {
+	return &NoteValidator{}
+}
+
+// Validate returns every rule m breaks.
+func (v *NoteValidator) Validate(ctx context.Context, m *Note) am.ValidationErrors {
+	var errs am.ValidationErrors
+	if !(am.IsRequired(m.Body)) {
+		errs = append(errs, am.ValidationError{Field: "body", Code: "required", Message: "body is required"})
+	}
+	return errs
+}
@@ -0,0 +1,39 @@
+package ordering
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// Order is a model of the ordering feat.
+type Order struct {
+	ID        uuid.UUID  `json:"id" bson:"_id"`
+	Customer  string     `json:"customer" bson:"customer"`
+	Total     float64    `json:"total" bson:"total"`
+	PlacedAt  *time.Time `json:"placed_at" bson:"placed_at"`
+	Revision  int        `json:"revision" bson:"revision"`
+	Items     []LineItem `json:"items" bson:"items"`
+	Notes     []Note     `json:"notes" bson:"notes"`
+	CreatedAt time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" bson:"updated_at"`
+	CreatedBy uuid.UUID  `json:"created_by" bson:"created_by"`
+	UpdatedBy uuid.UUID  `json:"updated_by" bson:"updated_by"`
+}
+
+// NewOrder returns a Order with a fresh ID.
+func NewOrder() *Order {
+	return &Order{ID: uuid.New(), Items: []LineItem{}, Notes: []Note{}}
+}
+
+// BeforeCreate sets audit fields for a new Order.
+func (m *Order) BeforeCreate() {
+	am.SetAuditFieldsBeforeCreate(&m.CreatedAt, &m.UpdatedAt, &m.CreatedBy, &m.UpdatedBy)
+}
+
+// BeforeUpdate refreshes audit fields before a Order is saved.
+func (m *Order) BeforeUpdate() {
+	am.SetAuditFieldsBeforeUpdate(&m.UpdatedAt, &m.UpdatedBy)
+}
@@ -0,0 +1,42 @@
+package ordering
+
+import (
+	"reflect"
+
+	"github.com/google/uuid"
+)
+
+// prepareChildren readies the children of m, such as those decoded from a
+// request, to be saved over those of stored, nil for a new Order: a child
+// without an ID gets a fresh one. Audit fields never come from the request:
+// children stored does not have get fresh ones from BeforeCreate, the others
+// keep the stored ones, stamped by BeforeUpdate when the child changed. The
+// children are saved with m, as a whole: change them through the methods of
+// Order and save m with OrderRepo.Update.
+func (m *Order) prepareChildren(stored *Order) {
+	for i := range m.Items {
+		c := &m.Items[i]
+		if c.ID == uuid.Nil {
+			c.ID = uuid.New()
+		}
+	}
+	for i := range m.Notes {
+		c := &m.Notes[i]
+		if c.ID == uuid.Nil {
+			c.ID = uuid.New()
+		}
+		var cur *Note
+		if stored != nil {
+			cur, _ = stored.Note(c.ID)
+		}
+		if cur == nil {
+			c.CreatedBy, c.UpdatedBy = uuid.Nil, uuid.Nil
+			c.BeforeCreate()
+			continue
+		}
+		c.CreatedAt, c.UpdatedAt, c.CreatedBy, c.UpdatedBy = cur.CreatedAt, cur.UpdatedAt, cur.CreatedBy, cur.UpdatedBy
+		if !reflect.DeepEqual(*c, *cur) {
+			c.BeforeUpdate()
+		}
+	}
+}
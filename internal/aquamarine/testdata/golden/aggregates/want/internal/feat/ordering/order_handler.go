@@ -0,0 +1,128 @@
+package ordering
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"example.com/orders/internal/platform"
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// OrderHandler serves the Order JSON API.
+type OrderHandler struct {
+	repo      OrderRepo
+	validator *OrderValidator
+	log       am.Logger
+}
+
+// NewOrderHandler creates a OrderHandler backed by repo.
+func NewOrderHandler(repo OrderRepo, xp platform.XParams) *OrderHandler {
+	return &OrderHandler{
+		repo:      repo,
+		validator: NewOrderValidator(),
+		log:       xp.Log,
+	}
+}
+
+// Routes mounts the Order endpoints on r.
+func (h *OrderHandler) Routes(r chi.Router) {
+	r.Get("/", h.List)
+	r.Post("/", h.Create)
+	r.Get("/{id}", h.Get)
+	r.Put("/{id}", h.Update)
+	r.Delete("/{id}", h.Delete)
+}
+
+// List returns all Orders.
+func (h *OrderHandler) List(w http.ResponseWriter, r *http.Request) {
+	items, err := h.repo.List(r.Context())
+	if err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusOK, items, nil)
+}
+
+// Get returns a single Order.
+func (h *OrderHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	m, err := h.repo.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusOK, m, nil)
+}
+
+// Create stores a new Order.
+func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	m := NewOrder()
+	if err := json.NewDecoder(r.Body).Decode(m); err != nil {
+		am.Error(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+		return
+	}
+	m.ID = uuid.New()
+	m.BeforeCreate()
+	m.prepareChildren(nil)
+	if errs := h.validator.Validate(r.Context(), m); errs.HasErrors() {
+		writeError(w, h.log, errs)
+		return
+	}
+	if err := h.repo.Create(r.Context(), m); err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusCreated, m, nil)
+}
+
+// Update replaces an existing Order. The body carries the version it was read
+// at; a Order saved since then is a 409 conflict. Audit fields come
+// from the stored Order, never from the body.
+func (h *OrderHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	var m Order
+	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+		am.Error(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+		return
+	}
+	m.ID = id
+	stored, err := h.repo.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	m.CreatedAt, m.CreatedBy = stored.CreatedAt, stored.CreatedBy
+	m.BeforeUpdate()
+	m.prepareChildren(stored)
+	if errs := h.validator.Validate(r.Context(), &m); errs.HasErrors() {
+		writeError(w, h.log, errs)
+		return
+	}
+	if err := h.repo.Update(r.Context(), &m); err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusOK, m, nil)
+}
+
+// Delete removes a Order.
+func (h *OrderHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusNoContent, nil, nil)
+}
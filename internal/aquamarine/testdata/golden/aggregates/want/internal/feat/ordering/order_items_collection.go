@@ -0,0 +1,48 @@
+package ordering
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Item returns the item of the Order with the given id.
+func (m *Order) Item(id uuid.UUID) (*LineItem, bool) {
+	i := slices.IndexFunc(m.Items, func(c LineItem) bool { return c.ID == id })
+	if i < 0 {
+		return nil, false
+	}
+	return &m.Items[i], true
+}
+
+// AddItem appends c to the items of the Order, with a fresh ID unless
+// it has one, and returns that ID.
+func (m *Order) AddItem(c LineItem) uuid.UUID {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	m.Items = append(m.Items, c)
+	return c.ID
+}
+
+// UpdateItem replaces the item of the Order with c's ID. It
+// returns ErrNotFound when the Order has none.
+func (m *Order) UpdateItem(c LineItem) error {
+	cur, ok := m.Item(c.ID)
+	if !ok {
+		return ErrNotFound
+	}
+	*cur = c
+	return nil
+}
+
+// RemoveItem removes the item with the given id from the Order. It
+// returns ErrNotFound when the Order has none.
+func (m *Order) RemoveItem(id uuid.UUID) error {
+	i := slices.IndexFunc(m.Items, func(c LineItem) bool { return c.ID == id })
+	if i < 0 {
+		return ErrNotFound
+	}
+	m.Items = slices.Delete(m.Items, i, i+1)
+	return nil
+}
@@ -0,0 +1,51 @@
+package ordering
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Note returns the note of the Order with the given id.
+func (m *Order) Note(id uuid.UUID) (*Note, bool) {
+	i := slices.IndexFunc(m.Notes, func(c Note) bool { return c.ID == id })
+	if i < 0 {
+		return nil, false
+	}
+	return &m.Notes[i], true
+}
+
+// AddNote appends c to the notes of the Order, with a fresh ID unless
+// it has one, and returns that ID.
+func (m *Order) AddNote(c Note) uuid.UUID {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	c.BeforeCreate()
+	m.Notes = append(m.Notes, c)
+	return c.ID
+}
+
+// UpdateNote replaces the note of the Order with c's ID. It
+// returns ErrNotFound when the Order has none.
+func (m *Order) UpdateNote(c Note) error {
+	cur, ok := m.Note(c.ID)
+	if !ok {
+		return ErrNotFound
+	}
+	c.CreatedAt, c.CreatedBy = cur.CreatedAt, cur.CreatedBy
+	c.BeforeUpdate()
+	*cur = c
+	return nil
+}
+
+// RemoveNote removes the note with the given id from the Order. It
+// returns ErrNotFound when the Order has none.
+func (m *Order) RemoveNote(id uuid.UUID) error {
+	i := slices.IndexFunc(m.Notes, func(c Note) bool { return c.ID == id })
+	if i < 0 {
+		return ErrNotFound
+	}
+	m.Notes = slices.Delete(m.Notes, i, i+1)
+	return nil
+}
@@ -0,0 +1,17 @@
+package ordering
+
+// SQL statements used by OrderSQLiteRepo.
+const (
+	insertOrderSQL      = `INSERT INTO orders (id, customer, total, placed_at, revision, created_at, updated_at, created_by, updated_by) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	selectOrderSQL      = `SELECT id, customer, total, placed_at, revision, created_at, updated_at, created_by, updated_by FROM orders WHERE id = ?`
+	listOrderSQL        = `SELECT id, customer, total, placed_at, revision, created_at, updated_at, created_by, updated_by FROM orders ORDER BY id`
+	updateOrderSQL      = `UPDATE orders SET customer = ?, total = ?, placed_at = ?, updated_at = ?, updated_by = ?, revision = revision + 1 WHERE id = ? AND revision = ?`
+	versionOrderSQL     = `SELECT revision FROM orders WHERE id = ?`
+	deleteOrderSQL      = `DELETE FROM orders WHERE id = ?`
+	orderItemsInsertSQL = `INSERT INTO order_items (id, quantity, product_id, order_id, position) VALUES (?, ?, ?, ?, ?)`
+	orderItemsSelectSQL = `SELECT id, quantity, product_id FROM order_items WHERE order_id = ? ORDER BY position`
+	orderItemsDeleteSQL = `DELETE FROM order_items WHERE order_id = ?`
+	orderNotesInsertSQL = `INSERT INTO order_notes (id, body, created_at, updated_at, created_by, updated_by, order_id, position) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	orderNotesSelectSQL = `SELECT id, body, created_at, updated_at, created_by, updated_by FROM order_notes WHERE order_id = ? ORDER BY position`
+	orderNotesDeleteSQL = `DELETE FROM order_notes WHERE order_id = ?`
+)
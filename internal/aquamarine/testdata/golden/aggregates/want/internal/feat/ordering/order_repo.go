@@ -0,0 +1,20 @@
+package ordering
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// OrderRepo persists Order values.
+// Get, Update and Delete return ErrNotFound when no Order matches.
+// Order is an aggregate root: its repo saves and loads it with its children
+// as a whole. Update returns a *ConflictError when the stored Order is no
+// longer at m.Revision.
+type OrderRepo interface {
+	Create(ctx context.Context, m *Order) error
+	Get(ctx context.Context, id uuid.UUID) (*Order, error)
+	List(ctx context.Context) ([]Order, error)
+	Update(ctx context.Context, m *Order) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,218 @@
+package ordering
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// OrderSQLiteRepo is a OrderRepo backed by SQLite.
+type OrderSQLiteRepo struct {
+	db *sql.DB
+}
+
+// NewOrderSQLiteRepo creates a OrderSQLiteRepo on db.
+func NewOrderSQLiteRepo(db *sql.DB) *OrderSQLiteRepo {
+	return &OrderSQLiteRepo{db: db}
+}
+
+// Create inserts m and its children in one transaction, at version 1.
+func (r *OrderSQLiteRepo) Create(ctx context.Context, m *Order) error {
+	m.Revision = 1
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, insertOrderSQL, m.ID, m.Customer, m.Total, m.PlacedAt, m.Revision, m.CreatedAt, m.UpdatedAt, m.CreatedBy, m.UpdatedBy); err != nil {
+		return err
+	}
+	if err := saveOrderChildren(ctx, tx, m); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Get returns the Order with the given id and its children.
+func (r *OrderSQLiteRepo) Get(ctx context.Context, id uuid.UUID) (*Order, error) {
+	m, err := scanOrder(r.db.QueryRowContext(ctx, selectOrderSQL, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := loadOrderChildren(ctx, r.db, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// List returns every Order.
+func (r *OrderSQLiteRepo) List(ctx context.Context) ([]Order, error) {
+	return queryOrders(ctx, r.db, listOrderSQL)
+}
+
+// Update saves m and its children over the stored Order in one
+// transaction. The stored Order must still be at m.Revision: otherwise
+// Update returns a *ConflictError. On success m.Revision is the new version.
+func (r *OrderSQLiteRepo) Update(ctx context.Context, m *Order) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	res, err := tx.ExecContext(ctx, updateOrderSQL, m.Customer, m.Total, m.PlacedAt, m.UpdatedAt, m.UpdatedBy, m.ID, m.Revision)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		var current int
+		err := tx.QueryRowContext(ctx, versionOrderSQL, m.ID).Scan(&current)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		return &ConflictError{Model: "Order", ID: m.ID, Version: m.Revision, Current: current}
+	}
+	if err := saveOrderChildren(ctx, tx, m); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	m.Revision++
+	return nil
+}
+
+// Delete removes the Order with the given id; its children go with it.
+func (r *OrderSQLiteRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, deleteOrderSQL, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// queryOrders runs a query selecting Order columns.
+func queryOrders(ctx context.Context, db *sql.DB, query string, args ...any) ([]Order, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Order{}
+	for rows.Next() {
+		m, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	for i := range items {
+		if err := loadOrderChildren(ctx, db, &items[i]); err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
+
+func scanOrder(row interface{ Scan(dest ...any) error }) (*Order, error) {
+	var m Order
+	if err := row.Scan(&m.ID, &m.Customer, &m.Total, &m.PlacedAt, &m.Revision, &m.CreatedAt, &m.UpdatedAt, &m.CreatedBy, &m.UpdatedBy); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// saveOrderChildren replaces the stored children of m with its own,
+// in their order.
+func saveOrderChildren(ctx context.Context, tx *sql.Tx, m *Order) error {
+	if _, err := tx.ExecContext(ctx, orderItemsDeleteSQL, m.ID); err != nil {
+		return err
+	}
+	for i, c := range m.Items {
+		if _, err := tx.ExecContext(ctx, orderItemsInsertSQL, c.ID, c.Quantity, c.ProductID, m.ID, i); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, orderNotesDeleteSQL, m.ID); err != nil {
+		return err
+	}
+	for i, c := range m.Notes {
+		if _, err := tx.ExecContext(ctx, orderNotesInsertSQL, c.ID, c.Body, c.CreatedAt, c.UpdatedAt, c.CreatedBy, c.UpdatedBy, m.ID, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadOrderChildren reads the stored children of m into it.
+func loadOrderChildren(ctx context.Context, db *sql.DB, m *Order) error {
+	var err error
+	if m.Items, err = loadOrderItems(ctx, db, m.ID); err != nil {
+		return err
+	}
+	if m.Notes, err = loadOrderNotes(ctx, db, m.ID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// loadOrderItems returns the items of the Order id, in their order.
+func loadOrderItems(ctx context.Context, db *sql.DB, id uuid.UUID) ([]LineItem, error) {
+	rows, err := db.QueryContext(ctx, orderItemsSelectSQL, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []LineItem{}
+	for rows.Next() {
+		var c LineItem
+		if err := rows.Scan(&c.ID, &c.Quantity, &c.ProductID); err != nil {
+			return nil, err
+		}
+		items = append(items, c)
+	}
+	return items, rows.Err()
+}
+
+// loadOrderNotes returns the notes of the Order id, in their order.
+func loadOrderNotes(ctx context.Context, db *sql.DB, id uuid.UUID) ([]Note, error) {
+	rows, err := db.QueryContext(ctx, orderNotesSelectSQL, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Note{}
+	for rows.Next() {
+		var c Note
+		if err := rows.Scan(&c.ID, &c.Body, &c.CreatedAt, &c.UpdatedAt, &c.CreatedBy, &c.UpdatedBy); err != nil {
+			return nil, err
+		}
+		items = append(items, c)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,37 @@
+package ordering
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// OrderValidator checks Order values before they are stored.
+type OrderValidator struct{}
+
+// NewOrderValidator creates a OrderValidator.
+func NewOrderValidator() *OrderValidator {
+	return &OrderValidator{}
+}
+
+// Validate returns every rule m breaks.
+func (v *OrderValidator) Validate(ctx context.Context, m *Order) am.ValidationErrors {
+	var errs am.ValidationErrors
+	if !(am.IsRequired(m.Customer)) {
+		errs = append(errs, am.ValidationError{Field: "customer", Code: "required", Message: "customer is required"})
+	}
+	for i := range m.Items {
+		for _, e := range NewLineItemValidator().Validate(ctx, &m.Items[i]) {
+			e.Field = fmt.Sprintf("items[%d].%s", i, e.Field)
+			errs = append(errs, e)
+		}
+	}
+	for i := range m.Notes {
+		for _, e := range NewNoteValidator().Validate(ctx, &m.Notes[i]) {
+			e.Field = fmt.Sprintf("notes[%d].%s", i, e.Field)
+			errs = append(errs, e)
+		}
+	}
+	return errs
+}
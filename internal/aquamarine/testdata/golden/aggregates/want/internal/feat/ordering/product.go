@@ -0,0 +1,17 @@
+package ordering
+
+import (
+	"github.com/google/uuid"
+)
+
+// Product is a model of the ordering feat.
+type Product struct {
+	ID   uuid.UUID `json:"id" bson:"_id"`
+	SKU  string    `json:"sku" bson:"sku"`
+	Name string    `json:"name" bson:"name"`
+}
+
+// NewProduct returns a Product with a fresh ID.
+func NewProduct() *Product {
+	return &Product{ID: uuid.New()}
+}
@@ -0,0 +1,116 @@
+package ordering
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"example.com/orders/internal/platform"
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// ProductHandler serves the Product JSON API.
+type ProductHandler struct {
+	repo      ProductRepo
+	validator *ProductValidator
+	log       am.Logger
+}
+
+// NewProductHandler creates a ProductHandler backed by repo.
+func NewProductHandler(repo ProductRepo, xp platform.XParams) *ProductHandler {
+	return &ProductHandler{
+		repo:      repo,
+		validator: NewProductValidator(),
+		log:       xp.Log,
+	}
+}
+
+// Routes mounts the Product endpoints on r.
+func (h *ProductHandler) Routes(r chi.Router) {
+	r.Get("/", h.List)
+	r.Post("/", h.Create)
+	r.Get("/{id}", h.Get)
+	r.Put("/{id}", h.Update)
+	r.Delete("/{id}", h.Delete)
+}
+
+// List returns all Products.
+func (h *ProductHandler) List(w http.ResponseWriter, r *http.Request) {
+	items, err := h.repo.List(r.Context())
+	if err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusOK, items, nil)
+}
+
+// Get returns a single Product.
+func (h *ProductHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	m, err := h.repo.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusOK, m, nil)
+}
+
+// Create stores a new Product.
+func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
+	m := NewProduct()
+	if err := json.NewDecoder(r.Body).Decode(m); err != nil {
+		am.Error(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+		return
+	}
+	m.ID = uuid.New()
+	if errs := h.validator.Validate(r.Context(), m); errs.HasErrors() {
+		writeError(w, h.log, errs)
+		return
+	}
+	if err := h.repo.Create(r.Context(), m); err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusCreated, m, nil)
+}
+
+// Update replaces an existing Product.
+func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	var m Product
+	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+		am.Error(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+		return
+	}
+	m.ID = id
+	if errs := h.validator.Validate(r.Context(), &m); errs.HasErrors() {
+		writeError(w, h.log, errs)
+		return
+	}
+	if err := h.repo.Update(r.Context(), &m); err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusOK, m, nil)
+}
+
+// Delete removes a Product.
+func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusNoContent, nil, nil)
+}
@@ -0,0 +1,10 @@
+package ordering
+
+// SQL statements used by ProductSQLiteRepo.
+const (
+	insertProductSQL = `INSERT INTO products (id, sku, name) VALUES (?, ?, ?)`
+	selectProductSQL = `SELECT id, sku, name FROM products WHERE id = ?`
+	listProductSQL   = `SELECT id, sku, name FROM products ORDER BY id`
+	updateProductSQL = `UPDATE products SET sku = ?, name = ? WHERE id = ?`
+	deleteProductSQL = `DELETE FROM products WHERE id = ?`
+)
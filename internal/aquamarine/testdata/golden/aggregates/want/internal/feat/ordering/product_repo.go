@@ -0,0 +1,17 @@
+package ordering
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ProductRepo persists Product values.
+// Get, Update and Delete return ErrNotFound when no Product matches.
+type ProductRepo interface {
+	Create(ctx context.Context, m *Product) error
+	Get(ctx context.Context, id uuid.UUID) (*Product, error)
+	List(ctx context.Context) ([]Product, error)
+	Update(ctx context.Context, m *Product) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,98 @@
+package platform
+
+import (
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// Config is the runtime configuration of orders.
+type Config struct {
+	App      AppConfig      `yaml:"app"`
+	HTTP     HTTPConfig     `yaml:"http"`
+	Database DatabaseConfig `yaml:"database"`
+	Log      LogConfig      `yaml:"log"`
+}
+
+// AppConfig holds application identity settings.
+type AppConfig struct {
+	Name string `yaml:"name"`
+}
+
+// HTTPConfig holds the API and web server endpoints.
+type HTTPConfig struct {
+	API Endpoint `yaml:"api"`
+	Web Endpoint `yaml:"web"`
+}
+
+// Endpoint is a host and port to listen on.
+type Endpoint struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+}
+
+// Addr returns the endpoint in host:port form.
+func (e Endpoint) Addr() string {
+	return net.JoinHostPort(e.Host, strconv.Itoa(e.Port))
+}
+
+// DatabaseConfig holds storage settings.
+type DatabaseConfig struct {
+	Engine string `yaml:"engine"`
+	DSN    string `yaml:"dsn"`
+	Name   string `yaml:"name"`
+}
+
+// LogConfig holds logging settings.
+type LogConfig struct {
+	Level string `yaml:"level"`
+}
+
+// LoadConfig reads the YAML config at path from fsys, then applies the
+// DATABASE_DSN, LOG_LEVEL, API_PORT and WEB_PORT environment overrides.
+// Without DATABASE_DSN, the ${VAR} references of the dsn are expanded.
+func LoadConfig(fsys fs.FS, path string) (*Config, error) {
+	b, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read config: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("cannot parse config: %w", err)
+	}
+
+	if v := os.Getenv("DATABASE_DSN"); v != "" {
+		cfg.Database.DSN = v
+	} else if cfg.Database.DSN, err = am.ExpandEnv(cfg.Database.DSN); err != nil {
+		return nil, fmt.Errorf("invalid database dsn: %w", err)
+	}
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		cfg.Log.Level = v
+	}
+	if err := portFromEnv("API_PORT", &cfg.HTTP.API.Port); err != nil {
+		return nil, err
+	}
+	if err := portFromEnv("WEB_PORT", &cfg.HTTP.Web.Port); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func portFromEnv(key string, port *int) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	p, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", key, err)
+	}
+	*port = p
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+
+	"github.com/go-chi/chi/v5"
+	_ "modernc.org/sqlite"
+
+	"example.com/orders/internal/feat/ordering"
+	"example.com/orders/internal/platform"
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+//go:embed assets
+var assetsFS embed.FS
+
+func main() {
+	cfg, err := platform.LoadConfig(assetsFS, "assets/config/config.yaml")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logger := am.NewLogger(cfg.Log.Level)
+	xp := platform.XParams{Cfg: cfg, Log: logger}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	db, err := sql.Open("sqlite", cfg.Database.DSN)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := am.MigrateSQL(ctx, db, assetsFS, "assets/migrations/sqlite", "ordering"); err != nil {
+		log.Fatal(err)
+	}
+	if err := am.SeedSQL(ctx, db, assetsFS, "assets/seeds/sqlite", "ordering"); err != nil {
+		log.Fatal(err)
+	}
+
+	orderingFeat, err := ordering.New(xp, ordering.Deps{
+		OrderRepo:   ordering.NewOrderSQLiteRepo(db),
+		ProductRepo: ordering.NewProductSQLiteRepo(db),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	apiRouter := chi.NewRouter()
+	webRouter := chi.NewRouter()
+
+	apiRouter.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("api ok"))
+	})
+
+	webRouter.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("web ok"))
+	})
+
+	deps := []any{orderingFeat}
+
+	starts, stops := am.Setup(ctx, apiRouter, webRouter, deps...)
+
+	if err := am.Start(ctx, starts, stops); err != nil {
+		log.Fatal(err)
+	}
+
+	servers := []am.Server{
+		{Name: "API", Addr: cfg.HTTP.API.Addr(), Handler: apiRouter},
+		{Name: "Web", Addr: cfg.HTTP.Web.Addr(), Handler: webRouter},
+	}
+
+	am.StartServers(servers, logger)
+
+	<-ctx.Done()
+
+	am.GracefulShutdown(servers, stops, logger)
+}
@@ -0,0 +1,10 @@
+-- notes: initial schema
+
+CREATE TABLE IF NOT EXISTS notes (
+	id TEXT PRIMARY KEY,
+	title TEXT NOT NULL,
+	body TEXT NOT NULL,
+	pinned INTEGER NOT NULL,
+	priority INTEGER NOT NULL,
+	score REAL NOT NULL
+);
@@ -0,0 +1,89 @@
+package notes
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"example.com/notes/internal/platform"
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// Errors returned by the notes feat.
+var (
+	ErrNotFound       = errors.New("notes: not found")
+	ErrNotImplemented = errors.New("notes: not implemented")
+)
+
+// Deps lists what the notes feat needs from the rest of the app.
+type Deps struct {
+	NoteRepo NoteRepo
+}
+
+// Feature wires the notes handlers. It is registered through am.Setup.
+type Feature struct {
+	xp          platform.XParams
+	deps        Deps
+	svc         Service
+	noteHandler *NoteHandler
+}
+
+// New builds the notes feat from its dependencies.
+func New(xp platform.XParams, deps Deps) (*Feature, error) {
+	f := &Feature{xp: xp, deps: deps}
+	f.svc = newService(deps)
+	f.noteHandler = NewNoteHandler(deps.NoteRepo, xp)
+	return f, nil
+}
+
+// Service returns the notes use cases for other feats to consume.
+func (f *Feature) Service() Service {
+	return f.svc
+}
+
+// RegisterAPIRoutes mounts the notes JSON endpoints.
+func (f *Feature) RegisterAPIRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Route("/notes/notes", f.noteHandler.Routes)
+		r.Get("/notes/pinned", f.handleListPinned)
+	})
+}
+
+// handleListPinned serves ListPinned over JSON.
+func (f *Feature) handleListPinned(w http.ResponseWriter, r *http.Request) {
+	var req ListPinnedRequest
+	res, err := f.svc.ListPinned(r.Context(), req)
+	if err != nil {
+		writeError(w, f.xp.Log, err)
+		return
+	}
+	am.Respond(w, http.StatusOK, res, nil)
+}
+
+// writeError maps feat errors to JSON error responses.
+func writeError(w http.ResponseWriter, log am.Logger, err error) {
+	var verrs am.ValidationErrors
+	switch {
+	case errors.As(err, &verrs):
+		am.Error(w, http.StatusUnprocessableEntity, "validation_failed", "Validation failed", verrs...)
+	case errors.Is(err, ErrNotFound):
+		am.Error(w, http.StatusNotFound, "not_found", "Resource not found")
+	case errors.Is(err, ErrNotImplemented):
+		am.Error(w, http.StatusNotImplemented, "not_implemented", "Not implemented")
+	default:
+		log.Error("request failed: ", err)
+		am.Error(w, http.StatusInternalServerError, "internal_error", "Internal error")
+	}
+}
+
+// parseID reads the {id} URL parameter.
+func parseID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		am.Error(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,20 @@
+package notes
+
+import (
+	"github.com/google/uuid"
+)
+
+// Note is a model of the notes feat.
+type Note struct {
+	ID       uuid.UUID `json:"id" bson:"_id"`
+	Title    string    `json:"title" bson:"title"`
+	Body     string    `json:"body" bson:"body"`
+	Pinned   bool      `json:"pinned" bson:"pinned"`
+	Priority int       `json:"priority" bson:"priority"`
+	Score    float64   `json:"score" bson:"score"`
+}
+
+// NewNote returns a Note with a fresh ID.
+func NewNote() *Note {
+	return &Note{ID: uuid.New()}
+}
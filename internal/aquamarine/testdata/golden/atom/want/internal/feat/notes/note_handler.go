@@ -0,0 +1,116 @@
+package notes
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"example.com/notes/internal/platform"
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// NoteHandler serves the Note JSON API.
+type NoteHandler struct {
+	repo      NoteRepo
+	validator *NoteValidator
+	log       am.Logger
+}
+
+// NewNoteHandler creates a NoteHandler backed by repo.
+func NewNoteHandler(repo NoteRepo, xp platform.XParams) *NoteHandler {
+	return &NoteHandler{
+		repo:      repo,
+		validator: NewNoteValidator(),
+		log:       xp.Log,
+	}
+}
+
+// Routes mounts the Note endpoints on r.
+func (h *NoteHandler) Routes(r chi.Router) {
+	r.Get("/", h.List)
+	r.Post("/", h.Create)
+	r.Get("/{id}", h.Get)
+	r.Put("/{id}", h.Update)
+	r.Delete("/{id}", h.Delete)
+}
+
+// List returns all Notes.
+func (h *NoteHandler) List(w http.ResponseWriter, r *http.Request) {
+	items, err := h.repo.List(r.Context())
+	if err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusOK, items, nil)
+}
+
+// Get returns a single Note.
+func (h *NoteHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	m, err := h.repo.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusOK, m, nil)
+}
+
+// Create stores a new Note.
+func (h *NoteHandler) Create(w http.ResponseWriter, r *http.Request) {
+	m := NewNote()
+	if err := json.NewDecoder(r.Body).Decode(m); err != nil {
+		am.Error(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+		return
+	}
+	m.ID = uuid.New()
+	if errs := h.validator.Validate(r.Context(), m); errs.HasErrors() {
+		writeError(w, h.log, errs)
+		return
+	}
+	if err := h.repo.Create(r.Context(), m); err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusCreated, m, nil)
+}
+
+// Update replaces an existing Note.
+func (h *NoteHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	var m Note
+	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+		am.Error(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+		return
+	}
+	m.ID = id
+	if errs := h.validator.Validate(r.Context(), &m); errs.HasErrors() {
+		writeError(w, h.log, errs)
+		return
+	}
+	if err := h.repo.Update(r.Context(), &m); err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusOK, m, nil)
+}
+
+// Delete removes a Note.
+func (h *NoteHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusNoContent, nil, nil)
+}
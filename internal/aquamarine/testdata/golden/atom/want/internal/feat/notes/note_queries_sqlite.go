@@ -0,0 +1,10 @@
+package notes
+
+// SQL statements used by NoteSQLiteRepo.
+const (
+	insertNoteSQL = `INSERT INTO notes (id, title, body, pinned, priority, score) VALUES (?, ?, ?, ?, ?, ?)`
+	selectNoteSQL = `SELECT id, title, body, pinned, priority, score FROM notes WHERE id = ?`
+	listNoteSQL   = `SELECT id, title, body, pinned, priority, score FROM notes ORDER BY id`
+	updateNoteSQL = `UPDATE notes SET title = ?, body = ?, pinned = ?, priority = ?, score = ? WHERE id = ?`
+	deleteNoteSQL = `DELETE FROM notes WHERE id = ?`
+)
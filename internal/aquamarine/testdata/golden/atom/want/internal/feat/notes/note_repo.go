@@ -0,0 +1,17 @@
+package notes
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// NoteRepo persists Note values.
+// Get, Update and Delete return ErrNotFound when no Note matches.
+type NoteRepo interface {
+	Create(ctx context.Context, m *Note) error
+	Get(ctx context.Context, id uuid.UUID) (*Note, error)
+	List(ctx context.Context) ([]Note, error)
+	Update(ctx context.Context, m *Note) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,98 @@
+package notes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// NoteSQLiteRepo is a NoteRepo backed by SQLite.
+type NoteSQLiteRepo struct {
+	db *sql.DB
+}
+
+// NewNoteSQLiteRepo creates a NoteSQLiteRepo on db.
+func NewNoteSQLiteRepo(db *sql.DB) *NoteSQLiteRepo {
+	return &NoteSQLiteRepo{db: db}
+}
+
+// Create inserts m.
+func (r *NoteSQLiteRepo) Create(ctx context.Context, m *Note) error {
+	_, err := r.db.ExecContext(ctx, insertNoteSQL, m.ID, m.Title, m.Body, m.Pinned, m.Priority, m.Score)
+	return err
+}
+
+// Get returns the Note with the given id.
+func (r *NoteSQLiteRepo) Get(ctx context.Context, id uuid.UUID) (*Note, error) {
+	m, err := scanNote(r.db.QueryRowContext(ctx, selectNoteSQL, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return m, err
+}
+
+// List returns every Note.
+func (r *NoteSQLiteRepo) List(ctx context.Context) ([]Note, error) {
+	return queryNotes(ctx, r.db, listNoteSQL)
+}
+
+// Update saves m over the stored Note.
+func (r *NoteSQLiteRepo) Update(ctx context.Context, m *Note) error {
+	res, err := r.db.ExecContext(ctx, updateNoteSQL, m.Title, m.Body, m.Pinned, m.Priority, m.Score, m.ID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes the Note with the given id.
+func (r *NoteSQLiteRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, deleteNoteSQL, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// queryNotes runs a query selecting Note columns.
+func queryNotes(ctx context.Context, db *sql.DB, query string, args ...any) ([]Note, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Note{}
+	for rows.Next() {
+		m, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *m)
+	}
+	return items, rows.Err()
+}
+
+func scanNote(row interface{ Scan(dest ...any) error }) (*Note, error) {
+	var m Note
+	if err := row.Scan(&m.ID, &m.Title, &m.Body, &m.Pinned, &m.Priority, &m.Score); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
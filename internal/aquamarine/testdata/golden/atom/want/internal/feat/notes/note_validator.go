@@ -0,0 +1,30 @@
+package notes
+
+import (
+	"context"
+
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// NoteValidator checks Note values before they are stored.
+type NoteValidator struct{}
+
+// NewNoteValidator creates a NoteValidator.
+func NewNoteValidator() *NoteValidator {
+	return &NoteValidator{}
+}
+
+// Validate returns every rule m breaks.
+func (v *NoteValidator) Validate(ctx context.Context, m *Note) am.ValidationErrors {
+	var errs am.ValidationErrors
+	if !(am.IsRequired(m.Title)) {
+		errs = append(errs, am.ValidationError{Field: "title", Code: "required", Message: "title is required"})
+	}
+	if !(am.MaxLength(m.Title, 120)) {
+		errs = append(errs, am.ValidationError{Field: "title", Code: "max", Message: "title must be at most 120 characters"})
+	}
+	if !(m.Priority >= 1) {
+		errs = append(errs, am.ValidationError{Field: "priority", Code: "min", Message: "priority must be at least 1"})
+	}
+	return errs
+}
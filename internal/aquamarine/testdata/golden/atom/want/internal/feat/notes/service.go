@@ -0,0 +1,47 @@
+package notes
+
+import (
+	"context"
+	// aquamarine:user imports
+	// aquamarine:end
+)
+
+// Service exposes the notes use cases.
+type Service interface {
+	NoteRepo
+	ListPinned(ctx context.Context, req ListPinnedRequest) (ListPinnedResponse, error)
+}
+
+// ListPinnedRequest is the input of ListPinned.
+type ListPinnedRequest struct {
+	// aquamarine:user ListPinnedRequest
+	// aquamarine:end
+}
+
+// ListPinnedResponse is the output of ListPinned.
+type ListPinnedResponse struct {
+	// aquamarine:user ListPinnedResponse
+	// aquamarine:end
+}
+
+// service implements Service. The use cases below are stubs to fill in; code
+// between aquamarine:user and aquamarine:end markers survives regeneration,
+// the imports they need included.
+type service struct {
+	NoteRepo
+	deps Deps
+}
+
+func newService(deps Deps) *service {
+	return &service{
+		NoteRepo: deps.NoteRepo,
+		deps:     deps,
+	}
+}
+
+// ListPinned implements Service.
+func (s *service) ListPinned(ctx context.Context, req ListPinnedRequest) (ListPinnedResponse, error) {
+	// aquamarine:user ListPinned
+	return ListPinnedResponse{}, ErrNotImplemented
+	// aquamarine:end
+}
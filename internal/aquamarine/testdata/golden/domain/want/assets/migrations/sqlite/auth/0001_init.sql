@@ -0,0 +1,13 @@
+-- auth: initial schema
+
+CREATE TABLE IF NOT EXISTS users (
+	id TEXT PRIMARY KEY,
+	email TEXT NOT NULL,
+	username TEXT NOT NULL,
+	pass TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS roles (
+	id TEXT PRIMARY KEY,
+	name TEXT NOT NULL UNIQUE
+);
@@ -0,0 +1,11 @@
+module example.com/shop
+
+go 1.22
+
+require (
+	github.com/aquamarinepk/aquamarine v0.1.0
+	github.com/go-chi/chi/v5 v5.1.0
+	github.com/google/uuid v1.6.0
+	gopkg.in/yaml.v3 v3.0.1
+	modernc.org/sqlite v1.33.1
+)
@@ -0,0 +1,16 @@
+package auth
+
+import (
+	"github.com/google/uuid"
+)
+
+// Role is a model of the auth feat.
+type Role struct {
+	ID   uuid.UUID `json:"id" bson:"_id"`
+	Name string    `json:"name" bson:"name"`
+}
+
+// NewRole returns a Role with a fresh ID.
+func NewRole() *Role {
+	return &Role{ID: uuid.New()}
+}
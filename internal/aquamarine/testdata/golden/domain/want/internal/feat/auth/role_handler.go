@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"example.com/shop/internal/platform"
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// RoleHandler serves the Role JSON API.
+type RoleHandler struct {
+	repo      RoleRepo
+	validator *RoleValidator
+	log       am.Logger
+}
+
+// NewRoleHandler creates a RoleHandler backed by repo.
+func NewRoleHandler(repo RoleRepo, xp platform.XParams) *RoleHandler {
+	return &RoleHandler{
+		repo:      repo,
+		validator: NewRoleValidator(),
+		log:       xp.Log,
+	}
+}
+
+// Routes mounts the Role endpoints on r.
+func (h *RoleHandler) Routes(r chi.Router) {
+	r.Get("/", h.List)
+	r.Post("/", h.Create)
+	r.Get("/{id}", h.Get)
+	r.Put("/{id}", h.Update)
+	r.Delete("/{id}", h.Delete)
+}
+
+// List returns all Roles.
+func (h *RoleHandler) List(w http.ResponseWriter, r *http.Request) {
+	items, err := h.repo.List(r.Context())
+	if err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusOK, items, nil)
+}
+
+// Get returns a single Role.
+func (h *RoleHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	m, err := h.repo.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusOK, m, nil)
+}
+
+// Create stores a new Role.
+func (h *RoleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	m := NewRole()
+	if err := json.NewDecoder(r.Body).Decode(m); err != nil {
+		am.Error(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+		return
+	}
+	m.ID = uuid.New()
+	if errs := h.validator.Validate(r.Context(), m); errs.HasErrors() {
+		writeError(w, h.log, errs)
+		return
+	}
+	if err := h.repo.Create(r.Context(), m); err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusCreated, m, nil)
+}
+
+// Update replaces an existing Role.
+func (h *RoleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	var m Role
+	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+		am.Error(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+		return
+	}
+	m.ID = id
+	if errs := h.validator.Validate(r.Context(), &m); errs.HasErrors() {
+		writeError(w, h.log, errs)
+		return
+	}
+	if err := h.repo.Update(r.Context(), &m); err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusOK, m, nil)
+}
+
+// Delete removes a Role.
+func (h *RoleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusNoContent, nil, nil)
+}
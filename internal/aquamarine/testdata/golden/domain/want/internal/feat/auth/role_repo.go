@@ -0,0 +1,17 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// RoleRepo persists Role values.
+// Get, Update and Delete return ErrNotFound when no Role matches.
+type RoleRepo interface {
+	Create(ctx context.Context, m *Role) error
+	Get(ctx context.Context, id uuid.UUID) (*Role, error)
+	List(ctx context.Context) ([]Role, error)
+	Update(ctx context.Context, m *Role) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// RoleSQLiteRepo is a RoleRepo backed by SQLite.
+type RoleSQLiteRepo struct {
+	db *sql.DB
+}
+
+// NewRoleSQLiteRepo creates a RoleSQLiteRepo on db.
+func NewRoleSQLiteRepo(db *sql.DB) *RoleSQLiteRepo {
+	return &RoleSQLiteRepo{db: db}
+}
+
+// Create inserts m.
+func (r *RoleSQLiteRepo) Create(ctx context.Context, m *Role) error {
+	_, err := r.db.ExecContext(ctx, insertRoleSQL, m.ID, m.Name)
+	return err
+}
+
+// Get returns the Role with the given id.
+func (r *RoleSQLiteRepo) Get(ctx context.Context, id uuid.UUID) (*Role, error) {
+	m, err := scanRole(r.db.QueryRowContext(ctx, selectRoleSQL, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return m, err
+}
+
+// List returns every Role.
+func (r *RoleSQLiteRepo) List(ctx context.Context) ([]Role, error) {
+	return queryRoles(ctx, r.db, listRoleSQL)
+}
+
+// Update saves m over the stored Role.
+func (r *RoleSQLiteRepo) Update(ctx context.Context, m *Role) error {
+	res, err := r.db.ExecContext(ctx, updateRoleSQL, m.Name, m.ID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes the Role with the given id.
+func (r *RoleSQLiteRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, deleteRoleSQL, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// queryRoles runs a query selecting Role columns.
+func queryRoles(ctx context.Context, db *sql.DB, query string, args ...any) ([]Role, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Role{}
+	for rows.Next() {
+		m, err := scanRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *m)
+	}
+	return items, rows.Err()
+}
+
+func scanRole(row interface{ Scan(dest ...any) error }) (*Role, error) {
+	var m Role
+	if err := row.Scan(&m.ID, &m.Name); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"context"
+	// aquamarine:user imports
+	// aquamarine:end
+)
+
+// Service exposes the auth use cases.
+type Service interface {
+	Register(ctx context.Context, req RegisterRequest) (RegisterResponse, error)
+	Login(ctx context.Context, req LoginRequest) (LoginResponse, error)
+	AssignRole(ctx context.Context, req AssignRoleRequest) (AssignRoleResponse, error)
+}
+
+// RegisterRequest is the input of Register.
+type RegisterRequest struct {
+	// aquamarine:user RegisterRequest
+	// aquamarine:end
+}
+
+// RegisterResponse is the output of Register.
+type RegisterResponse struct {
+	// aquamarine:user RegisterResponse
+	// aquamarine:end
+}
+
+// LoginRequest is the input of Login.
+type LoginRequest struct {
+	// aquamarine:user LoginRequest
+	// aquamarine:end
+}
+
+// LoginResponse is the output of Login.
+type LoginResponse struct {
+	// aquamarine:user LoginResponse
+	// aquamarine:end
+}
+
+// AssignRoleRequest is the input of AssignRole.
+type AssignRoleRequest struct {
+	// aquamarine:user AssignRoleRequest
+	// aquamarine:end
+}
+
+// AssignRoleResponse is the output of AssignRole.
+type AssignRoleResponse struct {
+	// aquamarine:user AssignRoleResponse
+	// aquamarine:end
+}
+
+// service implements Service. The use cases below are stubs to fill in; code
+// between aquamarine:user and aquamarine:end markers survives regeneration,
+// the imports they need included.
+type service struct {
+	deps Deps
+}
+
+func newService(deps Deps) *service {
+	return &service{
+		deps: deps,
+	}
+}
+
+// Register implements Service.
+func (s *service) Register(ctx context.Context, req RegisterRequest) (RegisterResponse, error) {
+	// aquamarine:user Register
+	return RegisterResponse{}, ErrNotImplemented
+	// aquamarine:end
+}
+
+// Login implements Service.
+func (s *service) Login(ctx context.Context, req LoginRequest) (LoginResponse, error) {
+	// aquamarine:user Login
+	return LoginResponse{}, ErrNotImplemented
+	// aquamarine:end
+}
+
+// AssignRole implements Service.
+func (s *service) AssignRole(ctx context.Context, req AssignRoleRequest) (AssignRoleResponse, error) {
+	// aquamarine:user AssignRole
+	return AssignRoleResponse{}, ErrNotImplemented
+	// aquamarine:end
+}
@@ -0,0 +1,18 @@
+package auth
+
+import (
+	"github.com/google/uuid"
+)
+
+// User is a model of the auth feat.
+type User struct {
+	ID       uuid.UUID `json:"id" bson:"_id"`
+	Email    string    `json:"email" bson:"email"`
+	Username string    `json:"username" bson:"username"`
+	Pass     string    `json:"pass" bson:"pass"`
+}
+
+// NewUser returns a User with a fresh ID.
+func NewUser() *User {
+	return &User{ID: uuid.New()}
+}
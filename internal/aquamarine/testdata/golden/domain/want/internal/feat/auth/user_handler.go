@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"example.com/shop/internal/platform"
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// UserHandler serves the User JSON API.
+type UserHandler struct {
+	repo      UserRepo
+	validator *UserValidator
+	log       am.Logger
+}
+
+// NewUserHandler creates a UserHandler backed by repo.
+func NewUserHandler(repo UserRepo, xp platform.XParams) *UserHandler {
+	return &UserHandler{
+		repo:      repo,
+		validator: NewUserValidator(),
+		log:       xp.Log,
+	}
+}
+
+// Routes mounts the User endpoints on r.
+func (h *UserHandler) Routes(r chi.Router) {
+	r.Get("/", h.List)
+	r.Post("/", h.Create)
+	r.Get("/{id}", h.Get)
+	r.Put("/{id}", h.Update)
+	r.Delete("/{id}", h.Delete)
+}
+
+// List returns all Users.
+func (h *UserHandler) List(w http.ResponseWriter, r *http.Request) {
+	items, err := h.repo.List(r.Context())
+	if err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusOK, items, nil)
+}
+
+// Get returns a single User.
+func (h *UserHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	m, err := h.repo.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusOK, m, nil)
+}
+
+// Create stores a new User.
+func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
+	m := NewUser()
+	if err := json.NewDecoder(r.Body).Decode(m); err != nil {
+		am.Error(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+		return
+	}
+	m.ID = uuid.New()
+	if errs := h.validator.Validate(r.Context(), m); errs.HasErrors() {
+		writeError(w, h.log, errs)
+		return
+	}
+	if err := h.repo.Create(r.Context(), m); err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusCreated, m, nil)
+}
+
+// Update replaces an existing User.
+func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	var m User
+	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+		am.Error(w, http.StatusBadRequest, "invalid_body", "Invalid request body")
+		return
+	}
+	m.ID = id
+	if errs := h.validator.Validate(r.Context(), &m); errs.HasErrors() {
+		writeError(w, h.log, errs)
+		return
+	}
+	if err := h.repo.Update(r.Context(), &m); err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusOK, m, nil)
+}
+
+// Delete removes a User.
+func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		writeError(w, h.log, err)
+		return
+	}
+	am.Respond(w, http.StatusNoContent, nil, nil)
+}
@@ -0,0 +1,10 @@
+package auth
+
+// SQL statements used by UserSQLiteRepo.
+const (
+	insertUserSQL = `INSERT INTO users (id, email, username, pass) VALUES (?, ?, ?, ?)`
+	selectUserSQL = `SELECT id, email, username, pass FROM users WHERE id = ?`
+	listUserSQL   = `SELECT id, email, username, pass FROM users ORDER BY id`
+	updateUserSQL = `UPDATE users SET email = ?, username = ?, pass = ? WHERE id = ?`
+	deleteUserSQL = `DELETE FROM users WHERE id = ?`
+)
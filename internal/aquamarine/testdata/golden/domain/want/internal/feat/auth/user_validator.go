@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+var userUsernamePattern = regexp.MustCompile("^[a-z0-9_]+$")
+
+// UserValidator checks User values before they are stored.
+type UserValidator struct{}
+
+// NewUserValidator creates a UserValidator.
+func NewUserValidator() *UserValidator {
+	return &UserValidator{}
+}
+
+// Validate returns every rule m breaks.
+func (v *UserValidator) Validate(ctx context.Context, m *User) am.ValidationErrors {
+	var errs am.ValidationErrors
+	if !(am.IsRequired(m.Email)) {
+		errs = append(errs, am.ValidationError{Field: "email", Code: "required", Message: "email is required"})
+	}
+	if !(am.IsEmail(m.Email)) {
+		errs = append(errs, am.ValidationError{Field: "email", Code: "email", Message: "email must be a valid email address"})
+	}
+	if !(am.IsRequired(m.Username)) {
+		errs = append(errs, am.ValidationError{Field: "username", Code: "required", Message: "username is required"})
+	}
+	if !(userUsernamePattern.MatchString(m.Username)) {
+		errs = append(errs, am.ValidationError{Field: "username", Code: "pattern", Message: "username has an invalid format"})
+	}
+	if !(am.IsRequired(m.Pass)) {
+		errs = append(errs, am.ValidationError{Field: "pass", Code: "required", Message: "pass is required"})
+	}
+	if !(am.MinLength(m.Pass, 8)) {
+		errs = append(errs, am.ValidationError{Field: "pass", Code: "min", Message: "pass must be at least 8 characters"})
+	}
+	return errs
+}
@@ -0,0 +1,26 @@
+-- catalog: initial schema
+
+CREATE TABLE IF NOT EXISTS items (
+	id TEXT PRIMARY KEY,
+	type TEXT NOT NULL CHECK (type IN ('book', 'disc')),
+	"range" INTEGER NOT NULL,
+	"select" INTEGER NOT NULL,
+	"default" TEXT,
+	package_id TEXT REFERENCES shelves (id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS shelves (
+	id TEXT PRIMARY KEY,
+	label TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS tags (
+	id TEXT PRIMARY KEY,
+	name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS item_import (
+	item_id TEXT NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+	import_id TEXT NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+	PRIMARY KEY (item_id, import_id)
+);
@@ -0,0 +1,97 @@
+package catalog
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"example.com/catalog/internal/platform"
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// Errors returned by the catalog feat.
+var (
+	ErrNotFound       = errors.New("catalog: not found")
+	ErrNotImplemented = errors.New("catalog: not implemented")
+)
+
+// Deps lists what the catalog feat needs from the rest of the app.
+type Deps struct {
+	ItemRepo  ItemRepo
+	ShelfRepo ShelfRepo
+	TagRepo   TagRepo
+}
+
+// Feature wires the catalog handlers. It is registered through am.Setup.
+type Feature struct {
+	xp           platform.XParams
+	deps         Deps
+	svc          Service
+	itemHandler  *ItemHandler
+	shelfHandler *ShelfHandler
+	tagHandler   *TagHandler
+}
+
+// New builds the catalog feat from its dependencies.
+func New(xp platform.XParams, deps Deps) (*Feature, error) {
+	f := &Feature{xp: xp, deps: deps}
+	f.svc = newService(deps)
+	f.itemHandler = NewItemHandler(deps.ItemRepo, xp)
+	f.shelfHandler = NewShelfHandler(deps.ShelfRepo, xp)
+	f.tagHandler = NewTagHandler(deps.TagRepo, xp)
+	return f, nil
+}
+
+// Service returns the catalog use cases for other feats to consume.
+func (f *Feature) Service() Service {
+	return f.svc
+}
+
+// RegisterAPIRoutes mounts the catalog JSON endpoints.
+func (f *Feature) RegisterAPIRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Route("/catalog/items", f.itemHandler.Routes)
+		r.Route("/catalog/shelves", f.shelfHandler.Routes)
+		r.Route("/catalog/tags", f.tagHandler.Routes)
+		r.Get("/items/by-type/{type}", f.handleListByType)
+	})
+}
+
+// handleListByType serves ListByType over JSON.
+func (f *Feature) handleListByType(w http.ResponseWriter, r *http.Request) {
+	var req ListByTypeRequest
+	res, err := f.svc.ListByType(r.Context(), req)
+	if err != nil {
+		writeError(w, f.xp.Log, err)
+		return
+	}
+	am.Respond(w, http.StatusOK, res, nil)
+}
+
+// writeError maps feat errors to JSON error responses.
+func writeError(w http.ResponseWriter, log am.Logger, err error) {
+	var verrs am.ValidationErrors
+	switch {
+	case errors.As(err, &verrs):
+		am.Error(w, http.StatusUnprocessableEntity, "validation_failed", "Validation failed", verrs...)
+	case errors.Is(err, ErrNotFound):
+		am.Error(w, http.StatusNotFound, "not_found", "Resource not found")
+	case errors.Is(err, ErrNotImplemented):
+		am.Error(w, http.StatusNotImplemented, "not_implemented", "Not implemented")
+	default:
+		log.Error("request failed: ", err)
+		am.Error(w, http.StatusInternalServerError, "internal_error", "Internal error")
+	}
+}
+
+// parseID reads the {id} URL parameter.
+func parseID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		am.Error(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,62 @@
+package catalog
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Item is a model of the catalog feat.
+type Item struct {
+	ID        uuid.UUID  `json:"id" bson:"_id"`
+	Type      ItemType   `json:"type" bson:"type"`
+	Range     int        `json:"range" bson:"range"`
+	Select    bool       `json:"select" bson:"select"`
+	Default   *string    `json:"default" bson:"default"`
+	PackageID *uuid.UUID `json:"package_id" bson:"package_id"`
+}
+
+// NewItem returns a Item with a fresh ID.
+func NewItem() *Item {
+	return &Item{ID: uuid.New()}
+}
+
+// ItemType enumerates the values of Item.type.
+type ItemType string
+
+// ItemType values.
+const (
+	ItemTypeBook ItemType = "book"
+	ItemTypeDisc ItemType = "disc"
+)
+
+// Valid reports whether s is one of the ItemType values.
+func (s ItemType) Valid() bool {
+	switch s {
+	case ItemTypeBook, ItemTypeDisc:
+		return true
+	}
+	return false
+}
+
+// Scan implements sql.Scanner, rejecting values outside ItemType.
+func (s *ItemType) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		*s = ItemType(v)
+	case []byte:
+		*s = ItemType(v)
+	default:
+		return fmt.Errorf("cannot scan %T into ItemType", src)
+	}
+	if !s.Valid() {
+		return fmt.Errorf("invalid ItemType %q", string(*s))
+	}
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (s ItemType) Value() (driver.Value, error) {
+	return string(s), nil
+}
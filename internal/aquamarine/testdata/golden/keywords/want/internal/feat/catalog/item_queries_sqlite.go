@@ -0,0 +1,14 @@
+package catalog
+
+// SQL statements used by ItemSQLiteRepo.
+const (
+	insertItemSQL          = `INSERT INTO items (id, type, "range", "select", "default", package_id) VALUES (?, ?, ?, ?, ?, ?)`
+	selectItemSQL          = `SELECT id, type, "range", "select", "default", package_id FROM items WHERE id = ?`
+	listItemSQL            = `SELECT id, type, "range", "select", "default", package_id FROM items ORDER BY id`
+	updateItemSQL          = `UPDATE items SET type = ?, "range" = ?, "select" = ?, "default" = ?, package_id = ? WHERE id = ?`
+	deleteItemSQL          = `DELETE FROM items WHERE id = ?`
+	listItemsForPackageSQL = `SELECT id, type, "range", "select", "default", package_id FROM items WHERE package_id = ? ORDER BY id`
+	listImportForItemSQL   = `SELECT tags.id, tags.name FROM tags JOIN item_import ON item_import.import_id = tags.id WHERE item_import.item_id = ? ORDER BY tags.id`
+	itemAssignImportSQL    = `INSERT OR IGNORE INTO item_import (item_id, import_id) VALUES (?, ?)`
+	itemUnassignImportSQL  = `DELETE FROM item_import WHERE item_id = ? AND import_id = ?`
+)
@@ -0,0 +1,21 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ItemRepo persists Item values.
+// Get, Update and Delete return ErrNotFound when no Item matches.
+type ItemRepo interface {
+	Create(ctx context.Context, m *Item) error
+	Get(ctx context.Context, id uuid.UUID) (*Item, error)
+	List(ctx context.Context) ([]Item, error)
+	Update(ctx context.Context, m *Item) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListItemsForPackage(ctx context.Context, id uuid.UUID) ([]Item, error)
+	ListImportForItem(ctx context.Context, id uuid.UUID) ([]Tag, error)
+	AssignImport(ctx context.Context, id, tagID uuid.UUID) error
+	UnassignImport(ctx context.Context, id, tagID uuid.UUID) error
+}
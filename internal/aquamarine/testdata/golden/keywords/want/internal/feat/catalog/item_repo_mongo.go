@@ -0,0 +1,139 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ItemMongoRepo is a ItemRepo backed by MongoDB.
+type ItemMongoRepo struct {
+	coll *mongo.Collection
+}
+
+// NewItemMongoRepo creates a ItemMongoRepo on db.
+func NewItemMongoRepo(db *mongo.Database) *ItemMongoRepo {
+	return &ItemMongoRepo{coll: db.Collection("items")}
+}
+
+// Create inserts m.
+func (r *ItemMongoRepo) Create(ctx context.Context, m *Item) error {
+	_, err := r.coll.InsertOne(ctx, m)
+	return err
+}
+
+// Get returns the Item with the given id.
+func (r *ItemMongoRepo) Get(ctx context.Context, id uuid.UUID) (*Item, error) {
+	var m Item
+	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&m)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// List returns every Item.
+func (r *ItemMongoRepo) List(ctx context.Context) ([]Item, error) {
+	cur, err := r.coll.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	items := []Item{}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Update saves m over the stored Item, keeping its assigned ids.
+func (r *ItemMongoRepo) Update(ctx context.Context, m *Item) error {
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": m.ID}, bson.M{"$set": m})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes the Item with the given id.
+func (r *ItemMongoRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListItemsForPackage returns the Items whose package_id is id.
+func (r *ItemMongoRepo) ListItemsForPackage(ctx context.Context, id uuid.UUID) ([]Item, error) {
+	cur, err := r.coll.Find(ctx, bson.M{"package_id": id})
+	if err != nil {
+		return nil, err
+	}
+	items := []Item{}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListImportForItem returns the Tags assigned to the Item id.
+// Their ids are kept in the import_ids array of its document.
+func (r *ItemMongoRepo) ListImportForItem(ctx context.Context, id uuid.UUID) ([]Tag, error) {
+	var doc struct {
+		IDs []uuid.UUID `bson:"import_ids"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"import_ids": 1})
+	err := r.coll.FindOne(ctx, bson.M{"_id": id}, opts).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	items := []Tag{}
+	if len(doc.IDs) == 0 {
+		return items, nil
+	}
+	cur, err := r.coll.Database().Collection("tags").Find(ctx, bson.M{"_id": bson.M{"$in": doc.IDs}})
+	if err != nil {
+		return nil, err
+	}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// AssignImport links the Tag tagID to the Item id; assigning it twice is a no-op.
+func (r *ItemMongoRepo) AssignImport(ctx context.Context, id, tagID uuid.UUID) error {
+	return r.updateRefs(ctx, id, bson.M{"$addToSet": bson.M{"import_ids": tagID}})
+}
+
+// UnassignImport unlinks the Tag tagID from the Item id.
+func (r *ItemMongoRepo) UnassignImport(ctx context.Context, id, tagID uuid.UUID) error {
+	return r.updateRefs(ctx, id, bson.M{"$pull": bson.M{"import_ids": tagID}})
+}
+
+func (r *ItemMongoRepo) updateRefs(ctx context.Context, id uuid.UUID, update bson.M) error {
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ItemSQLiteRepo is a ItemRepo backed by SQLite.
+type ItemSQLiteRepo struct {
+	db *sql.DB
+}
+
+// NewItemSQLiteRepo creates a ItemSQLiteRepo on db.
+func NewItemSQLiteRepo(db *sql.DB) *ItemSQLiteRepo {
+	return &ItemSQLiteRepo{db: db}
+}
+
+// Create inserts m.
+func (r *ItemSQLiteRepo) Create(ctx context.Context, m *Item) error {
+	_, err := r.db.ExecContext(ctx, insertItemSQL, m.ID, m.Type, m.Range, m.Select, m.Default, m.PackageID)
+	return err
+}
+
+// Get returns the Item with the given id.
+func (r *ItemSQLiteRepo) Get(ctx context.Context, id uuid.UUID) (*Item, error) {
+	m, err := scanItem(r.db.QueryRowContext(ctx, selectItemSQL, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return m, err
+}
+
+// List returns every Item.
+func (r *ItemSQLiteRepo) List(ctx context.Context) ([]Item, error) {
+	return queryItems(ctx, r.db, listItemSQL)
+}
+
+// Update saves m over the stored Item.
+func (r *ItemSQLiteRepo) Update(ctx context.Context, m *Item) error {
+	res, err := r.db.ExecContext(ctx, updateItemSQL, m.Type, m.Range, m.Select, m.Default, m.PackageID, m.ID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes the Item with the given id.
+func (r *ItemSQLiteRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, deleteItemSQL, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListItemsForPackage returns the Items whose package_id is id.
+func (r *ItemSQLiteRepo) ListItemsForPackage(ctx context.Context, id uuid.UUID) ([]Item, error) {
+	return queryItems(ctx, r.db, listItemsForPackageSQL, id)
+}
+
+// ListImportForItem returns the Tags assigned to the Item id.
+func (r *ItemSQLiteRepo) ListImportForItem(ctx context.Context, id uuid.UUID) ([]Tag, error) {
+	return queryTags(ctx, r.db, listImportForItemSQL, id)
+}
+
+// AssignImport links the Tag tagID to the Item id; assigning it twice is a no-op.
+func (r *ItemSQLiteRepo) AssignImport(ctx context.Context, id, tagID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, itemAssignImportSQL, id, tagID)
+	return err
+}
+
+// UnassignImport unlinks the Tag tagID from the Item id.
+func (r *ItemSQLiteRepo) UnassignImport(ctx context.Context, id, tagID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, itemUnassignImportSQL, id, tagID)
+	return err
+}
+
+// queryItems runs a query selecting Item columns.
+func queryItems(ctx context.Context, db *sql.DB, query string, args ...any) ([]Item, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Item{}
+	for rows.Next() {
+		m, err := scanItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *m)
+	}
+	return items, rows.Err()
+}
+
+func scanItem(row interface{ Scan(dest ...any) error }) (*Item, error) {
+	var m Item
+	if err := row.Scan(&m.ID, &m.Type, &m.Range, &m.Select, &m.Default, &m.PackageID); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
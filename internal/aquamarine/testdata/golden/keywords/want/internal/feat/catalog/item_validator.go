@@ -0,0 +1,30 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// ItemValidator checks Item values before they are stored.
+type ItemValidator struct{}
+
+// NewItemValidator creates a ItemValidator.
+func NewItemValidator() *ItemValidator {
+	return &ItemValidator{}
+}
+
+// Validate returns every rule m breaks.
+func (v *ItemValidator) Validate(ctx context.Context, m *Item) am.ValidationErrors {
+	var errs am.ValidationErrors
+	if !(m.Type != "") {
+		errs = append(errs, am.ValidationError{Field: "type", Code: "required", Message: "type is required"})
+	}
+	if !(m.Type.Valid()) {
+		errs = append(errs, am.ValidationError{Field: "type", Code: "oneof", Message: "type must be one of book, disc"})
+	}
+	if !(m.Range >= 1) {
+		errs = append(errs, am.ValidationError{Field: "range", Code: "min", Message: "range must be at least 1"})
+	}
+	return errs
+}
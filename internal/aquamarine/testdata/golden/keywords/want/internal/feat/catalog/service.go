@@ -0,0 +1,44 @@
+package catalog
+
+import (
+	"context"
+	// aquamarine:user imports
+	// aquamarine:end
+)
+
+// Service exposes the catalog use cases.
+type Service interface {
+	ListByType(ctx context.Context, req ListByTypeRequest) (ListByTypeResponse, error)
+}
+
+// ListByTypeRequest is the input of ListByType.
+type ListByTypeRequest struct {
+	// aquamarine:user ListByTypeRequest
+	// aquamarine:end
+}
+
+// ListByTypeResponse is the output of ListByType.
+type ListByTypeResponse struct {
+	// aquamarine:user ListByTypeResponse
+	// aquamarine:end
+}
+
+// service implements Service. The use cases below are stubs to fill in; code
+// between aquamarine:user and aquamarine:end markers survives regeneration,
+// the imports they need included.
+type service struct {
+	deps Deps
+}
+
+func newService(deps Deps) *service {
+	return &service{
+		deps: deps,
+	}
+}
+
+// ListByType implements Service.
+func (s *service) ListByType(ctx context.Context, req ListByTypeRequest) (ListByTypeResponse, error) {
+	// aquamarine:user ListByType
+	return ListByTypeResponse{}, ErrNotImplemented
+	// aquamarine:end
+}
@@ -0,0 +1,16 @@
+package catalog
+
+import (
+	"github.com/google/uuid"
+)
+
+// Shelf is a model of the catalog feat.
+type Shelf struct {
+	ID    uuid.UUID `json:"id" bson:"_id"`
+	Label string    `json:"label" bson:"label"`
+}
+
+// NewShelf returns a Shelf with a fresh ID.
+func NewShelf() *Shelf {
+	return &Shelf{ID: uuid.New()}
+}
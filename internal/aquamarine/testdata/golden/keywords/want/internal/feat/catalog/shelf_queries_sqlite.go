@@ -0,0 +1,10 @@
+package catalog
+
+// SQL statements used by ShelfSQLiteRepo.
+const (
+	insertShelfSQL = `INSERT INTO shelves (id, label) VALUES (?, ?)`
+	selectShelfSQL = `SELECT id, label FROM shelves WHERE id = ?`
+	listShelfSQL   = `SELECT id, label FROM shelves ORDER BY id`
+	updateShelfSQL = `UPDATE shelves SET label = ? WHERE id = ?`
+	deleteShelfSQL = `DELETE FROM shelves WHERE id = ?`
+)
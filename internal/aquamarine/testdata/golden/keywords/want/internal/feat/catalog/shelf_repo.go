@@ -0,0 +1,17 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ShelfRepo persists Shelf values.
+// Get, Update and Delete return ErrNotFound when no Shelf matches.
+type ShelfRepo interface {
+	Create(ctx context.Context, m *Shelf) error
+	Get(ctx context.Context, id uuid.UUID) (*Shelf, error)
+	List(ctx context.Context) ([]Shelf, error)
+	Update(ctx context.Context, m *Shelf) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,76 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ShelfMongoRepo is a ShelfRepo backed by MongoDB.
+type ShelfMongoRepo struct {
+	coll *mongo.Collection
+}
+
+// NewShelfMongoRepo creates a ShelfMongoRepo on db.
+func NewShelfMongoRepo(db *mongo.Database) *ShelfMongoRepo {
+	return &ShelfMongoRepo{coll: db.Collection("shelves")}
+}
+
+// Create inserts m.
+func (r *ShelfMongoRepo) Create(ctx context.Context, m *Shelf) error {
+	_, err := r.coll.InsertOne(ctx, m)
+	return err
+}
+
+// Get returns the Shelf with the given id.
+func (r *ShelfMongoRepo) Get(ctx context.Context, id uuid.UUID) (*Shelf, error) {
+	var m Shelf
+	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&m)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// List returns every Shelf.
+func (r *ShelfMongoRepo) List(ctx context.Context) ([]Shelf, error) {
+	cur, err := r.coll.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	items := []Shelf{}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Update saves m over the stored Shelf.
+func (r *ShelfMongoRepo) Update(ctx context.Context, m *Shelf) error {
+	res, err := r.coll.ReplaceOne(ctx, bson.M{"_id": m.ID}, m)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes the Shelf with the given id.
+func (r *ShelfMongoRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ShelfSQLiteRepo is a ShelfRepo backed by SQLite.
+type ShelfSQLiteRepo struct {
+	db *sql.DB
+}
+
+// NewShelfSQLiteRepo creates a ShelfSQLiteRepo on db.
+func NewShelfSQLiteRepo(db *sql.DB) *ShelfSQLiteRepo {
+	return &ShelfSQLiteRepo{db: db}
+}
+
+// Create inserts m.
+func (r *ShelfSQLiteRepo) Create(ctx context.Context, m *Shelf) error {
+	_, err := r.db.ExecContext(ctx, insertShelfSQL, m.ID, m.Label)
+	return err
+}
+
+// Get returns the Shelf with the given id.
+func (r *ShelfSQLiteRepo) Get(ctx context.Context, id uuid.UUID) (*Shelf, error) {
+	m, err := scanShelf(r.db.QueryRowContext(ctx, selectShelfSQL, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return m, err
+}
+
+// List returns every Shelf.
+func (r *ShelfSQLiteRepo) List(ctx context.Context) ([]Shelf, error) {
+	return queryShelves(ctx, r.db, listShelfSQL)
+}
+
+// Update saves m over the stored Shelf.
+func (r *ShelfSQLiteRepo) Update(ctx context.Context, m *Shelf) error {
+	res, err := r.db.ExecContext(ctx, updateShelfSQL, m.Label, m.ID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes the Shelf with the given id.
+func (r *ShelfSQLiteRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, deleteShelfSQL, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// queryShelves runs a query selecting Shelf columns.
+func queryShelves(ctx context.Context, db *sql.DB, query string, args ...any) ([]Shelf, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Shelf{}
+	for rows.Next() {
+		m, err := scanShelf(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *m)
+	}
+	return items, rows.Err()
+}
+
+func scanShelf(row interface{ Scan(dest ...any) error }) (*Shelf, error) {
+	var m Shelf
+	if err := row.Scan(&m.ID, &m.Label); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
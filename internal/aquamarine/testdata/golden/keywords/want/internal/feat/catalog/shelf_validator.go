@@ -0,0 +1,24 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// ShelfValidator checks Shelf values before they are stored.
+type ShelfValidator struct{}
+
+// NewShelfValidator creates a ShelfValidator.
+func NewShelfValidator() *ShelfValidator {
+	return &ShelfValidator{}
+}
+
+// Validate returns every rule m breaks.
+func (v *ShelfValidator) Validate(ctx context.Context, m *Shelf) am.ValidationErrors {
+	var errs am.ValidationErrors
+	if !(am.IsRequired(m.Label)) {
+		errs = append(errs, am.ValidationError{Field: "label", Code: "required", Message: "label is required"})
+	}
+	return errs
+}
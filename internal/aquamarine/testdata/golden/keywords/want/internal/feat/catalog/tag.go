@@ -0,0 +1,16 @@
+package catalog
+
+import (
+	"github.com/google/uuid"
+)
+
+// Tag is a model of the catalog feat.
+type Tag struct {
+	ID   uuid.UUID `json:"id" bson:"_id"`
+	Name string    `json:"name" bson:"name"`
+}
+
+// NewTag returns a Tag with a fresh ID.
+func NewTag() *Tag {
+	return &Tag{ID: uuid.New()}
+}
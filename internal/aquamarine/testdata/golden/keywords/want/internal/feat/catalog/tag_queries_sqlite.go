@@ -0,0 +1,10 @@
+package catalog
+
+// SQL statements used by TagSQLiteRepo.
+const (
+	insertTagSQL = `INSERT INTO tags (id, name) VALUES (?, ?)`
+	selectTagSQL = `SELECT id, name FROM tags WHERE id = ?`
+	listTagSQL   = `SELECT id, name FROM tags ORDER BY id`
+	updateTagSQL = `UPDATE tags SET name = ? WHERE id = ?`
+	deleteTagSQL = `DELETE FROM tags WHERE id = ?`
+)
@@ -0,0 +1,17 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// TagRepo persists Tag values.
+// Get, Update and Delete return ErrNotFound when no Tag matches.
+type TagRepo interface {
+	Create(ctx context.Context, m *Tag) error
+	Get(ctx context.Context, id uuid.UUID) (*Tag, error)
+	List(ctx context.Context) ([]Tag, error)
+	Update(ctx context.Context, m *Tag) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
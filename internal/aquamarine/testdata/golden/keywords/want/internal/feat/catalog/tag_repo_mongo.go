@@ -0,0 +1,76 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TagMongoRepo is a TagRepo backed by MongoDB.
+type TagMongoRepo struct {
+	coll *mongo.Collection
+}
+
+// NewTagMongoRepo creates a TagMongoRepo on db.
+func NewTagMongoRepo(db *mongo.Database) *TagMongoRepo {
+	return &TagMongoRepo{coll: db.Collection("tags")}
+}
+
+// Create inserts m.
+func (r *TagMongoRepo) Create(ctx context.Context, m *Tag) error {
+	_, err := r.coll.InsertOne(ctx, m)
+	return err
+}
+
+// Get returns the Tag with the given id.
+func (r *TagMongoRepo) Get(ctx context.Context, id uuid.UUID) (*Tag, error) {
+	var m Tag
+	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&m)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// List returns every Tag.
+func (r *TagMongoRepo) List(ctx context.Context) ([]Tag, error) {
+	cur, err := r.coll.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	items := []Tag{}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Update saves m over the stored Tag.
+func (r *TagMongoRepo) Update(ctx context.Context, m *Tag) error {
+	res, err := r.coll.ReplaceOne(ctx, bson.M{"_id": m.ID}, m)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes the Tag with the given id.
+func (r *TagMongoRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
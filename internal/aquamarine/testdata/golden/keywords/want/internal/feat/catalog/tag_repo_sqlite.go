@@ -0,0 +1,98 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// TagSQLiteRepo is a TagRepo backed by SQLite.
+type TagSQLiteRepo struct {
+	db *sql.DB
+}
+
+// NewTagSQLiteRepo creates a TagSQLiteRepo on db.
+func NewTagSQLiteRepo(db *sql.DB) *TagSQLiteRepo {
+	return &TagSQLiteRepo{db: db}
+}
+
+// Create inserts m.
+func (r *TagSQLiteRepo) Create(ctx context.Context, m *Tag) error {
+	_, err := r.db.ExecContext(ctx, insertTagSQL, m.ID, m.Name)
+	return err
+}
+
+// Get returns the Tag with the given id.
+func (r *TagSQLiteRepo) Get(ctx context.Context, id uuid.UUID) (*Tag, error) {
+	m, err := scanTag(r.db.QueryRowContext(ctx, selectTagSQL, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return m, err
+}
+
+// List returns every Tag.
+func (r *TagSQLiteRepo) List(ctx context.Context) ([]Tag, error) {
+	return queryTags(ctx, r.db, listTagSQL)
+}
+
+// Update saves m over the stored Tag.
+func (r *TagSQLiteRepo) Update(ctx context.Context, m *Tag) error {
+	res, err := r.db.ExecContext(ctx, updateTagSQL, m.Name, m.ID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes the Tag with the given id.
+func (r *TagSQLiteRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, deleteTagSQL, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// queryTags runs a query selecting Tag columns.
+func queryTags(ctx context.Context, db *sql.DB, query string, args ...any) ([]Tag, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Tag{}
+	for rows.Next() {
+		m, err := scanTag(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *m)
+	}
+	return items, rows.Err()
+}
+
+func scanTag(row interface{ Scan(dest ...any) error }) (*Tag, error) {
+	var m Tag
+	if err := row.Scan(&m.ID, &m.Name); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
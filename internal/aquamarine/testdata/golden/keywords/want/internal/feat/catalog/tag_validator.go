@@ -0,0 +1,24 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// TagValidator checks Tag values before they are stored.
+type TagValidator struct{}
+
+// NewTagValidator creates a TagValidator.
+func NewTagValidator() *TagValidator {
+	return &TagValidator{}
+}
+
+// Validate returns every rule m breaks.
+func (v *TagValidator) Validate(ctx context.Context, m *Tag) am.ValidationErrors {
+	var errs am.ValidationErrors
+	if !(am.IsRequired(m.Name)) {
+		errs = append(errs, am.ValidationError{Field: "name", Code: "required", Message: "name is required"})
+	}
+	return errs
+}
@@ -0,0 +1,13 @@
+-- catalog: initial schema
+
+CREATE TABLE IF NOT EXISTS products (
+	id TEXT PRIMARY KEY,
+	name TEXT NOT NULL,
+	price REAL NOT NULL,
+	stock INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS categories (
+	id TEXT PRIMARY KEY,
+	title TEXT NOT NULL UNIQUE
+);
@@ -0,0 +1,16 @@
+package catalog
+
+import (
+	"github.com/google/uuid"
+)
+
+// Category is a model of the catalog feat.
+type Category struct {
+	ID    uuid.UUID `json:"id" bson:"_id"`
+	Title string    `json:"title" bson:"title"`
+}
+
+// NewCategory returns a Category with a fresh ID.
+func NewCategory() *Category {
+	return &Category{ID: uuid.New()}
+}
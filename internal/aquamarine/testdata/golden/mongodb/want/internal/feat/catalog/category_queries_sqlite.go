@@ -0,0 +1,10 @@
+package catalog
+
+// SQL statements used by CategorySQLiteRepo.
+const (
+	insertCategorySQL = `INSERT INTO categories (id, title) VALUES (?, ?)`
+	selectCategorySQL = `SELECT id, title FROM categories WHERE id = ?`
+	listCategorySQL   = `SELECT id, title FROM categories ORDER BY id`
+	updateCategorySQL = `UPDATE categories SET title = ? WHERE id = ?`
+	deleteCategorySQL = `DELETE FROM categories WHERE id = ?`
+)
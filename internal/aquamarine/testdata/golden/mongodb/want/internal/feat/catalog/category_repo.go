@@ -0,0 +1,17 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// CategoryRepo persists Category values.
+// Get, Update and Delete return ErrNotFound when no Category matches.
+type CategoryRepo interface {
+	Create(ctx context.Context, m *Category) error
+	Get(ctx context.Context, id uuid.UUID) (*Category, error)
+	List(ctx context.Context) ([]Category, error)
+	Update(ctx context.Context, m *Category) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,76 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// CategoryMongoRepo is a CategoryRepo backed by MongoDB.
+type CategoryMongoRepo struct {
+	coll *mongo.Collection
+}
+
+// NewCategoryMongoRepo creates a CategoryMongoRepo on db.
+func NewCategoryMongoRepo(db *mongo.Database) *CategoryMongoRepo {
+	return &CategoryMongoRepo{coll: db.Collection("categories")}
+}
+
+// Create inserts m.
+func (r *CategoryMongoRepo) Create(ctx context.Context, m *Category) error {
+	_, err := r.coll.InsertOne(ctx, m)
+	return err
+}
+
+// Get returns the Category with the given id.
+func (r *CategoryMongoRepo) Get(ctx context.Context, id uuid.UUID) (*Category, error) {
+	var m Category
+	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&m)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// List returns every Category.
+func (r *CategoryMongoRepo) List(ctx context.Context) ([]Category, error) {
+	cur, err := r.coll.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	items := []Category{}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Update saves m over the stored Category.
+func (r *CategoryMongoRepo) Update(ctx context.Context, m *Category) error {
+	res, err := r.coll.ReplaceOne(ctx, bson.M{"_id": m.ID}, m)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes the Category with the given id.
+func (r *CategoryMongoRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// CategorySQLiteRepo is a CategoryRepo backed by SQLite.
+type CategorySQLiteRepo struct {
+	db *sql.DB
+}
+
+// NewCategorySQLiteRepo creates a CategorySQLiteRepo on db.
+func NewCategorySQLiteRepo(db *sql.DB) *CategorySQLiteRepo {
+	return &CategorySQLiteRepo{db: db}
+}
+
+// Create inserts m.
+func (r *CategorySQLiteRepo) Create(ctx context.Context, m *Category) error {
+	_, err := r.db.ExecContext(ctx, insertCategorySQL, m.ID, m.Title)
+	return err
+}
+
+// Get returns the Category with the given id.
+func (r *CategorySQLiteRepo) Get(ctx context.Context, id uuid.UUID) (*Category, error) {
+	m, err := scanCategory(r.db.QueryRowContext(ctx, selectCategorySQL, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return m, err
+}
+
+// List returns every Category.
+func (r *CategorySQLiteRepo) List(ctx context.Context) ([]Category, error) {
+	return queryCategories(ctx, r.db, listCategorySQL)
+}
+
+// Update saves m over the stored Category.
+func (r *CategorySQLiteRepo) Update(ctx context.Context, m *Category) error {
+	res, err := r.db.ExecContext(ctx, updateCategorySQL, m.Title, m.ID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes the Category with the given id.
+func (r *CategorySQLiteRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, deleteCategorySQL, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// queryCategories runs a query selecting Category columns.
+func queryCategories(ctx context.Context, db *sql.DB, query string, args ...any) ([]Category, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Category{}
+	for rows.Next() {
+		m, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *m)
+	}
+	return items, rows.Err()
+}
+
+func scanCategory(row interface{ Scan(dest ...any) error }) (*Category, error) {
+	var m Category
+	if err := row.Scan(&m.ID, &m.Title); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
@@ -0,0 +1,24 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// CategoryValidator checks Category values before they are stored.
+type CategoryValidator struct{}
+
+// NewCategoryValidator creates a CategoryValidator.
+func NewCategoryValidator() *CategoryValidator {
+	return &CategoryValidator{}
+}
+
+// Validate returns every rule m breaks.
+func (v *CategoryValidator) Validate(ctx context.Context, m *Category) am.ValidationErrors {
+	var errs am.ValidationErrors
+	if !(am.IsRequired(m.Title)) {
+		errs = append(errs, am.ValidationError{Field: "title", Code: "required", Message: "title is required"})
+	}
+	return errs
+}
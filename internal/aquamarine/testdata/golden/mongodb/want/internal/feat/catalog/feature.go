@@ -0,0 +1,93 @@
+package catalog
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"example.com/catalog/internal/platform"
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// Errors returned by the catalog feat.
+var (
+	ErrNotFound       = errors.New("catalog: not found")
+	ErrNotImplemented = errors.New("catalog: not implemented")
+)
+
+// Deps lists what the catalog feat needs from the rest of the app.
+type Deps struct {
+	ProductRepo  ProductRepo
+	CategoryRepo CategoryRepo
+}
+
+// Feature wires the catalog handlers. It is registered through am.Setup.
+type Feature struct {
+	xp              platform.XParams
+	deps            Deps
+	svc             Service
+	productHandler  *ProductHandler
+	categoryHandler *CategoryHandler
+}
+
+// New builds the catalog feat from its dependencies.
+func New(xp platform.XParams, deps Deps) (*Feature, error) {
+	f := &Feature{xp: xp, deps: deps}
+	f.svc = newService(deps)
+	f.productHandler = NewProductHandler(deps.ProductRepo, xp)
+	f.categoryHandler = NewCategoryHandler(deps.CategoryRepo, xp)
+	return f, nil
+}
+
+// Service returns the catalog use cases for other feats to consume.
+func (f *Feature) Service() Service {
+	return f.svc
+}
+
+// RegisterAPIRoutes mounts the catalog JSON endpoints.
+func (f *Feature) RegisterAPIRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Route("/catalog/products", f.productHandler.Routes)
+		r.Route("/catalog/categories", f.categoryHandler.Routes)
+		r.Get("/featured", f.handleFeatured)
+	})
+}
+
+// handleFeatured serves Featured over JSON.
+func (f *Feature) handleFeatured(w http.ResponseWriter, r *http.Request) {
+	var req FeaturedRequest
+	res, err := f.svc.Featured(r.Context(), req)
+	if err != nil {
+		writeError(w, f.xp.Log, err)
+		return
+	}
+	am.Respond(w, http.StatusOK, res, nil)
+}
+
+// writeError maps feat errors to JSON error responses.
+func writeError(w http.ResponseWriter, log am.Logger, err error) {
+	var verrs am.ValidationErrors
+	switch {
+	case errors.As(err, &verrs):
+		am.Error(w, http.StatusUnprocessableEntity, "validation_failed", "Validation failed", verrs...)
+	case errors.Is(err, ErrNotFound):
+		am.Error(w, http.StatusNotFound, "not_found", "Resource not found")
+	case errors.Is(err, ErrNotImplemented):
+		am.Error(w, http.StatusNotImplemented, "not_implemented", "Not implemented")
+	default:
+		log.Error("request failed: ", err)
+		am.Error(w, http.StatusInternalServerError, "internal_error", "Internal error")
+	}
+}
+
+// parseID reads the {id} URL parameter.
+func parseID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		am.Error(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,18 @@
+package catalog
+
+import (
+	"github.com/google/uuid"
+)
+
+// Product is a model of the catalog feat.
+type Product struct {
+	ID    uuid.UUID `json:"id" bson:"_id"`
+	Name  string    `json:"name" bson:"name"`
+	Price float64   `json:"price" bson:"price"`
+	Stock int64     `json:"stock" bson:"stock"`
+}
+
+// NewProduct returns a Product with a fresh ID.
+func NewProduct() *Product {
+	return &Product{ID: uuid.New()}
+}
@@ -0,0 +1,10 @@
+package catalog
+
+// SQL statements used by ProductSQLiteRepo.
+const (
+	insertProductSQL = `INSERT INTO products (id, name, price, stock) VALUES (?, ?, ?, ?)`
+	selectProductSQL = `SELECT id, name, price, stock FROM products WHERE id = ?`
+	listProductSQL   = `SELECT id, name, price, stock FROM products ORDER BY id`
+	updateProductSQL = `UPDATE products SET name = ?, price = ?, stock = ? WHERE id = ?`
+	deleteProductSQL = `DELETE FROM products WHERE id = ?`
+)
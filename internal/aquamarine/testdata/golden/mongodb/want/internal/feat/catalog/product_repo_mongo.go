@@ -0,0 +1,76 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ProductMongoRepo is a ProductRepo backed by MongoDB.
+type ProductMongoRepo struct {
+	coll *mongo.Collection
+}
+
+// NewProductMongoRepo creates a ProductMongoRepo on db.
+func NewProductMongoRepo(db *mongo.Database) *ProductMongoRepo {
+	return &ProductMongoRepo{coll: db.Collection("products")}
+}
+
+// Create inserts m.
+func (r *ProductMongoRepo) Create(ctx context.Context, m *Product) error {
+	_, err := r.coll.InsertOne(ctx, m)
+	return err
+}
+
+// Get returns the Product with the given id.
+func (r *ProductMongoRepo) Get(ctx context.Context, id uuid.UUID) (*Product, error) {
+	var m Product
+	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&m)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// List returns every Product.
+func (r *ProductMongoRepo) List(ctx context.Context) ([]Product, error) {
+	cur, err := r.coll.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	items := []Product{}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Update saves m over the stored Product.
+func (r *ProductMongoRepo) Update(ctx context.Context, m *Product) error {
+	res, err := r.coll.ReplaceOne(ctx, bson.M{"_id": m.ID}, m)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes the Product with the given id.
+func (r *ProductMongoRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
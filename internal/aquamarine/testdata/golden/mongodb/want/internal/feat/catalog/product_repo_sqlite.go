@@ -0,0 +1,98 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ProductSQLiteRepo is a ProductRepo backed by SQLite.
+type ProductSQLiteRepo struct {
+	db *sql.DB
+}
+
+// NewProductSQLiteRepo creates a ProductSQLiteRepo on db.
+func NewProductSQLiteRepo(db *sql.DB) *ProductSQLiteRepo {
+	return &ProductSQLiteRepo{db: db}
+}
+
+// Create inserts m.
+func (r *ProductSQLiteRepo) Create(ctx context.Context, m *Product) error {
+	_, err := r.db.ExecContext(ctx, insertProductSQL, m.ID, m.Name, m.Price, m.Stock)
+	return err
+}
+
+// Get returns the Product with the given id.
+func (r *ProductSQLiteRepo) Get(ctx context.Context, id uuid.UUID) (*Product, error) {
+	m, err := scanProduct(r.db.QueryRowContext(ctx, selectProductSQL, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return m, err
+}
+
+// List returns every Product.
+func (r *ProductSQLiteRepo) List(ctx context.Context) ([]Product, error) {
+	return queryProducts(ctx, r.db, listProductSQL)
+}
+
+// Update saves m over the stored Product.
+func (r *ProductSQLiteRepo) Update(ctx context.Context, m *Product) error {
+	res, err := r.db.ExecContext(ctx, updateProductSQL, m.Name, m.Price, m.Stock, m.ID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes the Product with the given id.
+func (r *ProductSQLiteRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, deleteProductSQL, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// queryProducts runs a query selecting Product columns.
+func queryProducts(ctx context.Context, db *sql.DB, query string, args ...any) ([]Product, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Product{}
+	for rows.Next() {
+		m, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *m)
+	}
+	return items, rows.Err()
+}
+
+func scanProduct(row interface{ Scan(dest ...any) error }) (*Product, error) {
+	var m Product
+	if err := row.Scan(&m.ID, &m.Name, &m.Price, &m.Stock); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
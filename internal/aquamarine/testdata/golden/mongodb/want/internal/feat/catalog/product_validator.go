@@ -0,0 +1,24 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// ProductValidator checks Product values before they are stored.
+type ProductValidator struct{}
+
+// NewProductValidator creates a ProductValidator.
+func NewProductValidator() *ProductValidator {
+	return &ProductValidator{}
+}
+
+// Validate returns every rule m breaks.
+func (v *ProductValidator) Validate(ctx context.Context, m *Product) am.ValidationErrors {
+	var errs am.ValidationErrors
+	if !(am.IsRequired(m.Name)) {
+		errs = append(errs, am.ValidationError{Field: "name", Code: "required", Message: "name is required"})
+	}
+	return errs
+}
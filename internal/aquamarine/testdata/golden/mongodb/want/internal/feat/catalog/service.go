@@ -0,0 +1,44 @@
+package catalog
+
+import (
+	"context"
+	// aquamarine:user imports
+	// aquamarine:end
+)
+
+// Service exposes the catalog use cases.
+type Service interface {
+	Featured(ctx context.Context, req FeaturedRequest) (FeaturedResponse, error)
+}
+
+// FeaturedRequest is the input of Featured.
+type FeaturedRequest struct {
+	// aquamarine:user FeaturedRequest
+	// aquamarine:end
+}
+
+// FeaturedResponse is the output of Featured.
+type FeaturedResponse struct {
+	// aquamarine:user FeaturedResponse
+	// aquamarine:end
+}
+
+// service implements Service. The use cases below are stubs to fill in; code
+// between aquamarine:user and aquamarine:end markers survives regeneration,
+// the imports they need included.
+type service struct {
+	deps Deps
+}
+
+func newService(deps Deps) *service {
+	return &service{
+		deps: deps,
+	}
+}
+
+// Featured implements Service.
+func (s *service) Featured(ctx context.Context, req FeaturedRequest) (FeaturedResponse, error) {
+	// aquamarine:user Featured
+	return FeaturedResponse{}, ErrNotImplemented
+	// aquamarine:end
+}
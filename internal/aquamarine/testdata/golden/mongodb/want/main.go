@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"embed"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+
+	"github.com/go-chi/chi/v5"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"example.com/catalog/internal/feat/catalog"
+	"example.com/catalog/internal/platform"
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+//go:embed assets
+var assetsFS embed.FS
+
+func main() {
+	cfg, err := platform.LoadConfig(assetsFS, "assets/config/config.yaml")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logger := am.NewLogger(cfg.Log.Level)
+	xp := platform.XParams{Cfg: cfg, Log: logger}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.Database.DSN))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer client.Disconnect(context.Background())
+	db := client.Database(cfg.Database.Name)
+
+	catalogFeat, err := catalog.New(xp, catalog.Deps{
+		ProductRepo:  catalog.NewProductMongoRepo(db),
+		CategoryRepo: catalog.NewCategoryMongoRepo(db),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	apiRouter := chi.NewRouter()
+	webRouter := chi.NewRouter()
+
+	apiRouter.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("api ok"))
+	})
+
+	webRouter.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("web ok"))
+	})
+
+	deps := []any{catalogFeat}
+
+	starts, stops := am.Setup(ctx, apiRouter, webRouter, deps...)
+
+	if err := am.Start(ctx, starts, stops); err != nil {
+		log.Fatal(err)
+	}
+
+	servers := []am.Server{
+		{Name: "API", Addr: cfg.HTTP.API.Addr(), Handler: apiRouter},
+		{Name: "Web", Addr: cfg.HTTP.Web.Addr(), Handler: webRouter},
+	}
+
+	am.StartServers(servers, logger)
+
+	<-ctx.Done()
+
+	am.GracefulShutdown(servers, stops, logger)
+}
@@ -0,0 +1,6 @@
+-- auth: initial schema
+
+CREATE TABLE IF NOT EXISTS users (
+	id TEXT PRIMARY KEY,
+	email TEXT NOT NULL UNIQUE
+);
@@ -0,0 +1,8 @@
+-- blog: initial schema
+
+CREATE TABLE IF NOT EXISTS posts (
+	id TEXT PRIMARY KEY,
+	title TEXT NOT NULL,
+	author_id TEXT NOT NULL,
+	reviewer_id TEXT
+);
@@ -0,0 +1,7 @@
+-- profile: initial schema
+
+CREATE TABLE IF NOT EXISTS profiles (
+	id TEXT PRIMARY KEY,
+	user_id TEXT NOT NULL,
+	name TEXT NOT NULL
+);
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"example.com/social/internal/platform"
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// Errors returned by the auth feat.
+var (
+	ErrNotFound       = errors.New("auth: not found")
+	ErrNotImplemented = errors.New("auth: not implemented")
+)
+
+// Deps lists what the auth feat needs from the rest of the app.
+type Deps struct {
+	UserRepo UserRepo
+}
+
+// Feature wires the auth handlers. It is registered through am.Setup.
+type Feature struct {
+	xp          platform.XParams
+	deps        Deps
+	svc         Service
+	userHandler *UserHandler
+}
+
+// New builds the auth feat from its dependencies.
+func New(xp platform.XParams, deps Deps) (*Feature, error) {
+	f := &Feature{xp: xp, deps: deps}
+	f.svc = newService(deps)
+	f.userHandler = NewUserHandler(deps.UserRepo, xp)
+	return f, nil
+}
+
+// Service returns the auth use cases for other feats to consume.
+func (f *Feature) Service() Service {
+	return f.svc
+}
+
+// RegisterAPIRoutes mounts the auth JSON endpoints.
+func (f *Feature) RegisterAPIRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Route("/auth/users", f.userHandler.Routes)
+	})
+}
+
+// writeError maps feat errors to JSON error responses.
+func writeError(w http.ResponseWriter, log am.Logger, err error) {
+	var verrs am.ValidationErrors
+	switch {
+	case errors.As(err, &verrs):
+		am.Error(w, http.StatusUnprocessableEntity, "validation_failed", "Validation failed", verrs...)
+	case errors.Is(err, ErrNotFound):
+		am.Error(w, http.StatusNotFound, "not_found", "Resource not found")
+	case errors.Is(err, ErrNotImplemented):
+		am.Error(w, http.StatusNotImplemented, "not_implemented", "Not implemented")
+	default:
+		log.Error("request failed: ", err)
+		am.Error(w, http.StatusInternalServerError, "internal_error", "Internal error")
+	}
+}
+
+// parseID reads the {id} URL parameter.
+func parseID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		am.Error(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
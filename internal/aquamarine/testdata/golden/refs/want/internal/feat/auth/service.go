@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Service exposes the auth use cases.
+type Service interface {
+	HasUser(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+// service implements Service. The use cases below are stubs to fill in; code
+// between aquamarine:user and aquamarine:end markers survives regeneration,
+// the imports they need included.
+type service struct {
+	deps Deps
+}
+
+func newService(deps Deps) *service {
+	return &service{
+		deps: deps,
+	}
+}
+
+// HasUser reports whether the User id exists. Other feats reach it
+// through their read-only ports on User.
+func (s *service) HasUser(ctx context.Context, id uuid.UUID) (bool, error) {
+	_, err := s.deps.UserRepo.Get(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,10 @@
+package auth
+
+// SQL statements used by UserSQLiteRepo.
+const (
+	insertUserSQL = `INSERT INTO users (id, email) VALUES (?, ?)`
+	selectUserSQL = `SELECT id, email FROM users WHERE id = ?`
+	listUserSQL   = `SELECT id, email FROM users ORDER BY id`
+	updateUserSQL = `UPDATE users SET email = ? WHERE id = ?`
+	deleteUserSQL = `DELETE FROM users WHERE id = ?`
+)
@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// UserValidator checks User values before they are stored.
+type UserValidator struct{}
+
+// NewUserValidator creates a UserValidator.
+func NewUserValidator() *UserValidator {
+	return &UserValidator{}
+}
+
+// Validate returns every rule m breaks.
+func (v *UserValidator) Validate(ctx context.Context, m *User) am.ValidationErrors {
+	var errs am.ValidationErrors
+	if !(am.IsRequired(m.Email)) {
+		errs = append(errs, am.ValidationError{Field: "email", Code: "required", Message: "email is required"})
+	}
+	return errs
+}
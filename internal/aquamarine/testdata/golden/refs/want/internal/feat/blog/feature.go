@@ -0,0 +1,78 @@
+package blog
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"example.com/social/internal/platform"
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// Errors returned by the blog feat.
+var (
+	ErrNotFound       = errors.New("blog: not found")
+	ErrNotImplemented = errors.New("blog: not implemented")
+)
+
+// Deps lists what the blog feat needs from the rest of the app.
+type Deps struct {
+	PostRepo       PostRepo
+	AuthUserReader AuthUserReader
+}
+
+// AuthUserReader is the read-only view of auth.User the blog feat relies on,
+// for Post.author_id, Post.reviewer_id. The auth feat's Service satisfies it.
+type AuthUserReader interface {
+	HasUser(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+// Feature wires the blog handlers. It is registered through am.Setup.
+type Feature struct {
+	xp          platform.XParams
+	deps        Deps
+	postHandler *PostHandler
+}
+
+// New builds the blog feat from its dependencies.
+func New(xp platform.XParams, deps Deps) (*Feature, error) {
+	f := &Feature{xp: xp, deps: deps}
+	f.postHandler = NewPostHandler(deps.PostRepo, xp)
+	return f, nil
+}
+
+// RegisterAPIRoutes mounts the blog JSON endpoints.
+func (f *Feature) RegisterAPIRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Route("/blog/posts", f.postHandler.Routes)
+	})
+}
+
+// writeError maps feat errors to JSON error responses.
+func writeError(w http.ResponseWriter, log am.Logger, err error) {
+	var verrs am.ValidationErrors
+	switch {
+	case errors.As(err, &verrs):
+		am.Error(w, http.StatusUnprocessableEntity, "validation_failed", "Validation failed", verrs...)
+	case errors.Is(err, ErrNotFound):
+		am.Error(w, http.StatusNotFound, "not_found", "Resource not found")
+	case errors.Is(err, ErrNotImplemented):
+		am.Error(w, http.StatusNotImplemented, "not_implemented", "Not implemented")
+	default:
+		log.Error("request failed: ", err)
+		am.Error(w, http.StatusInternalServerError, "internal_error", "Internal error")
+	}
+}
+
+// parseID reads the {id} URL parameter.
+func parseID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		am.Error(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
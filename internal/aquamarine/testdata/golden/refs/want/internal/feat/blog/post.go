@@ -0,0 +1,18 @@
+package blog
+
+import (
+	"github.com/google/uuid"
+)
+
+// Post is a model of the blog feat.
+type Post struct {
+	ID         uuid.UUID  `json:"id" bson:"_id"`
+	Title      string     `json:"title" bson:"title"`
+	AuthorID   uuid.UUID  `json:"author_id" bson:"author_id"`
+	ReviewerID *uuid.UUID `json:"reviewer_id" bson:"reviewer_id"`
+}
+
+// NewPost returns a Post with a fresh ID.
+func NewPost() *Post {
+	return &Post{ID: uuid.New()}
+}
@@ -0,0 +1,10 @@
+package blog
+
+// SQL statements used by PostSQLiteRepo.
+const (
+	insertPostSQL = `INSERT INTO posts (id, title, author_id, reviewer_id) VALUES (?, ?, ?, ?)`
+	selectPostSQL = `SELECT id, title, author_id, reviewer_id FROM posts WHERE id = ?`
+	listPostSQL   = `SELECT id, title, author_id, reviewer_id FROM posts ORDER BY id`
+	updatePostSQL = `UPDATE posts SET title = ?, author_id = ?, reviewer_id = ? WHERE id = ?`
+	deletePostSQL = `DELETE FROM posts WHERE id = ?`
+)
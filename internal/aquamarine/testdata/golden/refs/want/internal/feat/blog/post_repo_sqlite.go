@@ -0,0 +1,98 @@
+package blog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// PostSQLiteRepo is a PostRepo backed by SQLite.
+type PostSQLiteRepo struct {
+	db *sql.DB
+}
+
+// NewPostSQLiteRepo creates a PostSQLiteRepo on db.
+func NewPostSQLiteRepo(db *sql.DB) *PostSQLiteRepo {
+	return &PostSQLiteRepo{db: db}
+}
+
+// Create inserts m.
+func (r *PostSQLiteRepo) Create(ctx context.Context, m *Post) error {
+	_, err := r.db.ExecContext(ctx, insertPostSQL, m.ID, m.Title, m.AuthorID, m.ReviewerID)
+	return err
+}
+
+// Get returns the Post with the given id.
+func (r *PostSQLiteRepo) Get(ctx context.Context, id uuid.UUID) (*Post, error) {
+	m, err := scanPost(r.db.QueryRowContext(ctx, selectPostSQL, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return m, err
+}
+
+// List returns every Post.
+func (r *PostSQLiteRepo) List(ctx context.Context) ([]Post, error) {
+	return queryPosts(ctx, r.db, listPostSQL)
+}
+
+// Update saves m over the stored Post.
+func (r *PostSQLiteRepo) Update(ctx context.Context, m *Post) error {
+	res, err := r.db.ExecContext(ctx, updatePostSQL, m.Title, m.AuthorID, m.ReviewerID, m.ID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes the Post with the given id.
+func (r *PostSQLiteRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, deletePostSQL, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// queryPosts runs a query selecting Post columns.
+func queryPosts(ctx context.Context, db *sql.DB, query string, args ...any) ([]Post, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Post{}
+	for rows.Next() {
+		m, err := scanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *m)
+	}
+	return items, rows.Err()
+}
+
+func scanPost(row interface{ Scan(dest ...any) error }) (*Post, error) {
+	var m Post
+	if err := row.Scan(&m.ID, &m.Title, &m.AuthorID, &m.ReviewerID); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
@@ -0,0 +1,17 @@
+package profile
+
+import (
+	"github.com/google/uuid"
+)
+
+// Profile is a model of the profile feat.
+type Profile struct {
+	ID     uuid.UUID `json:"id" bson:"_id"`
+	UserID uuid.UUID `json:"user_id" bson:"user_id"`
+	Name   string    `json:"name" bson:"name"`
+}
+
+// NewProfile returns a Profile with a fresh ID.
+func NewProfile() *Profile {
+	return &Profile{ID: uuid.New()}
+}
@@ -0,0 +1,10 @@
+package profile
+
+// SQL statements used by ProfileSQLiteRepo.
+const (
+	insertProfileSQL = `INSERT INTO profiles (id, user_id, name) VALUES (?, ?, ?)`
+	selectProfileSQL = `SELECT id, user_id, name FROM profiles WHERE id = ?`
+	listProfileSQL   = `SELECT id, user_id, name FROM profiles ORDER BY id`
+	updateProfileSQL = `UPDATE profiles SET user_id = ?, name = ? WHERE id = ?`
+	deleteProfileSQL = `DELETE FROM profiles WHERE id = ?`
+)
@@ -0,0 +1,17 @@
+package profile
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ProfileRepo persists Profile values.
+// Get, Update and Delete return ErrNotFound when no Profile matches.
+type ProfileRepo interface {
+	Create(ctx context.Context, m *Profile) error
+	Get(ctx context.Context, id uuid.UUID) (*Profile, error)
+	List(ctx context.Context) ([]Profile, error)
+	Update(ctx context.Context, m *Profile) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,98 @@
+package profile
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ProfileSQLiteRepo is a ProfileRepo backed by SQLite.
+type ProfileSQLiteRepo struct {
+	db *sql.DB
+}
+
+// NewProfileSQLiteRepo creates a ProfileSQLiteRepo on db.
+func NewProfileSQLiteRepo(db *sql.DB) *ProfileSQLiteRepo {
+	return &ProfileSQLiteRepo{db: db}
+}
+
+// Create inserts m.
+func (r *ProfileSQLiteRepo) Create(ctx context.Context, m *Profile) error {
+	_, err := r.db.ExecContext(ctx, insertProfileSQL, m.ID, m.UserID, m.Name)
+	return err
+}
+
+// Get returns the Profile with the given id.
+func (r *ProfileSQLiteRepo) Get(ctx context.Context, id uuid.UUID) (*Profile, error) {
+	m, err := scanProfile(r.db.QueryRowContext(ctx, selectProfileSQL, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return m, err
+}
+
+// List returns every Profile.
+func (r *ProfileSQLiteRepo) List(ctx context.Context) ([]Profile, error) {
+	return queryProfiles(ctx, r.db, listProfileSQL)
+}
+
+// Update saves m over the stored Profile.
+func (r *ProfileSQLiteRepo) Update(ctx context.Context, m *Profile) error {
+	res, err := r.db.ExecContext(ctx, updateProfileSQL, m.UserID, m.Name, m.ID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes the Profile with the given id.
+func (r *ProfileSQLiteRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, deleteProfileSQL, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// queryProfiles runs a query selecting Profile columns.
+func queryProfiles(ctx context.Context, db *sql.DB, query string, args ...any) ([]Profile, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Profile{}
+	for rows.Next() {
+		m, err := scanProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *m)
+	}
+	return items, rows.Err()
+}
+
+func scanProfile(row interface{ Scan(dest ...any) error }) (*Profile, error) {
+	var m Profile
+	if err := row.Scan(&m.ID, &m.UserID, &m.Name); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
@@ -0,0 +1,27 @@
+package profile
+
+import (
+	"context"
+
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// ProfileValidator checks Profile values before they are stored.
+type ProfileValidator struct{}
+
+// NewProfileValidator creates a ProfileValidator.
+func NewProfileValidator() *ProfileValidator {
+	return &ProfileValidator{}
+}
+
+// Validate returns every rule m breaks.
+func (v *ProfileValidator) Validate(ctx context.Context, m *Profile) am.ValidationErrors {
+	var errs am.ValidationErrors
+	if !(am.IsRequiredUUID(m.UserID)) {
+		errs = append(errs, am.ValidationError{Field: "user_id", Code: "required", Message: "user_id is required"})
+	}
+	if !(am.IsRequired(m.Name)) {
+		errs = append(errs, am.ValidationError{Field: "name", Code: "required", Message: "name is required"})
+	}
+	return errs
+}
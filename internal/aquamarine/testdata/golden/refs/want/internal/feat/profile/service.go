@@ -0,0 +1,21 @@
+package profile
+
+// Service exposes the profile use cases.
+type Service interface {
+	ProfileRepo
+}
+
+// service implements Service. The use cases below are stubs to fill in; code
+// between aquamarine:user and aquamarine:end markers survives regeneration,
+// the imports they need included.
+type service struct {
+	ProfileRepo
+	deps Deps
+}
+
+func newService(deps Deps) *service {
+	return &service{
+		ProfileRepo: deps.ProfileRepo,
+		deps:        deps,
+	}
+}
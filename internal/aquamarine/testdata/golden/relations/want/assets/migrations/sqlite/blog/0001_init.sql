@@ -0,0 +1,30 @@
+-- blog: initial schema
+
+CREATE TABLE IF NOT EXISTS users (
+	id TEXT PRIMARY KEY,
+	email TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS roles (
+	id TEXT PRIMARY KEY,
+	name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS posts (
+	id TEXT PRIMARY KEY,
+	title TEXT NOT NULL,
+	author_id TEXT NOT NULL REFERENCES users (id),
+	editor_id TEXT REFERENCES users (id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS comments (
+	id TEXT PRIMARY KEY,
+	body TEXT NOT NULL,
+	post_id TEXT NOT NULL REFERENCES posts (id)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+	user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	role_id TEXT NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+	PRIMARY KEY (user_id, role_id)
+);
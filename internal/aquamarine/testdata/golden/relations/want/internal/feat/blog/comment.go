@@ -0,0 +1,17 @@
+package blog
+
+import (
+	"github.com/google/uuid"
+)
+
+// Comment is a model of the blog feat.
+type Comment struct {
+	ID     uuid.UUID `json:"id" bson:"_id"`
+	Body   string    `json:"body" bson:"body"`
+	PostID uuid.UUID `json:"post_id" bson:"post_id"`
+}
+
+// NewComment returns a Comment with a fresh ID.
+func NewComment() *Comment {
+	return &Comment{ID: uuid.New()}
+}
@@ -0,0 +1,11 @@
+package blog
+
+// SQL statements used by CommentSQLiteRepo.
+const (
+	insertCommentSQL       = `INSERT INTO comments (id, body, post_id) VALUES (?, ?, ?)`
+	selectCommentSQL       = `SELECT id, body, post_id FROM comments WHERE id = ?`
+	listCommentSQL         = `SELECT id, body, post_id FROM comments ORDER BY id`
+	updateCommentSQL       = `UPDATE comments SET body = ?, post_id = ? WHERE id = ?`
+	deleteCommentSQL       = `DELETE FROM comments WHERE id = ?`
+	listCommentsForPostSQL = `SELECT id, body, post_id FROM comments WHERE post_id = ? ORDER BY id`
+)
@@ -0,0 +1,18 @@
+package blog
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// CommentRepo persists Comment values.
+// Get, Update and Delete return ErrNotFound when no Comment matches.
+type CommentRepo interface {
+	Create(ctx context.Context, m *Comment) error
+	Get(ctx context.Context, id uuid.UUID) (*Comment, error)
+	List(ctx context.Context) ([]Comment, error)
+	Update(ctx context.Context, m *Comment) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListCommentsForPost(ctx context.Context, id uuid.UUID) ([]Comment, error)
+}
@@ -0,0 +1,89 @@
+package blog
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// CommentMongoRepo is a CommentRepo backed by MongoDB.
+type CommentMongoRepo struct {
+	coll *mongo.Collection
+}
+
+// NewCommentMongoRepo creates a CommentMongoRepo on db.
+func NewCommentMongoRepo(db *mongo.Database) *CommentMongoRepo {
+	return &CommentMongoRepo{coll: db.Collection("comments")}
+}
+
+// Create inserts m.
+func (r *CommentMongoRepo) Create(ctx context.Context, m *Comment) error {
+	_, err := r.coll.InsertOne(ctx, m)
+	return err
+}
+
+// Get returns the Comment with the given id.
+func (r *CommentMongoRepo) Get(ctx context.Context, id uuid.UUID) (*Comment, error) {
+	var m Comment
+	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&m)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// List returns every Comment.
+func (r *CommentMongoRepo) List(ctx context.Context) ([]Comment, error) {
+	cur, err := r.coll.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	items := []Comment{}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Update saves m over the stored Comment.
+func (r *CommentMongoRepo) Update(ctx context.Context, m *Comment) error {
+	res, err := r.coll.ReplaceOne(ctx, bson.M{"_id": m.ID}, m)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes the Comment with the given id.
+func (r *CommentMongoRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListCommentsForPost returns the Comments whose post_id is id.
+func (r *CommentMongoRepo) ListCommentsForPost(ctx context.Context, id uuid.UUID) ([]Comment, error) {
+	cur, err := r.coll.Find(ctx, bson.M{"post_id": id})
+	if err != nil {
+		return nil, err
+	}
+	items := []Comment{}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
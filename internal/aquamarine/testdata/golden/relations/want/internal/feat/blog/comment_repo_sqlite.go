@@ -0,0 +1,103 @@
+package blog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// CommentSQLiteRepo is a CommentRepo backed by SQLite.
+type CommentSQLiteRepo struct {
+	db *sql.DB
+}
+
+// NewCommentSQLiteRepo creates a CommentSQLiteRepo on db.
+func NewCommentSQLiteRepo(db *sql.DB) *CommentSQLiteRepo {
+	return &CommentSQLiteRepo{db: db}
+}
+
+// Create inserts m.
+func (r *CommentSQLiteRepo) Create(ctx context.Context, m *Comment) error {
+	_, err := r.db.ExecContext(ctx, insertCommentSQL, m.ID, m.Body, m.PostID)
+	return err
+}
+
+// Get returns the Comment with the given id.
+func (r *CommentSQLiteRepo) Get(ctx context.Context, id uuid.UUID) (*Comment, error) {
+	m, err := scanComment(r.db.QueryRowContext(ctx, selectCommentSQL, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return m, err
+}
+
+// List returns every Comment.
+func (r *CommentSQLiteRepo) List(ctx context.Context) ([]Comment, error) {
+	return queryComments(ctx, r.db, listCommentSQL)
+}
+
+// Update saves m over the stored Comment.
+func (r *CommentSQLiteRepo) Update(ctx context.Context, m *Comment) error {
+	res, err := r.db.ExecContext(ctx, updateCommentSQL, m.Body, m.PostID, m.ID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes the Comment with the given id.
+func (r *CommentSQLiteRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, deleteCommentSQL, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListCommentsForPost returns the Comments whose post_id is id.
+func (r *CommentSQLiteRepo) ListCommentsForPost(ctx context.Context, id uuid.UUID) ([]Comment, error) {
+	return queryComments(ctx, r.db, listCommentsForPostSQL, id)
+}
+
+// queryComments runs a query selecting Comment columns.
+func queryComments(ctx context.Context, db *sql.DB, query string, args ...any) ([]Comment, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []Comment{}
+	for rows.Next() {
+		m, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *m)
+	}
+	return items, rows.Err()
+}
+
+func scanComment(row interface{ Scan(dest ...any) error }) (*Comment, error) {
+	var m Comment
+	if err := row.Scan(&m.ID, &m.Body, &m.PostID); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
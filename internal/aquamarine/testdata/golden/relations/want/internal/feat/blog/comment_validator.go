@@ -0,0 +1,27 @@
+package blog
+
+import (
+	"context"
+
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// CommentValidator checks Comment values before they are stored.
+type CommentValidator struct{}
+
+// NewCommentValidator creates a CommentValidator.
+func NewCommentValidator() *CommentValidator {
+	return &CommentValidator{}
+}
+
+// Validate returns every rule m breaks.
+func (v *CommentValidator) Validate(ctx context.Context, m *Comment) am.ValidationErrors {
+	var errs am.ValidationErrors
+	if !(am.IsRequired(m.Body)) {
+		errs = append(errs, am.ValidationError{Field: "body", Code: "required", Message: "body is required"})
+	}
+	if !(am.IsRequiredUUID(m.PostID)) {
+		errs = append(errs, am.ValidationError{Field: "post_id", Code: "required", Message: "post_id is required"})
+	}
+	return errs
+}
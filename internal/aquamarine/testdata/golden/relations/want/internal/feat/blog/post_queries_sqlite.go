@@ -0,0 +1,12 @@
+package blog
+
+// SQL statements used by PostSQLiteRepo.
+const (
+	insertPostSQL         = `INSERT INTO posts (id, title, author_id, editor_id) VALUES (?, ?, ?, ?)`
+	selectPostSQL         = `SELECT id, title, author_id, editor_id FROM posts WHERE id = ?`
+	listPostSQL           = `SELECT id, title, author_id, editor_id FROM posts ORDER BY id`
+	updatePostSQL         = `UPDATE posts SET title = ?, author_id = ?, editor_id = ? WHERE id = ?`
+	deletePostSQL         = `DELETE FROM posts WHERE id = ?`
+	listPostsForAuthorSQL = `SELECT id, title, author_id, editor_id FROM posts WHERE author_id = ? ORDER BY id`
+	listPostsForEditorSQL = `SELECT id, title, author_id, editor_id FROM posts WHERE editor_id = ? ORDER BY id`
+)
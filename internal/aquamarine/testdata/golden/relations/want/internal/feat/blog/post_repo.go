@@ -0,0 +1,19 @@
+package blog
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// PostRepo persists Post values.
+// Get, Update and Delete return ErrNotFound when no Post matches.
+type PostRepo interface {
+	Create(ctx context.Context, m *Post) error
+	Get(ctx context.Context, id uuid.UUID) (*Post, error)
+	List(ctx context.Context) ([]Post, error)
+	Update(ctx context.Context, m *Post) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListPostsForAuthor(ctx context.Context, id uuid.UUID) ([]Post, error)
+	ListPostsForEditor(ctx context.Context, id uuid.UUID) ([]Post, error)
+}
@@ -0,0 +1,102 @@
+package blog
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// PostMongoRepo is a PostRepo backed by MongoDB.
+type PostMongoRepo struct {
+	coll *mongo.Collection
+}
+
+// NewPostMongoRepo creates a PostMongoRepo on db.
+func NewPostMongoRepo(db *mongo.Database) *PostMongoRepo {
+	return &PostMongoRepo{coll: db.Collection("posts")}
+}
+
+// Create inserts m.
+func (r *PostMongoRepo) Create(ctx context.Context, m *Post) error {
+	_, err := r.coll.InsertOne(ctx, m)
+	return err
+}
+
+// Get returns the Post with the given id.
+func (r *PostMongoRepo) Get(ctx context.Context, id uuid.UUID) (*Post, error) {
+	var m Post
+	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&m)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// List returns every Post.
+func (r *PostMongoRepo) List(ctx context.Context) ([]Post, error) {
+	cur, err := r.coll.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	items := []Post{}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Update saves m over the stored Post.
+func (r *PostMongoRepo) Update(ctx context.Context, m *Post) error {
+	res, err := r.coll.ReplaceOne(ctx, bson.M{"_id": m.ID}, m)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes the Post with the given id.
+func (r *PostMongoRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListPostsForAuthor returns the Posts whose author_id is id.
+func (r *PostMongoRepo) ListPostsForAuthor(ctx context.Context, id uuid.UUID) ([]Post, error) {
+	cur, err := r.coll.Find(ctx, bson.M{"author_id": id})
+	if err != nil {
+		return nil, err
+	}
+	items := []Post{}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListPostsForEditor returns the Posts whose editor_id is id.
+func (r *PostMongoRepo) ListPostsForEditor(ctx context.Context, id uuid.UUID) ([]Post, error) {
+	cur, err := r.coll.Find(ctx, bson.M{"editor_id": id})
+	if err != nil {
+		return nil, err
+	}
+	items := []Post{}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,27 @@
+package blog
+
+import (
+	"context"
+
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// PostValidator checks Post values before they are stored.
+type PostValidator struct{}
+
+// NewPostValidator creates a PostValidator.
+func NewPostValidator() *PostValidator {
+	return &PostValidator{}
+}
+
+// Validate returns every rule m breaks.
+func (v *PostValidator) Validate(ctx context.Context, m *Post) am.ValidationErrors {
+	var errs am.ValidationErrors
+	if !(am.IsRequired(m.Title)) {
+		errs = append(errs, am.ValidationError{Field: "title", Code: "required", Message: "title is required"})
+	}
+	if !(am.IsRequiredUUID(m.AuthorID)) {
+		errs = append(errs, am.ValidationError{Field: "author_id", Code: "required", Message: "author_id is required"})
+	}
+	return errs
+}
@@ -0,0 +1,10 @@
+package blog
+
+// SQL statements used by RoleSQLiteRepo.
+const (
+	insertRoleSQL = `INSERT INTO roles (id, name) VALUES (?, ?)`
+	selectRoleSQL = `SELECT id, name FROM roles WHERE id = ?`
+	listRoleSQL   = `SELECT id, name FROM roles ORDER BY id`
+	updateRoleSQL = `UPDATE roles SET name = ? WHERE id = ?`
+	deleteRoleSQL = `DELETE FROM roles WHERE id = ?`
+)
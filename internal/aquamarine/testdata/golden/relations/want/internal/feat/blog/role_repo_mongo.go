@@ -0,0 +1,76 @@
+package blog
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// RoleMongoRepo is a RoleRepo backed by MongoDB.
+type RoleMongoRepo struct {
+	coll *mongo.Collection
+}
+
+// NewRoleMongoRepo creates a RoleMongoRepo on db.
+func NewRoleMongoRepo(db *mongo.Database) *RoleMongoRepo {
+	return &RoleMongoRepo{coll: db.Collection("roles")}
+}
+
+// Create inserts m.
+func (r *RoleMongoRepo) Create(ctx context.Context, m *Role) error {
+	_, err := r.coll.InsertOne(ctx, m)
+	return err
+}
+
+// Get returns the Role with the given id.
+func (r *RoleMongoRepo) Get(ctx context.Context, id uuid.UUID) (*Role, error) {
+	var m Role
+	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&m)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// List returns every Role.
+func (r *RoleMongoRepo) List(ctx context.Context) ([]Role, error) {
+	cur, err := r.coll.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	items := []Role{}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Update saves m over the stored Role.
+func (r *RoleMongoRepo) Update(ctx context.Context, m *Role) error {
+	res, err := r.coll.ReplaceOne(ctx, bson.M{"_id": m.ID}, m)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes the Role with the given id.
+func (r *RoleMongoRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
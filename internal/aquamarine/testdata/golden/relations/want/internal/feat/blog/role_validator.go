@@ -0,0 +1,24 @@
+package blog
+
+import (
+	"context"
+
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// RoleValidator checks Role values before they are stored.
+type RoleValidator struct{}
+
+// NewRoleValidator creates a RoleValidator.
+func NewRoleValidator() *RoleValidator {
+	return &RoleValidator{}
+}
+
+// Validate returns every rule m breaks.
+func (v *RoleValidator) Validate(ctx context.Context, m *Role) am.ValidationErrors {
+	var errs am.ValidationErrors
+	if !(am.IsRequired(m.Name)) {
+		errs = append(errs, am.ValidationError{Field: "name", Code: "required", Message: "name is required"})
+	}
+	return errs
+}
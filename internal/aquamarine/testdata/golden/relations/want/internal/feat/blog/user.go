@@ -0,0 +1,16 @@
+package blog
+
+import (
+	"github.com/google/uuid"
+)
+
+// User is a model of the blog feat.
+type User struct {
+	ID    uuid.UUID `json:"id" bson:"_id"`
+	Email string    `json:"email" bson:"email"`
+}
+
+// NewUser returns a User with a fresh ID.
+func NewUser() *User {
+	return &User{ID: uuid.New()}
+}
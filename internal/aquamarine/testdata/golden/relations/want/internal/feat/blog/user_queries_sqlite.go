@@ -0,0 +1,13 @@
+package blog
+
+// SQL statements used by UserSQLiteRepo.
+const (
+	insertUserSQL       = `INSERT INTO users (id, email) VALUES (?, ?)`
+	selectUserSQL       = `SELECT id, email FROM users WHERE id = ?`
+	listUserSQL         = `SELECT id, email FROM users ORDER BY id`
+	updateUserSQL       = `UPDATE users SET email = ? WHERE id = ?`
+	deleteUserSQL       = `DELETE FROM users WHERE id = ?`
+	listRolesForUserSQL = `SELECT roles.id, roles.name FROM roles JOIN user_roles ON user_roles.role_id = roles.id WHERE user_roles.user_id = ? ORDER BY roles.id`
+	userAssignRoleSQL   = `INSERT OR IGNORE INTO user_roles (user_id, role_id) VALUES (?, ?)`
+	userUnassignRoleSQL = `DELETE FROM user_roles WHERE user_id = ? AND role_id = ?`
+)
@@ -0,0 +1,20 @@
+package blog
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// UserRepo persists User values.
+// Get, Update and Delete return ErrNotFound when no User matches.
+type UserRepo interface {
+	Create(ctx context.Context, m *User) error
+	Get(ctx context.Context, id uuid.UUID) (*User, error)
+	List(ctx context.Context) ([]User, error)
+	Update(ctx context.Context, m *User) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListRolesForUser(ctx context.Context, id uuid.UUID) ([]Role, error)
+	AssignRole(ctx context.Context, id, roleID uuid.UUID) error
+	UnassignRole(ctx context.Context, id, roleID uuid.UUID) error
+}
@@ -0,0 +1,126 @@
+package blog
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// UserMongoRepo is a UserRepo backed by MongoDB.
+type UserMongoRepo struct {
+	coll *mongo.Collection
+}
+
+// NewUserMongoRepo creates a UserMongoRepo on db.
+func NewUserMongoRepo(db *mongo.Database) *UserMongoRepo {
+	return &UserMongoRepo{coll: db.Collection("users")}
+}
+
+// Create inserts m.
+func (r *UserMongoRepo) Create(ctx context.Context, m *User) error {
+	_, err := r.coll.InsertOne(ctx, m)
+	return err
+}
+
+// Get returns the User with the given id.
+func (r *UserMongoRepo) Get(ctx context.Context, id uuid.UUID) (*User, error) {
+	var m User
+	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&m)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// List returns every User.
+func (r *UserMongoRepo) List(ctx context.Context) ([]User, error) {
+	cur, err := r.coll.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	items := []User{}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Update saves m over the stored User, keeping its assigned ids.
+func (r *UserMongoRepo) Update(ctx context.Context, m *User) error {
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": m.ID}, bson.M{"$set": m})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes the User with the given id.
+func (r *UserMongoRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListRolesForUser returns the Roles assigned to the User id.
+// Their ids are kept in the role_ids array of its document.
+func (r *UserMongoRepo) ListRolesForUser(ctx context.Context, id uuid.UUID) ([]Role, error) {
+	var doc struct {
+		IDs []uuid.UUID `bson:"role_ids"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"role_ids": 1})
+	err := r.coll.FindOne(ctx, bson.M{"_id": id}, opts).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	items := []Role{}
+	if len(doc.IDs) == 0 {
+		return items, nil
+	}
+	cur, err := r.coll.Database().Collection("roles").Find(ctx, bson.M{"_id": bson.M{"$in": doc.IDs}})
+	if err != nil {
+		return nil, err
+	}
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// AssignRole links the Role roleID to the User id; assigning it twice is a no-op.
+func (r *UserMongoRepo) AssignRole(ctx context.Context, id, roleID uuid.UUID) error {
+	return r.updateRefs(ctx, id, bson.M{"$addToSet": bson.M{"role_ids": roleID}})
+}
+
+// UnassignRole unlinks the Role roleID from the User id.
+func (r *UserMongoRepo) UnassignRole(ctx context.Context, id, roleID uuid.UUID) error {
+	return r.updateRefs(ctx, id, bson.M{"$pull": bson.M{"role_ids": roleID}})
+}
+
+func (r *UserMongoRepo) updateRefs(ctx context.Context, id uuid.UUID, update bson.M) error {
+	res, err := r.coll.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package blog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// UserSQLiteRepo is a UserRepo backed by SQLite.
+type UserSQLiteRepo struct {
+	db *sql.DB
+}
+
+// NewUserSQLiteRepo creates a UserSQLiteRepo on db.
+func NewUserSQLiteRepo(db *sql.DB) *UserSQLiteRepo {
+	return &UserSQLiteRepo{db: db}
+}
+
+// Create inserts m.
+func (r *UserSQLiteRepo) Create(ctx context.Context, m *User) error {
+	_, err := r.db.ExecContext(ctx, insertUserSQL, m.ID, m.Email)
+	return err
+}
+
+// Get returns the User with the given id.
+func (r *UserSQLiteRepo) Get(ctx context.Context, id uuid.UUID) (*User, error) {
+	m, err := scanUser(r.db.QueryRowContext(ctx, selectUserSQL, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return m, err
+}
+
+// List returns every User.
+func (r *UserSQLiteRepo) List(ctx context.Context) ([]User, error) {
+	return queryUsers(ctx, r.db, listUserSQL)
+}
+
+// Update saves m over the stored User.
+func (r *UserSQLiteRepo) Update(ctx context.Context, m *User) error {
+	res, err := r.db.ExecContext(ctx, updateUserSQL, m.Email, m.ID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes the User with the given id.
+func (r *UserSQLiteRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, deleteUserSQL, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListRolesForUser returns the Roles assigned to the User id.
+func (r *UserSQLiteRepo) ListRolesForUser(ctx context.Context, id uuid.UUID) ([]Role, error) {
+	return queryRoles(ctx, r.db, listRolesForUserSQL, id)
+}
+
+// AssignRole links the Role roleID to the User id; assigning it twice is a no-op.
+func (r *UserSQLiteRepo) AssignRole(ctx context.Context, id, roleID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, userAssignRoleSQL, id, roleID)
+	return err
+}
+
+// UnassignRole unlinks the Role roleID from the User id.
+func (r *UserSQLiteRepo) UnassignRole(ctx context.Context, id, roleID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, userUnassignRoleSQL, id, roleID)
+	return err
+}
+
+// queryUsers runs a query selecting User columns.
+func queryUsers(ctx context.Context, db *sql.DB, query string, args ...any) ([]User, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []User{}
+	for rows.Next() {
+		m, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *m)
+	}
+	return items, rows.Err()
+}
+
+func scanUser(row interface{ Scan(dest ...any) error }) (*User, error) {
+	var m User
+	if err := row.Scan(&m.ID, &m.Email); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
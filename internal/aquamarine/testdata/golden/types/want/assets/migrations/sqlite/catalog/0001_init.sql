@@ -0,0 +1,19 @@
+-- catalog: initial schema
+
+CREATE TABLE IF NOT EXISTS products (
+	id TEXT PRIMARY KEY,
+	name TEXT NOT NULL,
+	site TEXT,
+	price TEXT NOT NULL DEFAULT '0.00',
+	discount TEXT,
+	stock INTEGER DEFAULT 0,
+	active INTEGER NOT NULL DEFAULT TRUE,
+	launch DATE,
+	listed TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	image BLOB,
+	attrs TEXT NOT NULL,
+	tags TEXT NOT NULL,
+	sizes TEXT NOT NULL,
+	status TEXT NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'published', 'archived')),
+	tier TEXT CHECK (tier IN ('basic', 'in_review'))
+);
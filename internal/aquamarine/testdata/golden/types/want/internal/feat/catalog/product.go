@@ -0,0 +1,114 @@
+package catalog
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// Product is a model of the catalog feat.
+type Product struct {
+	ID       uuid.UUID       `json:"id" bson:"_id"`
+	Name     string          `json:"name" bson:"name"`
+	Site     *string         `json:"site" bson:"site"`
+	Price    am.Decimal      `json:"price" bson:"price"`
+	Discount *am.Decimal     `json:"discount" bson:"discount"`
+	Stock    *int            `json:"stock" bson:"stock"`
+	Active   bool            `json:"active" bson:"active"`
+	Launch   *am.Date        `json:"launch" bson:"launch"`
+	Listed   time.Time       `json:"listed" bson:"listed"`
+	Image    *[]byte         `json:"image" bson:"image"`
+	Attrs    am.JSON         `json:"attrs" bson:"attrs"`
+	Tags     am.List[string] `json:"tags" bson:"tags"`
+	Sizes    am.List[int]    `json:"sizes" bson:"sizes"`
+	Status   ProductStatus   `json:"status" bson:"status"`
+	Tier     *ProductTier    `json:"tier" bson:"tier"`
+}
+
+// NewProduct returns a Product with a fresh ID and the spec defaults.
+func NewProduct() *Product {
+	return &Product{ID: uuid.New(), Price: "0.00", Stock: am.Ptr[int](0), Active: true, Listed: time.Now().UTC(), Status: ProductStatusDraft}
+}
+
+// ProductStatus enumerates the values of Product.status.
+type ProductStatus string
+
+// ProductStatus values.
+const (
+	ProductStatusDraft     ProductStatus = "draft"
+	ProductStatusPublished ProductStatus = "published"
+	ProductStatusArchived  ProductStatus = "archived"
+)
+
+// Valid reports whether s is one of the ProductStatus values.
+func (s ProductStatus) Valid() bool {
+	switch s {
+	case ProductStatusDraft, ProductStatusPublished, ProductStatusArchived:
+		return true
+	}
+	return false
+}
+
+// Scan implements sql.Scanner, rejecting values outside ProductStatus.
+func (s *ProductStatus) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		*s = ProductStatus(v)
+	case []byte:
+		*s = ProductStatus(v)
+	default:
+		return fmt.Errorf("cannot scan %T into ProductStatus", src)
+	}
+	if !s.Valid() {
+		return fmt.Errorf("invalid ProductStatus %q", string(*s))
+	}
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (s ProductStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// ProductTier enumerates the values of Product.tier.
+type ProductTier string
+
+// ProductTier values.
+const (
+	ProductTierBasic    ProductTier = "basic"
+	ProductTierInReview ProductTier = "in_review"
+)
+
+// Valid reports whether s is one of the ProductTier values.
+func (s ProductTier) Valid() bool {
+	switch s {
+	case ProductTierBasic, ProductTierInReview:
+		return true
+	}
+	return false
+}
+
+// Scan implements sql.Scanner, rejecting values outside ProductTier.
+func (s *ProductTier) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		*s = ProductTier(v)
+	case []byte:
+		*s = ProductTier(v)
+	default:
+		return fmt.Errorf("cannot scan %T into ProductTier", src)
+	}
+	if !s.Valid() {
+		return fmt.Errorf("invalid ProductTier %q", string(*s))
+	}
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (s ProductTier) Value() (driver.Value, error) {
+	return string(s), nil
+}
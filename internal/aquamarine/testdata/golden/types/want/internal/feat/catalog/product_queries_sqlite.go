@@ -0,0 +1,10 @@
+package catalog
+
+// SQL statements used by ProductSQLiteRepo.
+const (
+	insertProductSQL = `INSERT INTO products (id, name, site, price, discount, stock, active, launch, listed, image, attrs, tags, sizes, status, tier) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	selectProductSQL = `SELECT id, name, site, price, discount, stock, active, launch, listed, image, attrs, tags, sizes, status, tier FROM products WHERE id = ?`
+	listProductSQL   = `SELECT id, name, site, price, discount, stock, active, launch, listed, image, attrs, tags, sizes, status, tier FROM products ORDER BY id`
+	updateProductSQL = `UPDATE products SET name = ?, site = ?, price = ?, discount = ?, stock = ?, active = ?, launch = ?, listed = ?, image = ?, attrs = ?, tags = ?, sizes = ?, status = ?, tier = ? WHERE id = ?`
+	deleteProductSQL = `DELETE FROM products WHERE id = ?`
+)
@@ -0,0 +1,48 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// ProductValidator checks Product values before they are stored.
+type ProductValidator struct{}
+
+// NewProductValidator creates a ProductValidator.
+func NewProductValidator() *ProductValidator {
+	return &ProductValidator{}
+}
+
+// Validate returns every rule m breaks.
+func (v *ProductValidator) Validate(ctx context.Context, m *Product) am.ValidationErrors {
+	var errs am.ValidationErrors
+	if !(am.IsRequired(m.Name)) {
+		errs = append(errs, am.ValidationError{Field: "name", Code: "required", Message: "name is required"})
+	}
+	if !(am.MaxLength(m.Name, 80)) {
+		errs = append(errs, am.ValidationError{Field: "name", Code: "max", Message: "name must be at most 80 characters"})
+	}
+	if !(m.Site == nil || am.MaxLength(*m.Site, 200)) {
+		errs = append(errs, am.ValidationError{Field: "site", Code: "max_length", Message: "site must be at most 200 characters"})
+	}
+	if !(m.Price.Cmp("0") >= 0) {
+		errs = append(errs, am.ValidationError{Field: "price", Code: "min", Message: "price must be at least 0"})
+	}
+	if !(m.Discount == nil || m.Discount.Cmp("100") <= 0) {
+		errs = append(errs, am.ValidationError{Field: "discount", Code: "max", Message: "discount must be at most 100"})
+	}
+	if !(m.Stock == nil || *m.Stock >= 0) {
+		errs = append(errs, am.ValidationError{Field: "stock", Code: "min", Message: "stock must be at least 0"})
+	}
+	if !(len(m.Tags) <= 5) {
+		errs = append(errs, am.ValidationError{Field: "tags", Code: "max", Message: "tags must have at most 5 items"})
+	}
+	if !(m.Status.Valid()) {
+		errs = append(errs, am.ValidationError{Field: "status", Code: "oneof", Message: "status must be one of draft, published, archived"})
+	}
+	if !(m.Tier == nil || m.Tier.Valid()) {
+		errs = append(errs, am.ValidationError{Field: "tier", Code: "oneof", Message: "tier must be one of basic, in_review"})
+	}
+	return errs
+}
@@ -0,0 +1,21 @@
+package catalog
+
+// Service exposes the catalog use cases.
+type Service interface {
+	ProductRepo
+}
+
+// service implements Service. The use cases below are stubs to fill in; code
+// between aquamarine:user and aquamarine:end markers survives regeneration,
+// the imports they need included.
+type service struct {
+	ProductRepo
+	deps Deps
+}
+
+func newService(deps Deps) *service {
+	return &service{
+		ProductRepo: deps.ProductRepo,
+		deps:        deps,
+	}
+}
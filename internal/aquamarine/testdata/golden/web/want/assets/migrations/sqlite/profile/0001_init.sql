@@ -0,0 +1,7 @@
+-- profile: initial schema
+
+CREATE TABLE IF NOT EXISTS profiles (
+	id TEXT PRIMARY KEY,
+	name TEXT NOT NULL,
+	bio TEXT NOT NULL
+);
@@ -0,0 +1,89 @@
+package profile
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"example.com/portal/internal/platform"
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// Errors returned by the profile feat.
+var (
+	ErrNotFound       = errors.New("profile: not found")
+	ErrNotImplemented = errors.New("profile: not implemented")
+)
+
+// Deps lists what the profile feat needs from the rest of the app.
+type Deps struct {
+	ProfileRepo ProfileRepo
+}
+
+// Feature wires the profile handlers. It is registered through am.Setup.
+type Feature struct {
+	xp             platform.XParams
+	deps           Deps
+	svc            Service
+	profileHandler *ProfileHandler
+}
+
+// New builds the profile feat from its dependencies.
+func New(xp platform.XParams, deps Deps) (*Feature, error) {
+	f := &Feature{xp: xp, deps: deps}
+	f.svc = newService(deps)
+	f.profileHandler = NewProfileHandler(deps.ProfileRepo, xp)
+	return f, nil
+}
+
+// Service returns the profile use cases for other feats to consume.
+func (f *Feature) Service() Service {
+	return f.svc
+}
+
+// RegisterAPIRoutes mounts the profile JSON endpoints.
+func (f *Feature) RegisterAPIRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Route("/profile/profiles", f.profileHandler.Routes)
+		r.Get("/me", f.handleGetMe)
+	})
+}
+
+// handleGetMe serves GetMe over JSON.
+func (f *Feature) handleGetMe(w http.ResponseWriter, r *http.Request) {
+	var req GetMeRequest
+	res, err := f.svc.GetMe(r.Context(), req)
+	if err != nil {
+		writeError(w, f.xp.Log, err)
+		return
+	}
+	am.Respond(w, http.StatusOK, res, nil)
+}
+
+// writeError maps feat errors to JSON error responses.
+func writeError(w http.ResponseWriter, log am.Logger, err error) {
+	var verrs am.ValidationErrors
+	switch {
+	case errors.As(err, &verrs):
+		am.Error(w, http.StatusUnprocessableEntity, "validation_failed", "Validation failed", verrs...)
+	case errors.Is(err, ErrNotFound):
+		am.Error(w, http.StatusNotFound, "not_found", "Resource not found")
+	case errors.Is(err, ErrNotImplemented):
+		am.Error(w, http.StatusNotImplemented, "not_implemented", "Not implemented")
+	default:
+		log.Error("request failed: ", err)
+		am.Error(w, http.StatusInternalServerError, "internal_error", "Internal error")
+	}
+}
+
+// parseID reads the {id} URL parameter.
+func parseID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		am.Error(w, http.StatusBadRequest, "invalid_id", "Invalid id")
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,10 @@
+package profile
+
+// SQL statements used by ProfileSQLiteRepo.
+const (
+	insertProfileSQL = `INSERT INTO profiles (id, name, bio) VALUES (?, ?, ?)`
+	selectProfileSQL = `SELECT id, name, bio FROM profiles WHERE id = ?`
+	listProfileSQL   = `SELECT id, name, bio FROM profiles ORDER BY id`
+	updateProfileSQL = `UPDATE profiles SET name = ?, bio = ? WHERE id = ?`
+	deleteProfileSQL = `DELETE FROM profiles WHERE id = ?`
+)
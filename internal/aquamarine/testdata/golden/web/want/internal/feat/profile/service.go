@@ -0,0 +1,47 @@
+package profile
+
+import (
+	"context"
+	// aquamarine:user imports
+	// aquamarine:end
+)
+
+// Service exposes the profile use cases.
+type Service interface {
+	ProfileRepo
+	GetMe(ctx context.Context, req GetMeRequest) (GetMeResponse, error)
+}
+
+// GetMeRequest is the input of GetMe.
+type GetMeRequest struct {
+	// aquamarine:user GetMeRequest
+	// aquamarine:end
+}
+
+// GetMeResponse is the output of GetMe.
+type GetMeResponse struct {
+	// aquamarine:user GetMeResponse
+	// aquamarine:end
+}
+
+// service implements Service. The use cases below are stubs to fill in; code
+// between aquamarine:user and aquamarine:end markers survives regeneration,
+// the imports they need included.
+type service struct {
+	ProfileRepo
+	deps Deps
+}
+
+func newService(deps Deps) *service {
+	return &service{
+		ProfileRepo: deps.ProfileRepo,
+		deps:        deps,
+	}
+}
+
+// GetMe implements Service.
+func (s *service) GetMe(ctx context.Context, req GetMeRequest) (GetMeResponse, error) {
+	// aquamarine:user GetMe
+	return GetMeResponse{}, ErrNotImplemented
+	// aquamarine:end
+}
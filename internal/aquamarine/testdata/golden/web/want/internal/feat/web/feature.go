@@ -0,0 +1,80 @@
+package web
+
+import (
+	"errors"
+	"html/template"
+	"io/fs"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"example.com/portal/internal/feat/profile"
+	"example.com/portal/internal/platform"
+)
+
+// Errors returned by the web feat.
+var (
+	ErrNotFound       = errors.New("web: not found")
+	ErrNotImplemented = errors.New("web: not implemented")
+)
+
+// Deps lists what the web feat needs from the rest of the app.
+type Deps struct {
+	Assets  fs.FS
+	Profile profile.Service
+}
+
+// Feature wires the web handlers. It is registered through am.Setup.
+type Feature struct {
+	xp    platform.XParams
+	deps  Deps
+	pages *template.Template
+}
+
+// New builds the web feat from its dependencies.
+func New(xp platform.XParams, deps Deps) (*Feature, error) {
+	f := &Feature{xp: xp, deps: deps}
+	pages, err := template.ParseFS(deps.Assets, "assets/templates/web/*.html")
+	if err != nil {
+		return nil, err
+	}
+	f.pages = pages
+	return f, nil
+}
+
+// RegisterWebRoutes mounts the web pages.
+func (f *Feature) RegisterWebRoutes(r chi.Router) {
+	r.Get("/", f.pageIndex)
+	r.Get("/settings", f.pageSettings)
+	r.Post("/settings", f.pagePostSettings)
+}
+
+// pageIndex renders assets/templates/web/index.html.
+// Uses: profile.
+func (f *Feature) pageIndex(w http.ResponseWriter, r *http.Request) {
+	data := map[string]any{"Title": "Index"}
+	if err := f.pages.ExecuteTemplate(w, "index.html", data); err != nil {
+		f.xp.Log.Error("cannot render index: ", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
+
+// pageSettings renders assets/templates/web/settings.html.
+// Uses: profile.
+func (f *Feature) pageSettings(w http.ResponseWriter, r *http.Request) {
+	data := map[string]any{"Title": "Settings"}
+	if err := f.pages.ExecuteTemplate(w, "settings.html", data); err != nil {
+		f.xp.Log.Error("cannot render settings: ", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
+
+// pagePostSettings renders assets/templates/web/post_settings.html.
+// Uses: profile.
+func (f *Feature) pagePostSettings(w http.ResponseWriter, r *http.Request) {
+	data := map[string]any{"Title": "PostSettings"}
+	if err := f.pages.ExecuteTemplate(w, "post_settings.html", data); err != nil {
+		f.xp.Log.Error("cannot render post_settings: ", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
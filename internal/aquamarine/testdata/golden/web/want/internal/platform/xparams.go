@@ -0,0 +1,9 @@
+package platform
+
+import "github.com/aquamarinepk/aquamarine/pkg/lib/am"
+
+// XParams carries the cross-cutting dependencies handed to every feat.
+type XParams struct {
+	Cfg *Config
+	Log am.Logger
+}
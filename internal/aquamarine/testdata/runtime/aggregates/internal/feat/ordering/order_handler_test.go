@@ -0,0 +1,104 @@
+package ordering
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	_ "modernc.org/sqlite"
+
+	"example.com/orders/internal/platform"
+	"github.com/aquamarinepk/aquamarine/pkg/lib/am"
+)
+
+// TestOrderUpdate runs the Order API of the aggregates golden case against
+// SQLite: audit fields of children come from the stored Order, never from a
+// request, and an update from a stale revision is a 409.
+func TestOrderUpdate(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", "file:"+filepath.Join(t.TempDir(), "orders.db")+"?_pragma=foreign_keys(1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := am.MigrateSQL(ctx, db, os.DirFS("../../.."), "assets/migrations/sqlite", "ordering"); err != nil {
+		t.Fatal(err)
+	}
+	r := chi.NewRouter()
+	NewOrderHandler(NewOrderSQLiteRepo(db), platform.XParams{Log: am.NewLogger("error")}).Routes(r)
+	do := func(method, path string, body any) (int, Order) {
+		t.Helper()
+		b, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(method, path, bytes.NewReader(b)))
+		var res struct{ Data Order }
+		if rec.Code < 300 {
+			if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+				t.Fatalf("%s %s: %v", method, path, err)
+			}
+		}
+		return rec.Code, res.Data
+	}
+
+	forger := uuid.New()
+	long := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	code, created := do(http.MethodPost, "/", Order{
+		Customer: "ann",
+		Notes:    []Note{{Body: "ring twice", CreatedAt: long, CreatedBy: forger}},
+	})
+	if code != http.StatusCreated {
+		t.Fatalf("create: %d", code)
+	}
+	note := created.Notes[0]
+	if note.CreatedBy == forger || note.CreatedAt.Equal(long) {
+		t.Errorf("create kept the note's audit fields from the request: %+v", note)
+	}
+
+	edit := created
+	edit.Customer = "bob"
+	edit.Notes = []Note{{ID: note.ID, Body: note.Body, CreatedBy: forger}, {Body: "leave at the door"}}
+	path := "/" + created.ID.String()
+	code, updated := do(http.MethodPut, path, edit)
+	if code != http.StatusOK {
+		t.Fatalf("update: %d", code)
+	}
+	if updated.Revision != created.Revision+1 {
+		t.Errorf("update: revision %d, want %d", updated.Revision, created.Revision+1)
+	}
+	if got := updated.Notes[0]; got.CreatedBy != note.CreatedBy || !got.CreatedAt.Equal(note.CreatedAt) || !got.UpdatedAt.Equal(note.UpdatedAt) {
+		t.Errorf("update changed the audit fields of an unchanged note: %+v, stored %+v", got, note)
+	}
+	if updated.Notes[1].CreatedAt.IsZero() {
+		t.Error("update: added note has no creation time")
+	}
+
+	edit = updated
+	edit.Notes[0].Body = "knock"
+	code, updated = do(http.MethodPut, path, edit)
+	if code != http.StatusOK {
+		t.Fatalf("update: %d", code)
+	}
+	if got := updated.Notes[0]; !got.CreatedAt.Equal(note.CreatedAt) || !got.UpdatedAt.After(note.UpdatedAt) {
+		t.Errorf("update of a note: %+v, want its creation time kept and a new update time", got)
+	}
+
+	edit.Revision = created.Revision
+	if code, _ := do(http.MethodPut, path, edit); code != http.StatusConflict {
+		t.Errorf("update from stale revision %d: %d, want %d", edit.Revision, code, http.StatusConflict)
+	}
+	if code, _ := do(http.MethodPut, "/"+uuid.NewString(), edit); code != http.StatusNotFound {
+		t.Errorf("update of a missing order: %d, want %d", code, http.StatusNotFound)
+	}
+}
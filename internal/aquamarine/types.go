@@ -0,0 +1,274 @@
+package aquamarine
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Value classes group field types that validate, default and compare alike.
+const (
+	classString  = "string"
+	classNumber  = "number"
+	classBool    = "bool"
+	classUUID    = "uuid"
+	classDecimal = "decimal"
+	classDate    = "date"
+	classTime    = "time"
+	classBytes   = "bytes"
+	classJSON    = "json"
+	classList    = "list"
+	classEnum    = "enum"
+)
+
+// fieldType is how a spec field type maps onto the generated code and each
+// SQL engine. JSON and BSON follow from the Go type (see yaml.md, Field types).
+type fieldType struct {
+	name     string
+	goType   string
+	class    string
+	sqlite   string
+	postgres string
+}
+
+// fieldTypeTable lists every spec field type, in the order docs and errors
+// show them.
+var fieldTypeTable = []fieldType{
+	{"text", "string", classString, "TEXT", "TEXT"},
+	{"string", "string", classString, "TEXT", "TEXT"},
+	{"email", "string", classString, "TEXT", "TEXT"},
+	{"url", "string", classString, "TEXT", "TEXT"},
+	{"bool", "bool", classBool, "INTEGER", "BOOLEAN"},
+	{"uuid", "uuid.UUID", classUUID, "TEXT", "UUID"},
+	{"int", "int", classNumber, "INTEGER", "BIGINT"},
+	{"int64", "int64", classNumber, "INTEGER", "BIGINT"},
+	{"float64", "float64", classNumber, "REAL", "DOUBLE PRECISION"},
+	{"decimal", "am.Decimal", classDecimal, "TEXT", "NUMERIC"},
+	{"date", "am.Date", classDate, "DATE", "DATE"},
+	{"datetime", "time.Time", classTime, "TIMESTAMP", "TIMESTAMPTZ"},
+	{"time", "time.Time", classTime, "TIMESTAMP", "TIMESTAMPTZ"},
+	{"bytes", "[]byte", classBytes, "BLOB", "BYTEA"},
+	{"json", "am.JSON", classJSON, "TEXT", "JSONB"},
+	{"list", "am.List", classList, "TEXT", "JSONB"},
+	{"enum", "", classEnum, "TEXT", "TEXT"},
+}
+
+// fieldTypeNames returns the spec names of the field types.
+func fieldTypeNames() []string {
+	names := make([]string, len(fieldTypeTable))
+	for i, t := range fieldTypeTable {
+		names[i] = t.name
+	}
+	return names
+}
+
+// listElemTypes are the types a list field may hold (its of key).
+func listElemTypes() []string {
+	var names []string
+	for _, t := range fieldTypeTable {
+		switch t.class {
+		case classString, classNumber, classBool, classUUID, classDecimal, classDate, classTime:
+			names = append(names, t.name)
+		}
+	}
+	return names
+}
+
+func lookupFieldType(name string) (fieldType, bool) {
+	for _, t := range fieldTypeTable {
+		if t.name == name {
+			return t, true
+		}
+	}
+	return fieldType{}, false
+}
+
+// mapGoType maps YAML types to Go types.
+func mapGoType(yamlType string) string {
+	if t, ok := lookupFieldType(yamlType); ok {
+		return t.goType
+	}
+	return "any"
+}
+
+// goFieldType is the Go type of f: lists are typed by their elements, enums
+// by the type generated for them (enumType) and nullable fields are
+// pointers, nil standing for null.
+func goFieldType(f Field, enum string) string {
+	goType := mapGoType(f.Type)
+	switch f.Type {
+	case "list":
+		goType += "[" + mapGoType(f.Of) + "]"
+	case "enum":
+		goType = enum
+	}
+	if f.Nullable {
+		return "*" + goType
+	}
+	return goType
+}
+
+// columnType is the column type of a spec field type in engine.
+func columnType(engine, yamlType string) string {
+	t, ok := lookupFieldType(yamlType)
+	switch {
+	case !ok:
+		return "TEXT"
+	case engine == "postgres":
+		return t.postgres
+	default:
+		return t.sqlite
+	}
+}
+
+// sqlColumn renders the definition of f's column after its name in engine:
+// type, nullability, default, uniqueness, the table a foreign key references
+// and the values an enum allows.
+func sqlColumn(engine string, f FieldTemplateData) string {
+	def := columnType(engine, f.Spec.Type)
+	if !f.Spec.Nullable {
+		def += " NOT NULL"
+	}
+	if f.Spec.Default != "" {
+		def += " DEFAULT " + sqlDefault(f.Spec)
+	}
+	if f.Unique {
+		def += " UNIQUE"
+	}
+	if f.References != "" {
+		def += " REFERENCES " + sqlIdent(engine, f.References) + " (id)"
+		if f.Spec.Nullable {
+			def += " ON DELETE SET NULL"
+		}
+	}
+	if f.Spec.Type == "enum" && len(f.Spec.Values) > 0 {
+		values := make([]string, len(f.Spec.Values))
+		for i, v := range f.Spec.Values {
+			values[i] = sqlString(v)
+		}
+		def += " CHECK (" + sqlIdent(engine, f.Column) + " IN (" + strings.Join(values, ", ") + "))"
+	}
+	return def
+}
+
+func sqlString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// sqlDefault is the SQL literal of f's default, valid in every engine.
+// Decimals are quoted so a TEXT column keeps their digits.
+func sqlDefault(f Field) string {
+	t, _ := lookupFieldType(f.Type)
+	switch t.class {
+	case classNumber:
+		return f.Default
+	case classBool:
+		b, _ := strconv.ParseBool(f.Default)
+		return strings.ToUpper(strconv.FormatBool(b))
+	case classTime:
+		return "CURRENT_TIMESTAMP"
+	default:
+		return sqlString(f.Default)
+	}
+}
+
+// goDefault is the Go expression the model constructor sets f to, or "" when
+// f has no default. enum is the Go type of an enum field.
+func goDefault(f Field, enum string) string {
+	if f.Default == "" {
+		return ""
+	}
+	t, _ := lookupFieldType(f.Type)
+	goType := t.goType
+	var v string
+	switch t.class {
+	case classEnum:
+		goType, v = enum, enumConst(enum, f.Default)
+	case classNumber:
+		v = f.Default
+	case classBool:
+		b, _ := strconv.ParseBool(f.Default)
+		v = strconv.FormatBool(b)
+	case classTime:
+		v = "time.Now().UTC()"
+	default:
+		v = strconv.Quote(f.Default)
+	}
+	if f.Nullable {
+		return "am.Ptr[" + goType + "](" + v + ")"
+	}
+	return v
+}
+
+// enumType names the Go type of enum field of model: ArticleStatus.
+func enumType(model, field string) string {
+	return model + pascal(field)
+}
+
+// enumConst names the constant of value in enum type typ: ArticleStatusDraft.
+func enumConst(typ, value string) string {
+	return typ + pascal(value)
+}
+
+var decimalRe = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+
+// checkDefault reports why value cannot be the default of a field of type
+// yamlType, or "" when it can. Instants only default to now.
+func checkDefault(yamlType, value string) string {
+	t, ok := lookupFieldType(yamlType)
+	if !ok {
+		return ""
+	}
+	var err error
+	switch t.class {
+	case classString:
+		return ""
+	case classBool:
+		_, err = strconv.ParseBool(value)
+	case classNumber:
+		if t.goType == "float64" {
+			_, err = strconv.ParseFloat(value, 64)
+		} else {
+			_, err = strconv.ParseInt(value, 10, 64)
+		}
+	case classDecimal:
+		if !decimalRe.MatchString(value) {
+			return "want a decimal number"
+		}
+	case classDate:
+		_, err = time.Parse(time.DateOnly, value)
+	case classTime:
+		if value != "now" {
+			return "want now"
+		}
+	case classEnum:
+		return "" // checked against values by checkField
+	default:
+		return "type " + yamlType + " takes no default"
+	}
+	if err != nil {
+		return "not a valid " + yamlType
+	}
+	return ""
+}
+
+// validationApplies tells whether validation rule can check a field of type
+// yamlType.
+func validationApplies(rule, yamlType string) bool {
+	t, ok := lookupFieldType(yamlType)
+	if !ok {
+		return true
+	}
+	switch rule {
+	case "email", "pattern":
+		return t.class == classString
+	case "min", "max":
+		return t.class == classString || t.class == classNumber || t.class == classDecimal || t.class == classList
+	case "min_length", "max_length":
+		return t.class == classString || t.class == classList
+	case "unique":
+		return t.class != classList && t.class != classJSON
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package aquamarine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFieldTypes(t *testing.T) {
+	tests := []struct {
+		field            Field
+		goType, column   string
+		goDefault        string
+		sqliteDefinition string
+	}{
+		{Field{Type: "string"}, "string", "TEXT", "", "TEXT NOT NULL"},
+		{Field{Type: "text", Nullable: true, Default: "it's"}, "*string", "TEXT", `am.Ptr[string]("it's")`, "TEXT DEFAULT 'it''s'"},
+		{Field{Type: "int64", Default: "7"}, "int64", "BIGINT", "7", "INTEGER NOT NULL DEFAULT 7"},
+		{Field{Type: "bool", Default: "true"}, "bool", "BOOLEAN", "true", "INTEGER NOT NULL DEFAULT TRUE"},
+		{Field{Type: "decimal", Default: "9.90"}, "am.Decimal", "NUMERIC", `"9.90"`, "TEXT NOT NULL DEFAULT '9.90'"},
+		{Field{Type: "datetime", Nullable: true, Default: "now"}, "*time.Time", "TIMESTAMPTZ", "am.Ptr[time.Time](time.Now().UTC())", "TIMESTAMP DEFAULT CURRENT_TIMESTAMP"},
+		{Field{Type: "list", Of: "uuid"}, "am.List[uuid.UUID]", "JSONB", "", "TEXT NOT NULL"},
+		{Field{Type: "enum", Values: []string{"new", "in_use"}, Nullable: true, Default: "in_use"}, "*ItemState", "TEXT", "am.Ptr[ItemState](ItemStateInUse)", "TEXT DEFAULT 'in_use' CHECK (f IN ('new', 'in_use'))"},
+	}
+	for _, tt := range tests {
+		if got := goFieldType(tt.field, "ItemState"); got != tt.goType {
+			t.Errorf("%+v: Go type %q, want %q", tt.field, got, tt.goType)
+		}
+		if got := columnType("postgres", tt.field.Type); got != tt.column {
+			t.Errorf("%+v: postgres column %q, want %q", tt.field, got, tt.column)
+		}
+		if got := goDefault(tt.field, "ItemState"); got != tt.goDefault {
+			t.Errorf("%+v: Go default %q, want %q", tt.field, got, tt.goDefault)
+		}
+		if got := sqlColumn("sqlite", FieldTemplateData{Column: "f", Spec: tt.field}); got != tt.sqliteDefinition {
+			t.Errorf("%+v: column %q, want %q", tt.field, got, tt.sqliteDefinition)
+		}
+	}
+}
+
+func TestFieldTypeErrors(t *testing.T) {
+	tests := []struct {
+		field, path, want string
+	}{
+		{"{type: list}", "fields.f.of", "of is required"},
+		{"{type: list, of: json}", "fields.f.of", `unknown list element type "json"`},
+		{"{type: string, of: int}", "fields.f.of", "of only applies to list fields, not string"},
+		{"{type: int, default: many}", "fields.f.default", `invalid default "many": not a valid int`},
+		{"{type: date, default: 2024-13-01}", "fields.f.default", "not a valid date"},
+		{"{type: datetime, default: 2024-01-01}", "fields.f.default", "want now"},
+		{"{type: json, default: '{}'}", "fields.f.default", "type json takes no default"},
+		{"{type: enum}", "fields.f.values", "values is required"},
+		{"{type: enum, values: [a-b, a_b]}", "fields.f.values[1]", `enum value "a_b" clashes with "a-b" (both are AB)`},
+		{"{type: enum, values: [1st]}", "fields.f.values[0]", "does not make a Go identifier"},
+		{"{type: enum, values: [a, b], default: c}", "fields.f.default", `invalid default "c": want one of a|b`},
+		{"{type: string, values: [a]}", "fields.f.values", "values only apply to enum fields, not string"},
+		{"{type: bool, validations: [{min: 1}]}", "fields.f.validations[0]", `validation "min" does not apply to bool fields`},
+		{"{type: list, of: string, validations: [unique]}", "fields.f.validations[0]", `validation "unique" does not apply to list fields`},
+	}
+	for _, tt := range tests {
+		spec := "version: 0.2\nproject: {name: shop, module: example.com/shop}\nruntime: {http: {api: {port: 8081}, web: {port: 8080}}}\n" +
+			"feats:\n  - name: shop\n    models:\n      Item:\n        fields:\n          f: " + tt.field + "\n"
+		_, diags := ParseSpec("aquamarine.yaml", []byte(spec))
+		errs := diags.Errors()
+		if len(errs) != 1 || !strings.HasSuffix(errs[0].Path, tt.path) || !strings.Contains(errs[0].Message, tt.want) {
+			t.Errorf("%s: got %v, want %s: %s", tt.field, diags, tt.path, tt.want)
+		}
+	}
+}
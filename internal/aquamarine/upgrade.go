@@ -0,0 +1,304 @@
+package aquamarine
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SpecVersion is the spec format version this aquamarine writes. Specs at an
+// older version listed in specUpgrades are still read, with a warning, and
+// upgrade rewrites them; any other version is refused.
+const SpecVersion = "0.2"
+
+// specUpgrade rewrites a spec from one format version to the next. apply
+// edits the spec itself and, separately, every feat file it includes.
+type specUpgrade struct {
+	from, to string
+	apply    func(e *SpecEditor, included bool)
+}
+
+// specUpgrades chain from the oldest readable version to SpecVersion.
+var specUpgrades = []specUpgrade{
+	{from: "0.1", to: "0.2", apply: upgradeTo02},
+}
+
+// specMinor returns the MAJOR.MINOR part of a version: the format does not
+// change within a minor version.
+func specMinor(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	minor, _, _ := strings.Cut(parts[1], "-")
+	return parts[0] + "." + minor
+}
+
+// compareSpecVersions compares two MAJOR.MINOR versions like cmp.Compare.
+func compareSpecVersions(a, b string) int {
+	num := func(v string) (int, int) {
+		major, minor, _ := strings.Cut(specMinor(v), ".")
+		x, _ := strconv.Atoi(major)
+		y, _ := strconv.Atoi(minor)
+		return x, y
+	}
+	am, an := num(a)
+	bm, bn := num(b)
+	if am != bm {
+		return am - bm
+	}
+	return an - bn
+}
+
+// upgradesFrom returns the transforms taking version to SpecVersion, or an
+// error for a version this aquamarine cannot read.
+func upgradesFrom(version string) ([]specUpgrade, error) {
+	v := specMinor(version)
+	if v == SpecVersion {
+		return nil, nil
+	}
+	for i, u := range specUpgrades {
+		if u.from == v {
+			return specUpgrades[i:], nil
+		}
+	}
+	if compareSpecVersions(v, SpecVersion) > 0 {
+		return nil, fmt.Errorf("spec version %s is newer than this aquamarine supports (%s): update aquamarine", version, SpecVersion)
+	}
+	return nil, fmt.Errorf("unsupported spec version %s: this aquamarine reads %s", version, readableVersions())
+}
+
+func readableVersions() string {
+	var vs []string
+	for _, u := range specUpgrades {
+		vs = append(vs, u.from)
+	}
+	return strings.Join(append(vs, SpecVersion), ", ")
+}
+
+// UpgradeOptions controls an upgrade run.
+type UpgradeOptions struct {
+	SpecFile string // defaults to DefaultSpecFile
+	DryRun   bool   // print the diff without writing
+}
+
+// Upgrade rewrites the spec, and the feat files it includes, from its version
+// to SpecVersion, one version at a time, and writes a unified diff of the
+// changes to w. Edits are spliced into the text like those of add, so
+// comments and layout are kept. Nothing is written when the upgraded spec
+// does not validate.
+func Upgrade(w io.Writer, opts UpgradeOptions) error {
+	specFile := opts.SpecFile
+	if specFile == "" {
+		specFile = DefaultSpecFile
+	}
+	orig := map[string][]byte{}
+	read := func(file string) error {
+		b, err := os.ReadFile(file)
+		orig[file] = b
+		return err
+	}
+	if err := read(specFile); err != nil {
+		return err
+	}
+	e, err := newSpecEditor(specFile, orig[specFile])
+	if err != nil {
+		return err
+	}
+	version := scalarValue(e.root, "version")
+	if version == "" {
+		return fmt.Errorf("%s: version is required", specFile)
+	}
+	upgrades, err := upgradesFrom(version)
+	if err != nil {
+		return fmt.Errorf("%s: %w", specFile, err)
+	}
+	if len(upgrades) == 0 {
+		fmt.Fprintf(w, "%s is at spec version %s, nothing to upgrade\n", specFile, SpecVersion)
+		return nil
+	}
+
+	// Included feat files carry no version: they follow the spec's.
+	files := []string{specFile}
+	if cfg, _ := readSpec(specFile, specOptions{optionalEnv: true}); cfg != nil {
+		for _, name := range declared(cfg.Source, "feats", cfg.Feats) {
+			if p := cfg.Source[join("feats", name)]; p.File != "" && !slices.Contains(files, p.File) {
+				if err := read(p.File); err != nil {
+					return err
+				}
+				files = append(files, p.File)
+			}
+		}
+	}
+
+	edited := map[string][]byte{}
+	for file, b := range orig {
+		edited[file] = b
+	}
+	for _, u := range upgrades {
+		for i, file := range files {
+			fe, err := newSpecEditor(file, edited[file])
+			if err != nil {
+				return err
+			}
+			u.apply(fe, i > 0)
+			if i == 0 {
+				fe.setVersion(u.to)
+			}
+			edited[file] = fe.Bytes()
+		}
+	}
+
+	cfg, diags := readSpec(specFile, specOptions{edited: edited, optionalEnv: true})
+	if cfg != nil {
+		diags = append(diags, CheckSpec(cfg)...)
+	}
+	if diags.HasErrors() {
+		sortDiagnostics(diags)
+		return fmt.Errorf("upgraded spec is invalid, nothing written: %w", diags.Errors())
+	}
+	for _, file := range files {
+		if err := unifiedDiff(w, "a/"+file, "b/"+file, orig[file], edited[file]); err != nil {
+			return err
+		}
+	}
+	if opts.DryRun {
+		return nil
+	}
+	for _, file := range files {
+		if err := os.WriteFile(file, edited[file], 0o644); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(w, "Upgraded %s from spec version %s to %s\n", specFile, version, SpecVersion)
+	return nil
+}
+
+// setVersion rewrites the version value in place.
+func (e *SpecEditor) setVersion(version string) {
+	if _, v := lookup(e.root, "version"); v != nil {
+		v.Value = version
+		e.rewrite(v)
+	}
+}
+
+// upgradeTo02 settles on one form for each 0.1 shorthand: feats is a list,
+// a single model: is an entry of models: and a feat's top level pages: go
+// under web.pages.
+func upgradeTo02(e *SpecEditor, included bool) {
+	if included {
+		e.upgradeFeatTo02(e.root)
+		return
+	}
+	_, feats := lookup(e.root, "feats")
+	switch {
+	case feats == nil:
+	case feats.Kind == yaml.MappingNode:
+		items := make([]*yaml.Node, 0, len(feats.Content)/2)
+		for i := 0; i+1 < len(feats.Content); i += 2 {
+			k, f := feats.Content[i], resolve(feats.Content[i+1])
+			if f == nil || isNull(f) {
+				f = mappingNode(blockStyle)
+			}
+			if _, name := lookup(f, "name"); name == nil {
+				withName(k.Value, f)
+				f.HeadComment, f.Content[1].LineComment = k.HeadComment, k.LineComment
+			}
+			featTo02(f)
+			items = append(items, f)
+		}
+		feats.Kind, feats.Tag, feats.Content = yaml.SequenceNode, "!!seq", items
+		e.rewrite(feats)
+	case feats.Kind == yaml.SequenceNode:
+		for _, item := range feats.Content {
+			e.upgradeFeatTo02(resolve(item))
+		}
+	}
+}
+
+// upgradeFeatTo02 rewrites the entries featTo02 changed, or the whole feat
+// when it is a flow mapping or entries were merged.
+func (e *SpecEditor) upgradeFeatTo02(f *yaml.Node) {
+	if f == nil || f.Kind != yaml.MappingNode {
+		return
+	}
+	changed := featTo02(f)
+	switch {
+	case len(changed) == 0:
+	case f.Style&yaml.FlowStyle != 0 || slices.Contains(changed, f):
+		if f == e.root {
+			e.replace(1, len(e.lines), "", encodeNode(f))
+			return
+		}
+		e.rewrite(f)
+	default:
+		for _, n := range changed {
+			e.rewrite(n)
+		}
+	}
+}
+
+// featTo02 applies the 0.2 forms to the feat mapping f and returns the
+// values it replaced, or f itself when it removed entries.
+func featTo02(f *yaml.Node) []*yaml.Node {
+	var changed []*yaml.Node
+	if k, model := lookup(f, "model"); model != nil {
+		if nk, nv := lookup(model, "name"); nv != nil {
+			key := scalarNode(nv.Value)
+			key.HeadComment, key.LineComment = nk.HeadComment, nv.LineComment
+			removeKey(model, "name")
+			named := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Style: model.Style, Content: []*yaml.Node{key, model}}
+			if _, models := lookup(f, "models"); models != nil && models.Kind == yaml.MappingNode {
+				models.Content = append(models.Content, named.Content...)
+				removeKey(f, "model")
+				changed = append(changed, f)
+			} else {
+				k.Value = "models"
+				setValue(f, k, named)
+				changed = append(changed, named)
+			}
+		}
+	}
+	if k, pages := lookup(f, "pages"); pages != nil {
+		_, web := lookup(f, "web")
+		switch {
+		case web == nil:
+			k.Value = "web"
+			wrapped := mappingNode(blockStyle, "pages", pages)
+			setValue(f, k, wrapped)
+			changed = append(changed, wrapped)
+		case web.Kind == yaml.MappingNode:
+			if _, existing := lookup(web, "pages"); existing != nil && existing.Kind == yaml.SequenceNode {
+				existing.Content = append(existing.Content, pages.Content...)
+			} else {
+				setKey(web, "pages", pages)
+			}
+			removeKey(f, "pages")
+			changed = append(changed, f)
+		}
+	}
+	return changed
+}
+
+// setValue replaces the value of key k in mapping m.
+func setValue(m, k, v *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i] == k {
+			m.Content[i+1] = v
+		}
+	}
+}
+
+func removeKey(m *yaml.Node, key string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return
+		}
+	}
+}
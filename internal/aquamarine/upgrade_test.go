@@ -0,0 +1,91 @@
+package aquamarine
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const spec01 = `version: 0.1
+project: {name: shop, module: example.com/shop}
+runtime: {http: {api: {port: 8081}, web: {port: 8080}}}
+include: [feats/*.yaml]
+feats:
+  # the storefront
+  web:
+    kind: web
+    pages: [{route: "GET /", uses: [auth, orders]}]
+  auth: {model: {name: User, fields: {email: {type: email}}}, models: {Role: {fields: {}}}}
+`
+
+func TestUpgrade(t *testing.T) {
+	dir := writeSpecFiles(t, map[string]string{
+		"aquamarine.yaml":   spec01,
+		"feats/orders.yaml": "name: orders\nkind: atom\nmodel:\n  name: Order # the order\n  fields: {total: {type: int}}\n",
+	})
+	spec := filepath.Join(dir, "aquamarine.yaml")
+	before, _, err := LoadSpec(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := Upgrade(&out, UpgradeOptions{SpecFile: spec, DryRun: true}); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(spec); string(b) != spec01 || !strings.Contains(out.String(), "+version: 0.2") {
+		t.Fatalf("dry run wrote the spec or printed no diff:\n%s", out.String())
+	}
+
+	if err := Upgrade(&out, UpgradeOptions{SpecFile: spec}); err != nil {
+		t.Fatal(err)
+	}
+	if diags := ValidateSpec(spec); len(diags) > 0 {
+		t.Fatalf("upgraded spec: %v", diags)
+	}
+	after, _, err := LoadSpec(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Version != SpecVersion || !reflect.DeepEqual(after.Feats, before.Feats) {
+		t.Errorf("upgrade changed the feats:\n%+v\nwant\n%+v", after.Feats, before.Feats)
+	}
+	b, _ := os.ReadFile(spec)
+	feat, _ := os.ReadFile(filepath.Join(dir, "feats", "orders.yaml"))
+	for _, want := range []string{"# the storefront\n  - name: web", "    web:\n      pages: [", "Order: # the order"} {
+		if !strings.Contains(string(b)+string(feat), want) {
+			t.Errorf("upgraded files lack %q:\n%s%s", want, b, feat)
+		}
+	}
+
+	out.Reset()
+	if err := Upgrade(&out, UpgradeOptions{SpecFile: spec}); err != nil || !strings.Contains(out.String(), "nothing to upgrade") {
+		t.Errorf("second upgrade: %v, %s", err, out.String())
+	}
+}
+
+func TestSpecVersions(t *testing.T) {
+	tests := []struct {
+		spec, want string
+	}{
+		{"version: 0.3\n", "newer than this aquamarine supports"},
+		{"version: 0.0\n", "unsupported spec version 0.0"},
+		{"version: 0.2\nfeats: {a: {}}\n", "feats keyed by name was replaced"},
+		{"version: 0.2\nfeats: [{name: a, model: {name: A}}]\n", "model was replaced by an entry of models"},
+		{"version: 0.2\nfeats: [{name: a, pages: []}]\n", "pages was replaced by web.pages"},
+	}
+	for _, tt := range tests {
+		_, diags := ParseSpec("aquamarine.yaml", []byte(tt.spec))
+		if !strings.Contains(diags.Errors().Error(), tt.want) {
+			t.Errorf("%q: got %v, want %q", tt.spec, diags, tt.want)
+		}
+	}
+
+	_, diags := ParseSpec("aquamarine.yaml", []byte(strings.Replace(spec01, "include: [feats/*.yaml]\n", "", 1)))
+	if diags.HasErrors() || len(diags) != 1 || !strings.Contains(diags[0].Message, "run aquamarine upgrade") {
+		t.Errorf("0.1 spec: got %v, want a single upgrade warning", diags)
+	}
+}
@@ -22,9 +22,9 @@ func NewConfig() Config {
 func BuildConfig(devMode bool, outputDir string, modulePath string) Config {
 	if outputDir == "" {
 		if devMode {
-			outputDir = "example/ref"
+			outputDir = "out/dev"
 		} else {
-			outputDir = "."
+			outputDir = "out/prod"
 		}
 	}
 	return Config{
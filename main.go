@@ -1,19 +1,21 @@
 package main
 
 import (
-	"embed"
+	"errors"
 	"fmt"
 	"os"
 
+	"github.com/aquamarinepk/aquamarine/assets"
 	"github.com/aquamarinepk/aquamarine/internal/aquamarine"
 )
 
-//go:embed assets
-var assetsFS embed.FS
-
 func main() {
-	app := aquamarine.NewApp(assetsFS)
+	app := aquamarine.NewApp(assets.Templates())
 	if err := app.Run(os.Args); err != nil {
+		var exit *aquamarine.ExitError
+		if errors.As(err, &exit) {
+			os.Exit(exit.Code)
+		}
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
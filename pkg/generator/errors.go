@@ -0,0 +1,166 @@
+package generator
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aquamarinepk/aquamarine/internal/aquamarine"
+)
+
+// ErrNoOutput is returned when Options has no Output.
+var ErrNoOutput = errors.New("generator: no output")
+
+// Severity classifies a diagnostic.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a single finding about a spec. Line and Column are 1-based;
+// zero means the position is unknown.
+type Diagnostic struct {
+	Rule     string   `json:"rule"`
+	File     string   `json:"file"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Path     string   `json:"path,omitempty"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	return aquamarine.Diagnostic{
+		Rule: d.Rule, File: d.File, Line: d.Line, Column: d.Column, Path: d.Path,
+		Severity: aquamarine.Severity(d.Severity), Message: d.Message,
+	}.String()
+}
+
+// Diagnostics is the error returned for an invalid spec, every finding in
+// file order.
+type Diagnostics []Diagnostic
+
+// Error renders one diagnostic per line.
+func (ds Diagnostics) Error() string {
+	lines := make([]string, 0, len(ds))
+	for _, d := range ds {
+		lines = append(lines, d.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func diagnostics(ds aquamarine.Diagnostics) Diagnostics {
+	out := make(Diagnostics, len(ds))
+	for i, d := range ds {
+		out[i] = Diagnostic{
+			Rule: d.Rule, File: d.File, Line: d.Line, Column: d.Column, Path: d.Path,
+			Severity: Severity(d.Severity), Message: d.Message,
+		}
+	}
+	return out
+}
+
+// TemplateError reports a template that does not parse, fails to execute or
+// renders invalid Go.
+type TemplateError struct {
+	Template string
+	File     string // output file being rendered, empty for parse errors
+	Err      error
+}
+
+func (e *TemplateError) Error() string {
+	return (*aquamarine.TemplateError)(e).Error()
+}
+
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// PluginError reports a plugin that failed or returned a file it may not
+// write.
+type PluginError struct {
+	Plugin string
+	File   string // offending file, empty when the plugin itself failed
+	Err    error
+}
+
+func (e *PluginError) Error() string {
+	return (*aquamarine.PluginError)(e).Error()
+}
+
+func (e *PluginError) Unwrap() error {
+	return e.Err
+}
+
+// ConflictError reports hand-edited files that conflict with the spec. Their
+// new content was written next to them, with a .new suffix.
+type ConflictError struct {
+	Files []string
+}
+
+func (e *ConflictError) Error() string {
+	return (*aquamarine.ConflictError)(e).Error()
+}
+
+// publicError returns err with the errors of the generator it is or wraps
+// turned into those of this package.
+func publicError(err error) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case aquamarine.Diagnostics:
+		return diagnostics(e)
+	case *aquamarine.TemplateError:
+		return (*TemplateError)(e)
+	case *aquamarine.PluginError:
+		return (*PluginError)(e)
+	case *aquamarine.ConflictError:
+		return (*ConflictError)(e)
+	}
+	return wrappedError{err}
+}
+
+// wrappedError is an error of the generator wrapping others; errors.As finds
+// them as the errors of this package.
+type wrappedError struct {
+	err error
+}
+
+func (e wrappedError) Error() string {
+	return e.err.Error()
+}
+
+func (e wrappedError) Unwrap() error {
+	return e.err
+}
+
+func (e wrappedError) As(target any) bool {
+	switch t := target.(type) {
+	case *Diagnostics:
+		var ds aquamarine.Diagnostics
+		if errors.As(e.err, &ds) {
+			*t = diagnostics(ds)
+			return true
+		}
+	case **TemplateError:
+		var te *aquamarine.TemplateError
+		if errors.As(e.err, &te) {
+			*t = (*TemplateError)(te)
+			return true
+		}
+	case **PluginError:
+		var pe *aquamarine.PluginError
+		if errors.As(e.err, &pe) {
+			*t = (*PluginError)(pe)
+			return true
+		}
+	case **ConflictError:
+		var ce *aquamarine.ConflictError
+		if errors.As(e.err, &ce) {
+			*t = (*ConflictError)(ce)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+// Package generator embeds the Aquamarine generator in other programs. It
+// renders a spec through a set of templates into an Output (a directory, an
+// in-memory map or an archive) and reports every failure as an error.
+package generator
+
+import (
+	"io/fs"
+
+	"github.com/aquamarinepk/aquamarine/assets"
+	"github.com/aquamarinepk/aquamarine/internal/aquamarine"
+)
+
+// Options controls a generate run.
+type Options struct {
+	Templates fs.FS    // defaults to Templates()
+	Output    Output   // required
+	Dir       string   // names the output in messages; anchors the dev replace path
+	Dev       bool     // build against a local aquamarine checkout instead of a release
+	Profile   string   // overlay this spec profile's runtime; its local setting implies Dev
+	Force     bool     // overwrite files that conflict with hand edits
+	Feats     []string // limit feat files to these feats; nil renders all
+	Plugins   []Plugin // contribute files after the templates
+}
+
+// Templates returns the templates aquamarine ships with.
+func Templates() fs.FS {
+	return assets.Templates()
+}
+
+// SpecTemplates layers the spec's templates section (a dir and packs, paths
+// relative to specDir) and then override, when not empty, over the built-in
+// templates. Use it as Options.Templates to generate like the CLI does. The
+// warnings name the templates in those dirs that override no built-in one.
+func SpecTemplates(spec *Config, specDir, override string) (fs.FS, []string, error) {
+	return aquamarine.SpecTemplates(Templates(), spec.cfg, specDir, override)
+}
+
+// Run runs the aquamarine command line with args (as in os.Args) and
+// plugins, for builds of the command that ship their own plugins. Spec hooks
+// run as with the stock command; the library functions never run them.
+func Run(args []string, plugins ...Plugin) error {
+	return aquamarine.NewApp(Templates(), generatorPlugins(plugins)...).Run(args)
+}
+
+// DryRun returns the changes Generate would make, without writing.
+func DryRun(spec *Config, opts Options) (*Plan, error) {
+	fg, err := newGenerator(spec, opts)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := fg.Plan()
+	if err != nil {
+		return nil, publicError(err)
+	}
+	return newPlan(plan), nil
+}
+
+// Generate renders spec into opts.Output and returns what it changed. Hand
+// edits are kept unless opts.Force is set: conflicting files get their new
+// content next to them and Generate returns a *ConflictError along with the
+// applied plan.
+func Generate(spec *Config, opts Options) (*Plan, error) {
+	plan, err := DryRun(spec, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := plan.Apply(); err != nil {
+		return nil, err
+	}
+	return plan, plan.Err()
+}
+
+func newGenerator(spec *Config, opts Options) (*aquamarine.FeatureGenerator, error) {
+	if opts.Output == nil {
+		return nil, ErrNoOutput
+	}
+	cfg, dev := spec.cfg, opts.Dev
+	if opts.Profile != "" {
+		profiled := *cfg
+		p, err := profiled.ApplyProfile(opts.Profile)
+		if err != nil {
+			return nil, err
+		}
+		cfg, dev = &profiled, dev || *p.Local
+	}
+	templates := opts.Templates
+	if templates == nil {
+		templates = Templates()
+	}
+	fg, err := aquamarine.NewFeatureGenerator(*cfg, opts.Dir, dev, templates)
+	if err != nil {
+		return nil, publicError(err)
+	}
+	fg.Output = opts.Output
+	fg.Force = opts.Force
+	fg.Scope = opts.Feats
+	fg.Plugins = generatorPlugins(opts.Plugins)
+	return fg, nil
+}
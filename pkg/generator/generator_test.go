@@ -0,0 +1,210 @@
+package generator
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const spec = `version: 0.2
+project: {name: notes, module: example.com/notes}
+runtime:
+  http:
+    api: {port: 8081}
+    web: {port: 8080}
+feats:
+  - name: notes
+    kind: atom
+    models:
+      Note:
+        fields:
+          title: {type: string, validations: [required]}
+`
+
+func parse(t *testing.T) *Config {
+	t.Helper()
+	return parseSpec(t, spec)
+}
+
+func parseSpec(t *testing.T, spec string) *Config {
+	t.Helper()
+	cfg, err := ParseSpec("aquamarine.yaml", []byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestGenerateMapOutput(t *testing.T) {
+	cfg := parse(t)
+	out := MapOutput{}
+	if _, err := Generate(cfg, Options{Output: out}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"go.mod", "main.go", "internal/feat/notes/note.go", ".aquamarine/manifest.json"} {
+		if _, ok := out[name]; !ok {
+			t.Errorf("%s not generated", name)
+		}
+	}
+
+	plan, err := Generate(cfg, Options{Output: out})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range plan.Changes {
+		if c.Action != FileUnchanged {
+			t.Errorf("regenerate: %s %s", c.Action, c.Path)
+		}
+	}
+
+	out["assets/config/config.yaml"] = []byte("# edited by hand\n")
+	_, err = Generate(parseSpec(t, strings.Replace(spec, "8081", "9091", 1)), Options{Output: out})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || len(conflict.Files) != 1 || conflict.Files[0] != "assets/config/config.yaml" {
+		t.Fatalf("hand edit: got %v, want a conflict on config.yaml", err)
+	}
+	if _, ok := out["assets/config/config.yaml.new"]; !ok {
+		t.Error("config.yaml.new not written")
+	}
+}
+
+func TestGenerateZipOutput(t *testing.T) {
+	var buf bytes.Buffer
+	out := NewZipOutput(&buf)
+	if _, err := Generate(parse(t), Options{Output: out}); err != nil {
+		t.Fatal(err)
+	}
+	if err := out.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := zr.Open("internal/feat/notes/feature.go"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	_, err := ParseSpec("aquamarine.yaml", []byte("version: 0.2\n"))
+	var diags Diagnostics
+	if !errors.As(err, &diags) || len(diags) == 0 {
+		t.Errorf("invalid spec: got %v, want Diagnostics", err)
+	}
+
+	if _, err := Generate(parse(t), Options{}); !errors.Is(err, ErrNoOutput) {
+		t.Errorf("no output: got %v, want ErrNoOutput", err)
+	}
+
+	templates := fstest.MapFS{"model.tmpl": {Data: []byte("{{.Broken")}}
+	_, err = Generate(parse(t), Options{Output: MapOutput{}, Templates: templates})
+	var te *TemplateError
+	if !errors.As(err, &te) || te.Template != "model.tmpl" {
+		t.Errorf("broken template: got %v, want a TemplateError for model.tmpl", err)
+	}
+
+	// A model template failing to render is reported along with the model.
+	templates = fstest.MapFS{}
+	err = fs.WalkDir(Templates(), ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		b, err := fs.ReadFile(Templates(), name)
+		templates[name] = &fstest.MapFile{Data: b}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	templates["model.tmpl"] = &fstest.MapFile{Data: []byte("{{.Missing}}")}
+	_, err = Generate(parse(t), Options{Output: MapOutput{}, Templates: templates})
+	te = nil
+	if !errors.As(err, &te) || te.Template != "model.tmpl" || !strings.Contains(err.Error(), "model Note") {
+		t.Errorf("failing template: got %v, want a TemplateError for model.tmpl", err)
+	}
+}
+
+// owners writes CODEOWNERS and a runbook per feat.
+type owners struct{ team string }
+
+func (o owners) Name() string { return "owners" }
+
+func (o owners) ProjectFiles(spec *Config) ([]File, error) {
+	if o.team == "" {
+		return nil, errors.New("no team")
+	}
+	return []File{{Path: "CODEOWNERS", Content: []byte("* " + o.team + "\n")}}, nil
+}
+
+func (o owners) FeatureFiles(spec *Config, feat string) ([]File, error) {
+	return []File{{Path: "docs/runbooks/" + feat + ".md", Content: []byte(fmt.Sprintf("# %s on-call\n", feat))}}, nil
+}
+
+// schema writes a doc per model, naming the project and its feats.
+type schema struct{}
+
+func (schema) Name() string { return "schema" }
+
+func (schema) ModelFiles(spec *Config, feat, model string) ([]File, error) {
+	doc := fmt.Sprintf("# %s\n\n%s (%s), feats %v, models %v\n", model, spec.Name(), spec.Module(), spec.Feats(), spec.Models(feat))
+	return []File{{Path: "docs/models/" + feat + "/" + model + ".md", Content: []byte(doc)}}, nil
+}
+
+// escape writes outside the output dir.
+type escape struct{}
+
+func (escape) Name() string { return "escape" }
+
+func (escape) ProjectFiles(spec *Config) ([]File, error) {
+	return []File{{Path: "../CODEOWNERS"}}, nil
+}
+
+func TestPlugins(t *testing.T) {
+	cfg := parse(t)
+	out := MapOutput{}
+	if _, err := Generate(cfg, Options{Output: out, Plugins: []Plugin{owners{team: "@acme/notes"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(out["CODEOWNERS"]); got != "* @acme/notes\n" {
+		t.Errorf("CODEOWNERS = %q", got)
+	}
+	if _, ok := out["docs/runbooks/notes.md"]; !ok {
+		t.Error("runbook not generated")
+	}
+
+	plan, err := Generate(cfg, Options{Output: out})
+	if err != nil {
+		t.Fatal(err)
+	}
+	removed := 0
+	for _, c := range plan.Changes {
+		if c.Action == FileRemoved {
+			removed++
+		}
+	}
+	if _, ok := out["CODEOWNERS"]; ok || removed != 2 {
+		t.Errorf("plugin dropped: %d files removed, want its 2 files", removed)
+	}
+
+	if _, err := Generate(cfg, Options{Output: out, Plugins: []Plugin{schema{}}}); err != nil {
+		t.Fatal(err)
+	}
+	want := "# Note\n\nnotes (example.com/notes), feats [notes], models [Note]\n"
+	if got := string(out["docs/models/notes/Note.md"]); got != want {
+		t.Errorf("model doc = %q, want %q", got, want)
+	}
+
+	for _, p := range []Plugin{owners{}, escape{}} {
+		_, err := Generate(cfg, Options{Output: MapOutput{}, Plugins: []Plugin{p}})
+		var pe *PluginError
+		if !errors.As(err, &pe) || pe.Plugin != p.Name() {
+			t.Errorf("%s: got %v, want a PluginError", p.Name(), err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"io"
+
+	"github.com/aquamarinepk/aquamarine/internal/aquamarine"
+)
+
+// Output is where a generate run reads the previous tree from and writes the
+// new one to. Names are slash separated and relative to the output root.
+type Output interface {
+	// ReadFile returns the content of name, or an error wrapping
+	// fs.ErrNotExist when there is none.
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte) error
+	Remove(name string) error
+}
+
+// DirOutput is an Output rooted at a directory on disk.
+type DirOutput string
+
+func (d DirOutput) ReadFile(name string) ([]byte, error) {
+	return aquamarine.DirOutput(d).ReadFile(name)
+}
+
+func (d DirOutput) WriteFile(name string, data []byte) error {
+	return aquamarine.DirOutput(d).WriteFile(name, data)
+}
+
+func (d DirOutput) Remove(name string) error {
+	return aquamarine.DirOutput(d).Remove(name)
+}
+
+// MapOutput is an in-memory Output. Seed it with a previous tree to plan a
+// regenerate against it.
+type MapOutput map[string][]byte
+
+func (m MapOutput) ReadFile(name string) ([]byte, error) {
+	return aquamarine.MapOutput(m).ReadFile(name)
+}
+
+func (m MapOutput) WriteFile(name string, data []byte) error {
+	return aquamarine.MapOutput(m).WriteFile(name, data)
+}
+
+func (m MapOutput) Remove(name string) error {
+	return aquamarine.MapOutput(m).Remove(name)
+}
+
+// ZipOutput writes a fresh tree into a zip archive. Nothing is read back, so
+// every file is created and removing fails. Close finishes the archive.
+type ZipOutput struct {
+	out *aquamarine.ZipOutput
+}
+
+// NewZipOutput returns an Output writing a fresh tree into a zip archive.
+// Close it to finish the archive.
+func NewZipOutput(w io.Writer) *ZipOutput {
+	return &ZipOutput{out: aquamarine.NewZipOutput(w)}
+}
+
+func (z *ZipOutput) ReadFile(name string) ([]byte, error) {
+	return z.out.ReadFile(name)
+}
+
+func (z *ZipOutput) WriteFile(name string, data []byte) error {
+	return z.out.WriteFile(name, data)
+}
+
+func (z *ZipOutput) Remove(name string) error {
+	return z.out.Remove(name)
+}
+
+func (z *ZipOutput) Close() error {
+	return z.out.Close()
+}
+
+// TarOutput writes a fresh tree into a tar stream, like ZipOutput. Close
+// finishes the stream.
+type TarOutput struct {
+	out *aquamarine.TarOutput
+}
+
+// NewTarOutput returns an Output writing a fresh tree into a tar stream.
+// Close it to finish the stream.
+func NewTarOutput(w io.Writer) *TarOutput {
+	return &TarOutput{out: aquamarine.NewTarOutput(w)}
+}
+
+func (t *TarOutput) ReadFile(name string) ([]byte, error) {
+	return t.out.ReadFile(name)
+}
+
+func (t *TarOutput) WriteFile(name string, data []byte) error {
+	return t.out.WriteFile(name, data)
+}
+
+func (t *TarOutput) Remove(name string) error {
+	return t.out.Remove(name)
+}
+
+func (t *TarOutput) Close() error {
+	return t.out.Close()
+}
@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"io"
+
+	"github.com/aquamarinepk/aquamarine/internal/aquamarine"
+)
+
+// FileAction is what a generate run does with a file.
+type FileAction string
+
+const (
+	FileCreated   FileAction = "created"
+	FileUpdated   FileAction = "updated"
+	FileUnchanged FileAction = "unchanged"
+	FileKept      FileAction = "kept"     // hand-edited, nothing new to apply
+	FileConflict  FileAction = "conflict" // hand-edited and the template output changed
+	FileRemoved   FileAction = "removed"
+	FileOrphaned  FileAction = "orphaned" // no longer generated, hand-edited, left in place
+)
+
+// Change is a planned operation on a single generated file.
+type Change struct {
+	Path   string // relative to the output root, slash separated
+	Action FileAction
+	Old    []byte // content in the output, nil when there is no file
+	New    []byte // content to write; for conflicts, the file written next to it
+	Forced bool   // a conflict or orphan overridden by Options.Force
+}
+
+// Plan is the set of changes a generate run makes, computed without writing
+// to the output. Apply carries out the changes as planned: editing Changes
+// does not change what it writes.
+type Plan struct {
+	Dir      string // Options.Dir, naming the output in messages
+	Changes  []Change
+	Warnings []string // things to look at in the planned files
+	plan     *aquamarine.Plan
+}
+
+func newPlan(p *aquamarine.Plan) *Plan {
+	plan := &Plan{Dir: p.Dir, Warnings: p.Warnings, plan: p}
+	for _, c := range p.Changes {
+		plan.Changes = append(plan.Changes, Change{Path: c.Path, Action: FileAction(c.Action), Old: c.Old, New: c.New, Forced: c.Forced})
+	}
+	return plan
+}
+
+// Conflicts counts the files left for the user to merge.
+func (p *Plan) Conflicts() int {
+	return p.plan.Conflicts()
+}
+
+// Apply performs the planned changes and saves the manifest.
+func (p *Plan) Apply() error {
+	return publicError(p.plan.Apply())
+}
+
+// Err returns a *ConflictError listing the conflicts of the plan, nil when
+// there are none.
+func (p *Plan) Err() error {
+	return publicError(p.plan.Err())
+}
+
+// WriteSummary lists the planned changes, one line per file that changes or
+// needs attention, then the warnings.
+func (p *Plan) WriteSummary(w io.Writer) error {
+	return p.plan.WriteSummary(w)
+}
+
+// WriteDiff writes a unified diff of every planned change against the
+// output. Conflicts diff the file against the content written next to it.
+func (p *Plan) WriteDiff(w io.Writer) error {
+	return p.plan.WriteDiff(w)
+}
@@ -0,0 +1,86 @@
+package generator
+
+import "github.com/aquamarinepk/aquamarine/internal/aquamarine"
+
+// File is a file contributed by a plugin. Path is relative to the output
+// root.
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// Plugin contributes files to every generate run, from the same parsed spec
+// the templates see. A plugin implements one or more of ProjectPlugin,
+// FeaturePlugin and ModelPlugin. Its files go through the manifest like
+// rendered ones: user regions and hand edits are kept, and files a plugin
+// stops producing are pruned.
+type Plugin interface {
+	Name() string
+}
+
+// ProjectPlugin contributes project-wide files, e.g. CODEOWNERS.
+type ProjectPlugin interface {
+	Plugin
+	ProjectFiles(spec *Config) ([]File, error)
+}
+
+// FeaturePlugin contributes files per feat, e.g. an on-call runbook.
+type FeaturePlugin interface {
+	Plugin
+	FeatureFiles(spec *Config, feat string) ([]File, error)
+}
+
+// ModelPlugin contributes files per model of a feat.
+type ModelPlugin interface {
+	Plugin
+	ModelFiles(spec *Config, feat, model string) ([]File, error)
+}
+
+// plugin runs a Plugin in the generator. The generator asks it for every
+// kind of file; those the Plugin does not implement are none.
+type plugin struct {
+	Plugin
+}
+
+func (p plugin) ProjectFiles(spec *aquamarine.Config) ([]aquamarine.File, error) {
+	pp, ok := p.Plugin.(ProjectPlugin)
+	if !ok {
+		return nil, nil
+	}
+	return pluginFiles(pp.ProjectFiles(&Config{cfg: spec}))
+}
+
+func (p plugin) FeatureFiles(spec *aquamarine.Config, feat string) ([]aquamarine.File, error) {
+	fp, ok := p.Plugin.(FeaturePlugin)
+	if !ok {
+		return nil, nil
+	}
+	return pluginFiles(fp.FeatureFiles(&Config{cfg: spec}, feat))
+}
+
+func (p plugin) ModelFiles(spec *aquamarine.Config, feat, model string) ([]aquamarine.File, error) {
+	mp, ok := p.Plugin.(ModelPlugin)
+	if !ok {
+		return nil, nil
+	}
+	return pluginFiles(mp.ModelFiles(&Config{cfg: spec}, feat, model))
+}
+
+func pluginFiles(files []File, err error) ([]aquamarine.File, error) {
+	if err != nil {
+		return nil, err
+	}
+	out := make([]aquamarine.File, len(files))
+	for i, f := range files {
+		out[i] = aquamarine.File(f)
+	}
+	return out, nil
+}
+
+func generatorPlugins(ps []Plugin) []aquamarine.Plugin {
+	out := make([]aquamarine.Plugin, len(ps))
+	for i, p := range ps {
+		out[i] = plugin{p}
+	}
+	return out
+}
@@ -0,0 +1,54 @@
+package generator
+
+import "github.com/aquamarinepk/aquamarine/internal/aquamarine"
+
+// SpecVersion is the spec format version ParseSpec reads without warnings;
+// older specs need aquamarine upgrade.
+const SpecVersion = aquamarine.SpecVersion
+
+// Config is a parsed aquamarine.yaml, from LoadSpec or ParseSpec. Plugins
+// read the parts of the spec they need through its methods.
+type Config struct {
+	cfg *aquamarine.Config
+}
+
+// Name returns the project name.
+func (c *Config) Name() string {
+	return c.cfg.Project.Name
+}
+
+// Module returns the Go module path of the generated app.
+func (c *Config) Module() string {
+	return c.cfg.Project.Module
+}
+
+// Feats returns the feats in the order the spec declares them.
+func (c *Config) Feats() []string {
+	return c.cfg.FeatNames()
+}
+
+// Models returns the models of feat in the order the spec declares them,
+// none for an unknown feat.
+func (c *Config) Models(feat string) []string {
+	return c.cfg.ModelNames(feat)
+}
+
+// LoadSpec reads and validates a spec file and the feat files it includes.
+// An invalid spec returns its Diagnostics as the error.
+func LoadSpec(path string) (*Config, error) {
+	cfg, _, err := aquamarine.LoadSpec(path)
+	if err != nil {
+		return nil, publicError(err)
+	}
+	return &Config{cfg: cfg}, nil
+}
+
+// ParseSpec validates spec content; file only labels diagnostics. A spec
+// with include globs needs LoadSpec.
+func ParseSpec(file string, data []byte) (*Config, error) {
+	cfg, diags := aquamarine.ParseSpec(file, data)
+	if diags.HasErrors() {
+		return nil, diagnostics(diags.Errors())
+	}
+	return &Config{cfg: cfg}, nil
+}
@@ -0,0 +1,54 @@
+package am
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var envNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ExpandEnv replaces ${VAR} in s with the value of the environment variable
+// VAR and ${VAR:-default} with default when VAR is unset or empty, as the
+// aquamarine spec does. $${ is a literal ${. An unset VAR without a default
+// is an error, so a missing secret fails the start instead of connecting
+// with an empty one.
+func ExpandEnv(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		if i > 0 && s[i-1] == '$' {
+			b.WriteString(s[:i-1] + "${")
+			s = s[i+2:]
+			continue
+		}
+		b.WriteString(s[:i])
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated ${ in %q", s)
+		}
+		ref := s[i+2 : i+end]
+		s = s[i+end+1:]
+
+		name, def, hasDef := strings.Cut(ref, ":-")
+		if !envNameRe.MatchString(name) {
+			return "", fmt.Errorf("invalid variable name %q in ${%s}", name, ref)
+		}
+		v, ok := os.LookupEnv(name)
+		switch {
+		case hasDef && v == "":
+			v = def
+		case !ok:
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		b.WriteString(v)
+	}
+}
@@ -0,0 +1,116 @@
+package am
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+const createMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	name TEXT PRIMARY KEY,
+	applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	checksum TEXT
+)`
+
+// MigrateSQL applies pending .sql migrations found under dir/<feat>/ for each
+// feat, in the given feat order and filename order within a feat. Applied
+// migrations are recorded in schema_migrations with a checksum of their
+// content and skipped on later runs; an applied migration whose content
+// changed since is an error, as the database no longer matches it.
+func MigrateSQL(ctx context.Context, db *sql.DB, fsys fs.FS, dir string, feats ...string) error {
+	return applySQL(ctx, db, fsys, dir, "", feats)
+}
+
+// SeedSQL applies pending .sql seeds found under dir/<feat>/ like MigrateSQL
+// applies migrations, after them. Seeds are recorded as seeds/<feat>/<file>
+// and, unlike migrations, may be edited once applied; prefer idempotent
+// statements (upserts by natural keys) all the same.
+func SeedSQL(ctx context.Context, db *sql.DB, fsys fs.FS, dir string, feats ...string) error {
+	return applySQL(ctx, db, fsys, dir, "seeds/", feats)
+}
+
+// applySQL applies the files of dir/<feat>/ once each, recorded under prefix.
+func applySQL(ctx context.Context, db *sql.DB, fsys fs.FS, dir, prefix string, feats []string) error {
+	if _, err := db.ExecContext(ctx, createMigrationsTable); err != nil {
+		return fmt.Errorf("cannot create schema_migrations: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, `SELECT checksum FROM schema_migrations LIMIT 0`); err != nil {
+		// Created before migrations were checksummed.
+		if _, err := db.ExecContext(ctx, `ALTER TABLE schema_migrations ADD COLUMN checksum TEXT`); err != nil {
+			return fmt.Errorf("cannot add checksum to schema_migrations: %w", err)
+		}
+	}
+	for _, feat := range feats {
+		featDir := path.Join(dir, feat)
+		entries, err := fs.ReadDir(fsys, featDir)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return fmt.Errorf("cannot read %s: %w", featDir, err)
+		}
+		var names []string
+		for _, e := range entries {
+			if !e.IsDir() && strings.HasSuffix(e.Name(), ".sql") {
+				names = append(names, e.Name())
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := applyMigration(ctx, db, fsys, path.Join(featDir, name), prefix+feat+"/"+name, prefix == ""); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyMigration applies file once, recorded as name. When it was applied
+// already and strict, its content must not have changed since; applied
+// before checksums were recorded, it gets the checksum of its content.
+func applyMigration(ctx context.Context, db *sql.DB, fsys fs.FS, file, name string, strict bool) error {
+	stmt, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return fmt.Errorf("cannot read migration %s: %w", name, err)
+	}
+	sum := sha256.Sum256(stmt)
+	checksum := hex.EncodeToString(sum[:])
+
+	var applied sql.NullString
+	err = db.QueryRowContext(ctx, `SELECT checksum FROM schema_migrations WHERE name = ?`, name).Scan(&applied)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return fmt.Errorf("cannot check migration %s: %w", name, err)
+	case !applied.Valid:
+		if _, err := db.ExecContext(ctx, `UPDATE schema_migrations SET checksum = ? WHERE name = ?`, checksum, name); err != nil {
+			return fmt.Errorf("cannot record checksum of migration %s: %w", name, err)
+		}
+		return nil
+	case strict && applied.String != checksum:
+		return fmt.Errorf("migration %s was changed after it was applied: "+
+			"restore it and add a new migration for the change", name)
+	default:
+		return nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, string(stmt)); err != nil {
+		return fmt.Errorf("migration %s failed: %w", name, err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (name, checksum) VALUES (?, ?)`, name, checksum); err != nil {
+		return fmt.Errorf("cannot record migration %s: %w", name, err)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,259 @@
+package am
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Ptr returns a pointer to v, for nullable fields set from a value.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+var decimalRe = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+
+// Decimal is an exact decimal number, such as an amount of money. It keeps
+// its text, so no digit is lost to floating point on the way to and from
+// storage; the zero Decimal is 0. JSON encodes it as a string.
+type Decimal string
+
+// ParseDecimal returns s as a Decimal, or an error when s is not a decimal
+// number.
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if !decimalRe.MatchString(s) {
+		return "", fmt.Errorf("invalid decimal %q", s)
+	}
+	return Decimal(s), nil
+}
+
+// String returns d as text.
+func (d Decimal) String() string {
+	if d == "" {
+		return "0"
+	}
+	return string(d)
+}
+
+// Rat returns d as an exact rational number, for arithmetic.
+func (d Decimal) Rat() *big.Rat {
+	r, ok := new(big.Rat).SetString(d.String())
+	if !ok {
+		return new(big.Rat)
+	}
+	return r
+}
+
+// Cmp compares d and e by value: -1 if d < e, 0 if equal, +1 if d > e.
+func (d Decimal) Cmp(e Decimal) int {
+	return d.Rat().Cmp(e.Rat())
+}
+
+// MarshalJSON encodes d as a JSON string.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON accepts a JSON string or number; null leaves d unchanged.
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+	}
+	v, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// Scan implements sql.Scanner.
+func (d *Decimal) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("cannot scan %T into Decimal", src)
+	}
+	v, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Date is a calendar day, with no time of day or zone, as YYYY-MM-DD. The
+// zero Date is no date and is stored as null.
+type Date string
+
+// ParseDate returns s as a Date, or an error when s is not a YYYY-MM-DD day.
+func ParseDate(s string) (Date, error) {
+	if _, err := time.Parse(time.DateOnly, s); err != nil {
+		return "", fmt.Errorf("invalid date %q (want YYYY-MM-DD)", s)
+	}
+	return Date(s), nil
+}
+
+// DateOf returns the day t falls on in its location.
+func DateOf(t time.Time) Date {
+	return Date(t.Format(time.DateOnly))
+}
+
+// Time returns the start of d in UTC, or the zero time for the zero Date.
+func (d Date) Time() time.Time {
+	t, _ := time.Parse(time.DateOnly, string(d))
+	return t
+}
+
+// UnmarshalJSON accepts a YYYY-MM-DD string, or an empty one for no date.
+func (d *Date) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		*d = ""
+		return nil
+	}
+	v, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// Scan implements sql.Scanner. Drivers may return DATE columns as text or
+// as a time.Time.
+func (d *Date) Scan(src any) error {
+	switch v := src.(type) {
+	case time.Time:
+		*d = DateOf(v)
+		return nil
+	case string:
+		return d.scanText(v)
+	case []byte:
+		return d.scanText(string(v))
+	default:
+		return fmt.Errorf("cannot scan %T into Date", src)
+	}
+}
+
+func (d *Date) scanText(s string) error {
+	if len(s) > len(time.DateOnly) {
+		s = s[:len(time.DateOnly)]
+	}
+	v, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (d Date) Value() (driver.Value, error) {
+	if d == "" {
+		return nil, nil
+	}
+	return string(d), nil
+}
+
+// JSON is a JSON document kept as its text. It is embedded as is in JSON
+// output; the zero JSON is null.
+type JSON string
+
+// MarshalJSON returns j itself.
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if j == "" {
+		return []byte("null"), nil
+	}
+	return []byte(j), nil
+}
+
+// UnmarshalJSON keeps the document b.
+func (j *JSON) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*j = ""
+		return nil
+	}
+	*j = JSON(b)
+	return nil
+}
+
+// Scan implements sql.Scanner.
+func (j *JSON) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		*j = JSON(v)
+	case []byte:
+		*j = JSON(v)
+	default:
+		return fmt.Errorf("cannot scan %T into JSON", src)
+	}
+	if !json.Valid([]byte(*j)) {
+		return fmt.Errorf("invalid JSON document")
+	}
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (j JSON) Value() (driver.Value, error) {
+	if j == "" {
+		return "null", nil
+	}
+	return string(j), nil
+}
+
+// List is a list field. SQL columns hold it as a JSON array; JSON and BSON
+// encode it as a plain array.
+type List[T any] []T
+
+// Scan implements sql.Scanner.
+func (l *List[T]) Scan(src any) error {
+	switch v := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	case []byte:
+		return json.Unmarshal(v, l)
+	default:
+		return fmt.Errorf("cannot scan %T into List", src)
+	}
+}
+
+// Value implements driver.Valuer.
+func (l List[T]) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}